@@ -2,6 +2,7 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"log"
 
 	"github.com/1kovalevskiy/sso/config"
@@ -20,7 +21,6 @@ func main() {
 	}
 	if migrationsPath == "" {
 		log.Fatalf("Set migrations-path")
-
 	}
 
 	cfg, err := config.NewConfig(configPath)
@@ -31,6 +31,11 @@ func main() {
 	storagePath := cfg.SQL.URL
 	migrationsTable := "migrations"
 
+	if cfg.SQL.Driver == config.DriverMemory {
+		fmt.Println("in-memory storage needs no migrations")
+
+		return
+	}
 
 	if storagePath == "" {
 		panic("storage-path is required")
@@ -39,5 +44,12 @@ func main() {
 		panic("migrations-path is required")
 	}
 
-	migrator.MigrateUp(migrationsPath, storagePath, migrationsTable)
+	switch cfg.SQL.Driver {
+	case config.DriverSQLite:
+		migrator.MigrateUp(migrationsPath, migrator.SQLiteURL(storagePath, migrationsTable))
+	case config.DriverPostgres:
+		migrator.MigrateUp(migrationsPath, migrator.PostgresURL(storagePath, migrationsTable))
+	default:
+		log.Fatalf("Unknown sql driver: %s", cfg.SQL.Driver)
+	}
 }
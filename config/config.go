@@ -10,9 +10,19 @@ import (
 
 type (
 	Config struct {
-		App  `yaml:"app"`
-		GRPC `yaml:"grpc"`
-		SQL  `yaml:"sql"`
+		App       `yaml:"app"`
+		GRPC      `yaml:"grpc"`
+		HTTP      `yaml:"http"`
+		SQL       `yaml:"sql"`
+		Token     `yaml:"token"`
+		AdminAPI  `yaml:"admin_api"`
+		Lockout   `yaml:"lockout"`
+		RateLimit `yaml:"rate_limit"`
+		Password  `yaml:"password"`
+		Email     `yaml:"email"`
+		Notifier  `yaml:"notifier"`
+		MFA       `yaml:"mfa"`
+		OIDC      `yaml:"oidc"`
 	}
 
 	App struct {
@@ -24,10 +34,123 @@ type (
 		Port int `env-required:"true" yaml:"port" env:"GRPC_PORT"`
 	}
 
+	HTTP struct {
+		Port int `env-required:"true" yaml:"port" env:"HTTP_PORT"`
+	}
+
+	// SQL.Driver selects the storage backend: sqlite3, postgres or memory.
+	// The memory backend loses all data on restart. JournalMode, BusyTimeout
+	// and MaxOpenConns apply to sqlite3 only. Timeout bounds every query of the
+	// sqlite3 and postgres backends, queries slower than SlowQuery are logged.
 	SQL struct {
-		Timeout string `env-required:"true" yaml:"timeout" env:"SQL_TIMEOUT"`
-		URL     string `env:"SQL_URL"`
+		Driver       string `env-default:"sqlite3" yaml:"driver"         env:"SQL_DRIVER"`
+		Timeout      string `env-required:"true"   yaml:"timeout"        env:"SQL_TIMEOUT"`
+		URL          string `env:"SQL_URL"`
+		JournalMode  string `env-default:"WAL"     yaml:"journal_mode"   env:"SQL_JOURNAL_MODE"`
+		BusyTimeout  string `env-default:"5s"      yaml:"busy_timeout"   env:"SQL_BUSY_TIMEOUT"`
+		MaxOpenConns int    `env-default:"4"       yaml:"max_open_conns" env:"SQL_MAX_OPEN_CONNS"`
+		SlowQuery    string `env-default:"200ms"   yaml:"slow_query"     env:"SQL_SLOW_QUERY"`
+	}
+
+	Token struct {
+		RefreshTTL    string `env-default:"720h" yaml:"refresh_ttl"    env:"TOKEN_REFRESH_TTL"`
+		KeyOverlap    string `env-default:"24h"  yaml:"key_overlap"    env:"TOKEN_KEY_OVERLAP"`
+		SweepInterval string `env-default:"1h"   yaml:"sweep_interval" env:"TOKEN_SWEEP_INTERVAL"`
+	}
+
+	// AdminAPI serves app management on its own port. Callers authenticate
+	// with APIKey in the x-api-key metadata, with a client certificate signed
+	// by ClientCA, or both when both are set. The server is not started when
+	// neither is configured.
+	AdminAPI struct {
+		Port     int    `env-default:"9001" yaml:"port"      env:"ADMIN_API_PORT"`
+		APIKey   string `yaml:"api_key"   env:"ADMIN_API_KEY"`
+		TLSCert  string `yaml:"tls_cert"  env:"ADMIN_API_TLS_CERT"`
+		TLSKey   string `yaml:"tls_key"   env:"ADMIN_API_TLS_KEY"`
+		ClientCA string `yaml:"client_ca" env:"ADMIN_API_CLIENT_CA"`
+	}
+
+	Lockout struct {
+		MaxFailures   int    `env-default:"5"   yaml:"max_failures"    env:"LOCKOUT_MAX_FAILURES"`
+		MaxIPFailures int    `env-default:"100" yaml:"max_ip_failures" env:"LOCKOUT_MAX_IP_FAILURES"`
+		Window        string `env-default:"15m" yaml:"window"          env:"LOCKOUT_WINDOW"`
+		Duration      string `env-default:"15m" yaml:"duration"        env:"LOCKOUT_DURATION"`
+		DelayAfter    int    `env-default:"3"   yaml:"delay_after"     env:"LOCKOUT_DELAY_AFTER"`
+		BaseDelay     string `env-default:"1s"  yaml:"base_delay"      env:"LOCKOUT_BASE_DELAY"`
+		MaxDelay      string `env-default:"30s" yaml:"max_delay"       env:"LOCKOUT_MAX_DELAY"`
+	}
+
+	// Password.MaxLength is counted in bytes and capped at the 72 bytes bcrypt
+	// can hash. DenylistPath points to a file of refused passwords, one per
+	// line. Hasher is argon2id or bcrypt, Argon2Memory is counted in KiB.
+	Password struct {
+		ResetTTL      string `env-default:"1h"    yaml:"reset_ttl"      env:"PASSWORD_RESET_TTL"`
+		MinLength     int    `env-default:"8"     yaml:"min_length"     env:"PASSWORD_MIN_LENGTH"`
+		MaxLength     int    `env-default:"72"    yaml:"max_length"     env:"PASSWORD_MAX_LENGTH"`
+		RequireLower  bool   `env-default:"false" yaml:"require_lower"  env:"PASSWORD_REQUIRE_LOWER"`
+		RequireUpper  bool   `env-default:"false" yaml:"require_upper"  env:"PASSWORD_REQUIRE_UPPER"`
+		RequireDigit  bool   `env-default:"false" yaml:"require_digit"  env:"PASSWORD_REQUIRE_DIGIT"`
+		RequireSymbol bool   `env-default:"false" yaml:"require_symbol" env:"PASSWORD_REQUIRE_SYMBOL"`
+		DenylistPath  string `yaml:"denylist_path" env:"PASSWORD_DENYLIST_PATH"`
+
+		Hasher            string `env-default:"argon2id" yaml:"hasher"             env:"PASSWORD_HASHER"`
+		Argon2Memory      uint32 `env-default:"19456"    yaml:"argon2_memory"      env:"PASSWORD_ARGON2_MEMORY"`
+		Argon2Iterations  uint32 `env-default:"2"        yaml:"argon2_iterations"  env:"PASSWORD_ARGON2_ITERATIONS"`
+		Argon2Parallelism uint8  `env-default:"1"        yaml:"argon2_parallelism" env:"PASSWORD_ARGON2_PARALLELISM"`
+		BcryptCost        int    `env-default:"10"       yaml:"bcrypt_cost"        env:"PASSWORD_BCRYPT_COST"`
 	}
+
+	Email struct {
+		VerificationTTL string `env-default:"24h" yaml:"verification_ttl" env:"EMAIL_VERIFICATION_TTL"`
+	}
+
+	// MFA.Issuer names the service in authenticator apps. ChallengeTTL bounds
+	// the time between the password step of a login and its TOTP step.
+	MFA struct {
+		Issuer       string `env-default:"sso" yaml:"issuer"        env:"MFA_ISSUER"`
+		ChallengeTTL string `env-default:"5m"  yaml:"challenge_ttl" env:"MFA_CHALLENGE_TTL"`
+	}
+
+	// OIDC.Issuer is the public base URL of the HTTP server, used as the iss
+	// claim of ID tokens and in the discovery document. CodeTTL bounds the
+	// time between a login and the exchange of its authorization code.
+	OIDC struct {
+		Issuer  string `env-default:"http://localhost:8080" yaml:"issuer"   env:"OIDC_ISSUER"`
+		CodeTTL string `env-default:"1m"                    yaml:"code_ttl" env:"OIDC_CODE_TTL"`
+	}
+
+	// Notifier delivers password reset and email verification tokens to users. Type is log, which
+	// writes them to the service log, or file, which appends them as JSON
+	// lines to Path. Both are meant for local development.
+	Notifier struct {
+		Type string `env-default:"log" yaml:"type" env:"NOTIFIER_TYPE"`
+		Path string `yaml:"path" env:"NOTIFIER_PATH"`
+	}
+
+	RateLimit struct {
+		Methods []RateLimitMethod `yaml:"methods"`
+	}
+
+	// RateLimitMethod limits calls of a full gRPC method name, e.g. /auth.Auth/Login.
+	// Key is one of peer, app or peer_app.
+	RateLimitMethod struct {
+		Method string  `yaml:"method"`
+		Rate   float64 `yaml:"rate"`
+		Burst  int     `yaml:"burst"`
+		Key    string  `yaml:"key"`
+	}
+)
+
+const (
+	DriverSQLite   = "sqlite3"
+	DriverPostgres = "postgres"
+	DriverMemory   = "memory"
+
+	NotifierLog  = "log"
+	NotifierFile = "file"
+
+	HasherArgon2id = "argon2id"
+	HasherBcrypt   = "bcrypt"
 )
 
 func init() {
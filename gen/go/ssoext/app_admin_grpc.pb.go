@@ -0,0 +1,468 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.23.4
+// source: ssoext/app_admin.proto
+
+package ssoextv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	AppAdmin_CreateApp_FullMethodName          = "/auth.ext.AppAdmin/CreateApp"
+	AppAdmin_UpdateApp_FullMethodName          = "/auth.ext.AppAdmin/UpdateApp"
+	AppAdmin_DeleteApp_FullMethodName          = "/auth.ext.AppAdmin/DeleteApp"
+	AppAdmin_SetAppDisabled_FullMethodName     = "/auth.ext.AppAdmin/SetAppDisabled"
+	AppAdmin_SetAppEmailPolicy_FullMethodName  = "/auth.ext.AppAdmin/SetAppEmailPolicy"
+	AppAdmin_SetAppRedirectURIs_FullMethodName = "/auth.ext.AppAdmin/SetAppRedirectURIs"
+	AppAdmin_SetAppScopes_FullMethodName       = "/auth.ext.AppAdmin/SetAppScopes"
+	AppAdmin_SetAppClaims_FullMethodName       = "/auth.ext.AppAdmin/SetAppClaims"
+	AppAdmin_ListApps_FullMethodName           = "/auth.ext.AppAdmin/ListApps"
+	AppAdmin_GetApp_FullMethodName             = "/auth.ext.AppAdmin/GetApp"
+)
+
+// AppAdminClient is the client API for AppAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AppAdminClient interface {
+	CreateApp(ctx context.Context, in *CreateAppRequest, opts ...grpc.CallOption) (*CreateAppResponse, error)
+	UpdateApp(ctx context.Context, in *UpdateAppRequest, opts ...grpc.CallOption) (*UpdateAppResponse, error)
+	// DeleteApp removes the app with its signing keys, refresh tokens, roles
+	// and revoked tokens. Auth events are kept.
+	DeleteApp(ctx context.Context, in *DeleteAppRequest, opts ...grpc.CallOption) (*DeleteAppResponse, error)
+	// SetAppDisabled rejects logins and token refreshes of the app while it
+	// is disabled.
+	SetAppDisabled(ctx context.Context, in *SetAppDisabledRequest, opts ...grpc.CallOption) (*SetAppDisabledResponse, error)
+	// SetAppEmailPolicy makes logins to the app refuse users whose email is
+	// not verified.
+	SetAppEmailPolicy(ctx context.Context, in *SetAppEmailPolicyRequest, opts ...grpc.CallOption) (*SetAppEmailPolicyResponse, error)
+	// SetAppRedirectURIs replaces the uris the app may receive OAuth
+	// authorization codes at. They are matched exactly.
+	SetAppRedirectURIs(ctx context.Context, in *SetAppRedirectURIsRequest, opts ...grpc.CallOption) (*SetAppRedirectURIsResponse, error)
+	// SetAppScopes replaces the scopes the app may request for its own
+	// tokens by the client credentials grant.
+	SetAppScopes(ctx context.Context, in *SetAppScopesRequest, opts ...grpc.CallOption) (*SetAppScopesResponse, error)
+	// SetAppClaims replaces the custom claims added to access tokens of the
+	// app. Templates may reference user attributes as {{key}}; a claim is
+	// left out if an attribute it references is not set.
+	SetAppClaims(ctx context.Context, in *SetAppClaimsRequest, opts ...grpc.CallOption) (*SetAppClaimsResponse, error)
+	ListApps(ctx context.Context, in *ListAppsRequest, opts ...grpc.CallOption) (*ListAppsResponse, error)
+	GetApp(ctx context.Context, in *GetAppRequest, opts ...grpc.CallOption) (*GetAppResponse, error)
+}
+
+type appAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAppAdminClient(cc grpc.ClientConnInterface) AppAdminClient {
+	return &appAdminClient{cc}
+}
+
+func (c *appAdminClient) CreateApp(ctx context.Context, in *CreateAppRequest, opts ...grpc.CallOption) (*CreateAppResponse, error) {
+	out := new(CreateAppResponse)
+	err := c.cc.Invoke(ctx, AppAdmin_CreateApp_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appAdminClient) UpdateApp(ctx context.Context, in *UpdateAppRequest, opts ...grpc.CallOption) (*UpdateAppResponse, error) {
+	out := new(UpdateAppResponse)
+	err := c.cc.Invoke(ctx, AppAdmin_UpdateApp_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appAdminClient) DeleteApp(ctx context.Context, in *DeleteAppRequest, opts ...grpc.CallOption) (*DeleteAppResponse, error) {
+	out := new(DeleteAppResponse)
+	err := c.cc.Invoke(ctx, AppAdmin_DeleteApp_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appAdminClient) SetAppDisabled(ctx context.Context, in *SetAppDisabledRequest, opts ...grpc.CallOption) (*SetAppDisabledResponse, error) {
+	out := new(SetAppDisabledResponse)
+	err := c.cc.Invoke(ctx, AppAdmin_SetAppDisabled_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appAdminClient) SetAppEmailPolicy(ctx context.Context, in *SetAppEmailPolicyRequest, opts ...grpc.CallOption) (*SetAppEmailPolicyResponse, error) {
+	out := new(SetAppEmailPolicyResponse)
+	err := c.cc.Invoke(ctx, AppAdmin_SetAppEmailPolicy_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appAdminClient) SetAppRedirectURIs(ctx context.Context, in *SetAppRedirectURIsRequest, opts ...grpc.CallOption) (*SetAppRedirectURIsResponse, error) {
+	out := new(SetAppRedirectURIsResponse)
+	err := c.cc.Invoke(ctx, AppAdmin_SetAppRedirectURIs_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appAdminClient) SetAppScopes(ctx context.Context, in *SetAppScopesRequest, opts ...grpc.CallOption) (*SetAppScopesResponse, error) {
+	out := new(SetAppScopesResponse)
+	err := c.cc.Invoke(ctx, AppAdmin_SetAppScopes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appAdminClient) SetAppClaims(ctx context.Context, in *SetAppClaimsRequest, opts ...grpc.CallOption) (*SetAppClaimsResponse, error) {
+	out := new(SetAppClaimsResponse)
+	err := c.cc.Invoke(ctx, AppAdmin_SetAppClaims_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appAdminClient) ListApps(ctx context.Context, in *ListAppsRequest, opts ...grpc.CallOption) (*ListAppsResponse, error) {
+	out := new(ListAppsResponse)
+	err := c.cc.Invoke(ctx, AppAdmin_ListApps_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appAdminClient) GetApp(ctx context.Context, in *GetAppRequest, opts ...grpc.CallOption) (*GetAppResponse, error) {
+	out := new(GetAppResponse)
+	err := c.cc.Invoke(ctx, AppAdmin_GetApp_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AppAdminServer is the server API for AppAdmin service.
+// All implementations must embed UnimplementedAppAdminServer
+// for forward compatibility
+type AppAdminServer interface {
+	CreateApp(context.Context, *CreateAppRequest) (*CreateAppResponse, error)
+	UpdateApp(context.Context, *UpdateAppRequest) (*UpdateAppResponse, error)
+	// DeleteApp removes the app with its signing keys, refresh tokens, roles
+	// and revoked tokens. Auth events are kept.
+	DeleteApp(context.Context, *DeleteAppRequest) (*DeleteAppResponse, error)
+	// SetAppDisabled rejects logins and token refreshes of the app while it
+	// is disabled.
+	SetAppDisabled(context.Context, *SetAppDisabledRequest) (*SetAppDisabledResponse, error)
+	// SetAppEmailPolicy makes logins to the app refuse users whose email is
+	// not verified.
+	SetAppEmailPolicy(context.Context, *SetAppEmailPolicyRequest) (*SetAppEmailPolicyResponse, error)
+	// SetAppRedirectURIs replaces the uris the app may receive OAuth
+	// authorization codes at. They are matched exactly.
+	SetAppRedirectURIs(context.Context, *SetAppRedirectURIsRequest) (*SetAppRedirectURIsResponse, error)
+	// SetAppScopes replaces the scopes the app may request for its own
+	// tokens by the client credentials grant.
+	SetAppScopes(context.Context, *SetAppScopesRequest) (*SetAppScopesResponse, error)
+	// SetAppClaims replaces the custom claims added to access tokens of the
+	// app. Templates may reference user attributes as {{key}}; a claim is
+	// left out if an attribute it references is not set.
+	SetAppClaims(context.Context, *SetAppClaimsRequest) (*SetAppClaimsResponse, error)
+	ListApps(context.Context, *ListAppsRequest) (*ListAppsResponse, error)
+	GetApp(context.Context, *GetAppRequest) (*GetAppResponse, error)
+	mustEmbedUnimplementedAppAdminServer()
+}
+
+// UnimplementedAppAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedAppAdminServer struct {
+}
+
+func (UnimplementedAppAdminServer) CreateApp(context.Context, *CreateAppRequest) (*CreateAppResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateApp not implemented")
+}
+func (UnimplementedAppAdminServer) UpdateApp(context.Context, *UpdateAppRequest) (*UpdateAppResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateApp not implemented")
+}
+func (UnimplementedAppAdminServer) DeleteApp(context.Context, *DeleteAppRequest) (*DeleteAppResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteApp not implemented")
+}
+func (UnimplementedAppAdminServer) SetAppDisabled(context.Context, *SetAppDisabledRequest) (*SetAppDisabledResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAppDisabled not implemented")
+}
+func (UnimplementedAppAdminServer) SetAppEmailPolicy(context.Context, *SetAppEmailPolicyRequest) (*SetAppEmailPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAppEmailPolicy not implemented")
+}
+func (UnimplementedAppAdminServer) SetAppRedirectURIs(context.Context, *SetAppRedirectURIsRequest) (*SetAppRedirectURIsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAppRedirectURIs not implemented")
+}
+func (UnimplementedAppAdminServer) SetAppScopes(context.Context, *SetAppScopesRequest) (*SetAppScopesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAppScopes not implemented")
+}
+func (UnimplementedAppAdminServer) SetAppClaims(context.Context, *SetAppClaimsRequest) (*SetAppClaimsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAppClaims not implemented")
+}
+func (UnimplementedAppAdminServer) ListApps(context.Context, *ListAppsRequest) (*ListAppsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListApps not implemented")
+}
+func (UnimplementedAppAdminServer) GetApp(context.Context, *GetAppRequest) (*GetAppResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetApp not implemented")
+}
+func (UnimplementedAppAdminServer) mustEmbedUnimplementedAppAdminServer() {}
+
+// UnsafeAppAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AppAdminServer will
+// result in compilation errors.
+type UnsafeAppAdminServer interface {
+	mustEmbedUnimplementedAppAdminServer()
+}
+
+func RegisterAppAdminServer(s grpc.ServiceRegistrar, srv AppAdminServer) {
+	s.RegisterService(&AppAdmin_ServiceDesc, srv)
+}
+
+func _AppAdmin_CreateApp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAppRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppAdminServer).CreateApp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AppAdmin_CreateApp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppAdminServer).CreateApp(ctx, req.(*CreateAppRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppAdmin_UpdateApp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAppRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppAdminServer).UpdateApp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AppAdmin_UpdateApp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppAdminServer).UpdateApp(ctx, req.(*UpdateAppRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppAdmin_DeleteApp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAppRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppAdminServer).DeleteApp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AppAdmin_DeleteApp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppAdminServer).DeleteApp(ctx, req.(*DeleteAppRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppAdmin_SetAppDisabled_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAppDisabledRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppAdminServer).SetAppDisabled(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AppAdmin_SetAppDisabled_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppAdminServer).SetAppDisabled(ctx, req.(*SetAppDisabledRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppAdmin_SetAppEmailPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAppEmailPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppAdminServer).SetAppEmailPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AppAdmin_SetAppEmailPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppAdminServer).SetAppEmailPolicy(ctx, req.(*SetAppEmailPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppAdmin_SetAppRedirectURIs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAppRedirectURIsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppAdminServer).SetAppRedirectURIs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AppAdmin_SetAppRedirectURIs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppAdminServer).SetAppRedirectURIs(ctx, req.(*SetAppRedirectURIsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppAdmin_SetAppScopes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAppScopesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppAdminServer).SetAppScopes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AppAdmin_SetAppScopes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppAdminServer).SetAppScopes(ctx, req.(*SetAppScopesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppAdmin_SetAppClaims_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAppClaimsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppAdminServer).SetAppClaims(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AppAdmin_SetAppClaims_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppAdminServer).SetAppClaims(ctx, req.(*SetAppClaimsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppAdmin_ListApps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAppsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppAdminServer).ListApps(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AppAdmin_ListApps_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppAdminServer).ListApps(ctx, req.(*ListAppsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppAdmin_GetApp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAppRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppAdminServer).GetApp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AppAdmin_GetApp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppAdminServer).GetApp(ctx, req.(*GetAppRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AppAdmin_ServiceDesc is the grpc.ServiceDesc for AppAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AppAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.ext.AppAdmin",
+	HandlerType: (*AppAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateApp",
+			Handler:    _AppAdmin_CreateApp_Handler,
+		},
+		{
+			MethodName: "UpdateApp",
+			Handler:    _AppAdmin_UpdateApp_Handler,
+		},
+		{
+			MethodName: "DeleteApp",
+			Handler:    _AppAdmin_DeleteApp_Handler,
+		},
+		{
+			MethodName: "SetAppDisabled",
+			Handler:    _AppAdmin_SetAppDisabled_Handler,
+		},
+		{
+			MethodName: "SetAppEmailPolicy",
+			Handler:    _AppAdmin_SetAppEmailPolicy_Handler,
+		},
+		{
+			MethodName: "SetAppRedirectURIs",
+			Handler:    _AppAdmin_SetAppRedirectURIs_Handler,
+		},
+		{
+			MethodName: "SetAppScopes",
+			Handler:    _AppAdmin_SetAppScopes_Handler,
+		},
+		{
+			MethodName: "SetAppClaims",
+			Handler:    _AppAdmin_SetAppClaims_Handler,
+		},
+		{
+			MethodName: "ListApps",
+			Handler:    _AppAdmin_ListApps_Handler,
+		},
+		{
+			MethodName: "GetApp",
+			Handler:    _AppAdmin_GetApp_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "ssoext/app_admin.proto",
+}
@@ -0,0 +1,226 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.23.4
+// source: ssoext/user_admin.proto
+
+package ssoextv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	UserAdmin_SetUserAdmin_FullMethodName        = "/auth.ext.UserAdmin/SetUserAdmin"
+	UserAdmin_SetUserAttribute_FullMethodName    = "/auth.ext.UserAdmin/SetUserAttribute"
+	UserAdmin_DeleteUserAttribute_FullMethodName = "/auth.ext.UserAdmin/DeleteUserAttribute"
+	UserAdmin_GetUserAttributes_FullMethodName   = "/auth.ext.UserAdmin/GetUserAttributes"
+)
+
+// UserAdminClient is the client API for UserAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type UserAdminClient interface {
+	// SetUserAdmin grants or revokes admin rights. It is the only way to make
+	// a user an admin.
+	SetUserAdmin(ctx context.Context, in *SetUserAdminRequest, opts ...grpc.CallOption) (*SetUserAdminResponse, error)
+	SetUserAttribute(ctx context.Context, in *SetUserAttributeRequest, opts ...grpc.CallOption) (*SetUserAttributeResponse, error)
+	// DeleteUserAttribute succeeds if the attribute is not set.
+	DeleteUserAttribute(ctx context.Context, in *DeleteUserAttributeRequest, opts ...grpc.CallOption) (*DeleteUserAttributeResponse, error)
+	GetUserAttributes(ctx context.Context, in *GetUserAttributesRequest, opts ...grpc.CallOption) (*GetUserAttributesResponse, error)
+}
+
+type userAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserAdminClient(cc grpc.ClientConnInterface) UserAdminClient {
+	return &userAdminClient{cc}
+}
+
+func (c *userAdminClient) SetUserAdmin(ctx context.Context, in *SetUserAdminRequest, opts ...grpc.CallOption) (*SetUserAdminResponse, error) {
+	out := new(SetUserAdminResponse)
+	err := c.cc.Invoke(ctx, UserAdmin_SetUserAdmin_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAdminClient) SetUserAttribute(ctx context.Context, in *SetUserAttributeRequest, opts ...grpc.CallOption) (*SetUserAttributeResponse, error) {
+	out := new(SetUserAttributeResponse)
+	err := c.cc.Invoke(ctx, UserAdmin_SetUserAttribute_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAdminClient) DeleteUserAttribute(ctx context.Context, in *DeleteUserAttributeRequest, opts ...grpc.CallOption) (*DeleteUserAttributeResponse, error) {
+	out := new(DeleteUserAttributeResponse)
+	err := c.cc.Invoke(ctx, UserAdmin_DeleteUserAttribute_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAdminClient) GetUserAttributes(ctx context.Context, in *GetUserAttributesRequest, opts ...grpc.CallOption) (*GetUserAttributesResponse, error) {
+	out := new(GetUserAttributesResponse)
+	err := c.cc.Invoke(ctx, UserAdmin_GetUserAttributes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserAdminServer is the server API for UserAdmin service.
+// All implementations must embed UnimplementedUserAdminServer
+// for forward compatibility
+type UserAdminServer interface {
+	// SetUserAdmin grants or revokes admin rights. It is the only way to make
+	// a user an admin.
+	SetUserAdmin(context.Context, *SetUserAdminRequest) (*SetUserAdminResponse, error)
+	SetUserAttribute(context.Context, *SetUserAttributeRequest) (*SetUserAttributeResponse, error)
+	// DeleteUserAttribute succeeds if the attribute is not set.
+	DeleteUserAttribute(context.Context, *DeleteUserAttributeRequest) (*DeleteUserAttributeResponse, error)
+	GetUserAttributes(context.Context, *GetUserAttributesRequest) (*GetUserAttributesResponse, error)
+	mustEmbedUnimplementedUserAdminServer()
+}
+
+// UnimplementedUserAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedUserAdminServer struct {
+}
+
+func (UnimplementedUserAdminServer) SetUserAdmin(context.Context, *SetUserAdminRequest) (*SetUserAdminResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserAdmin not implemented")
+}
+func (UnimplementedUserAdminServer) SetUserAttribute(context.Context, *SetUserAttributeRequest) (*SetUserAttributeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserAttribute not implemented")
+}
+func (UnimplementedUserAdminServer) DeleteUserAttribute(context.Context, *DeleteUserAttributeRequest) (*DeleteUserAttributeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUserAttribute not implemented")
+}
+func (UnimplementedUserAdminServer) GetUserAttributes(context.Context, *GetUserAttributesRequest) (*GetUserAttributesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserAttributes not implemented")
+}
+func (UnimplementedUserAdminServer) mustEmbedUnimplementedUserAdminServer() {}
+
+// UnsafeUserAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserAdminServer will
+// result in compilation errors.
+type UnsafeUserAdminServer interface {
+	mustEmbedUnimplementedUserAdminServer()
+}
+
+func RegisterUserAdminServer(s grpc.ServiceRegistrar, srv UserAdminServer) {
+	s.RegisterService(&UserAdmin_ServiceDesc, srv)
+}
+
+func _UserAdmin_SetUserAdmin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUserAdminRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAdminServer).SetUserAdmin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAdmin_SetUserAdmin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAdminServer).SetUserAdmin(ctx, req.(*SetUserAdminRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAdmin_SetUserAttribute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUserAttributeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAdminServer).SetUserAttribute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAdmin_SetUserAttribute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAdminServer).SetUserAttribute(ctx, req.(*SetUserAttributeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAdmin_DeleteUserAttribute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserAttributeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAdminServer).DeleteUserAttribute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAdmin_DeleteUserAttribute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAdminServer).DeleteUserAttribute(ctx, req.(*DeleteUserAttributeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAdmin_GetUserAttributes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserAttributesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAdminServer).GetUserAttributes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAdmin_GetUserAttributes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAdminServer).GetUserAttributes(ctx, req.(*GetUserAttributesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserAdmin_ServiceDesc is the grpc.ServiceDesc for UserAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.ext.UserAdmin",
+	HandlerType: (*UserAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetUserAdmin",
+			Handler:    _UserAdmin_SetUserAdmin_Handler,
+		},
+		{
+			MethodName: "SetUserAttribute",
+			Handler:    _UserAdmin_SetUserAttribute_Handler,
+		},
+		{
+			MethodName: "DeleteUserAttribute",
+			Handler:    _UserAdmin_DeleteUserAttribute_Handler,
+		},
+		{
+			MethodName: "GetUserAttributes",
+			Handler:    _UserAdmin_GetUserAttributes_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "ssoext/user_admin.proto",
+}
@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/1kovalevskiy/sso/integration-test/suite"
+
+	ssov1 "github.com/1kovalevskiy/proto_sso/gen/go/sso"
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ListAllApps walks every page of ListApps.
+func ListAllApps(t *testing.T, ctx context.Context, st *suite.Suite) []*ssoextv1.AppInfo {
+	var apps []*ssoextv1.AppInfo
+
+	req := &ssoextv1.ListAppsRequest{PageSize: 2}
+	for {
+		resp, err := st.AdminClient.ListApps(ctx, req)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(resp.GetApps()), 2)
+
+		apps = append(apps, resp.GetApps()...)
+		if resp.GetNextPageToken() == "" {
+			return apps
+		}
+		req.PageToken = resp.GetNextPageToken()
+	}
+}
+
+func TestAppAdmin_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	name := gofakeit.UUID()
+	respCreate, err := st.AdminClient.CreateApp(ctx, &ssoextv1.CreateAppRequest{
+		Name:     name,
+		Password: appPassword,
+		Secret:   appSecret,
+		TtlHour:  appTTL,
+	})
+	require.NoError(t, err)
+	appID := respCreate.GetAppId()
+
+	respGet, err := st.AdminClient.GetApp(ctx, &ssoextv1.GetAppRequest{AppId: appID})
+	require.NoError(t, err)
+	assert.Equal(t, name, respGet.GetApp().GetName())
+	assert.Equal(t, int32(appTTL), respGet.GetApp().GetTtlHour())
+	assert.Equal(t, ssoextv1.SigningAlgorithm_SIGNING_ALGORITHM_HS256, respGet.GetApp().GetSigningAlgorithm())
+
+	respUpdate, err := st.AdminClient.UpdateApp(ctx, &ssoextv1.UpdateAppRequest{
+		AppId:    appID,
+		Password: "new-app-password",
+		Secret:   "new-secret",
+		TtlHour:  2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), respUpdate.GetApp().GetTtlHour())
+
+	respAdd, err := st.AuthClient.AddApp(ctx, &ssov1.AddAppRequest{
+		Name:     name,
+		Password: "new-app-password",
+		Secret:   "new-secret",
+		TtlHour:  2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, appID, respAdd.GetAppId())
+
+	var listed bool
+	for _, app := range ListAllApps(t, ctx, st) {
+		listed = listed || app.GetId() == appID
+	}
+	assert.True(t, listed)
+
+	_, _, token := RegisterLogin(t, ctx, st, appID)
+
+	_, err = st.AdminClient.DeleteApp(ctx, &ssoextv1.DeleteAppRequest{AppId: appID})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.GetApp(ctx, &ssoextv1.GetAppRequest{AppId: appID})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	respValidate, err := st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{Token: token})
+	require.NoError(t, err)
+	assert.False(t, respValidate.GetValid())
+}
+
+func TestAppAdmin_CreateDuplicate(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	name := gofakeit.UUID()
+	AddNamedApp(t, ctx, st, name)
+
+	_, err := st.AdminClient.CreateApp(ctx, &ssoextv1.CreateAppRequest{
+		Name:     name,
+		Password: appPassword,
+		Secret:   appSecret,
+		TtlHour:  appTTL,
+	})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+}
+
+func TestAppAdmin_RequiresAPIKey(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	cc, err := grpc.DialContext(ctx, st.AdminAddress(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { cc.Close() })
+	client := ssoextv1.NewAppAdminClient(cc)
+
+	_, err = client.ListApps(ctx, &ssoextv1.ListAppsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", "wrong-key")
+	_, err = client.ListApps(ctx, &ssoextv1.ListAppsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestAddApp_UnknownAppIsNotCreated(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	_, err := st.AuthClient.AddApp(ctx, &ssov1.AddAppRequest{
+		Name:     gofakeit.UUID(),
+		Password: appPassword,
+		Secret:   appSecret,
+		TtlHour:  appTTL,
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestAppAdmin_ListAppsPagination(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	for i := 0; i < 3; i++ {
+		AddNamedApp(t, ctx, st, gofakeit.UUID())
+	}
+
+	apps := ListAllApps(t, ctx, st)
+	require.GreaterOrEqual(t, len(apps), 3)
+	for i := 1; i < len(apps); i++ {
+		assert.Less(t, apps[i-1].GetId(), apps[i].GetId())
+	}
+
+	_, err := st.AdminClient.ListApps(ctx, &ssoextv1.ListAppsRequest{PageToken: "not-a-number"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAppAdmin_DisabledAppRejectsLogin(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddNamedApp(t, ctx, st, gofakeit.UUID())
+	_, email, pass := RegisterUser(t, ctx, st)
+	respLogin := RegisterLoginWithRefresh(t, ctx, st, appID)
+
+	_, err := st.AdminClient.SetAppDisabled(ctx, &ssoextv1.SetAppDisabledRequest{AppId: appID, Disabled: true})
+	require.NoError(t, err)
+
+	respGet, err := st.AdminClient.GetApp(ctx, &ssoextv1.GetAppRequest{AppId: appID})
+	require.NoError(t, err)
+	assert.True(t, respGet.GetApp().GetDisabled())
+
+	_, err = st.AuthClient.Login(ctx, &ssov1.LoginRequest{Email: email, Password: pass, AppId: appID})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = st.TokenClient.Refresh(ctx, &ssoextv1.RefreshRequest{RefreshToken: respLogin.GetRefreshToken()})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = st.AdminClient.SetAppDisabled(ctx, &ssoextv1.SetAppDisabledRequest{AppId: appID, Disabled: false})
+	require.NoError(t, err)
+
+	Login(t, ctx, st, email, pass, appID)
+}
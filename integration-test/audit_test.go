@@ -0,0 +1,175 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/1kovalevskiy/sso/integration-test/suite"
+
+	ssov1 "github.com/1kovalevskiy/proto_sso/gen/go/sso"
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// adminEmail is granted admin rights through the UserAdmin service.
+const (
+	adminEmail = "security@sso.test"
+	adminPass  = "Secur1ty-Team-Passw0rd!"
+)
+
+// AdminLogin returns an access token of the admin user.
+func AdminLogin(t *testing.T, ctx context.Context, st *suite.Suite, appID int32) string {
+	_, err := st.AuthClient.Register(ctx, &ssov1.RegisterRequest{
+		Email:    adminEmail,
+		Password: adminPass,
+	})
+	if status.Code(err) != codes.AlreadyExists {
+		require.NoError(t, err)
+	}
+
+	token := Login(t, ctx, st, adminEmail, adminPass, appID)
+
+	resp, err := st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{Token: token})
+	require.NoError(t, err)
+	require.True(t, resp.GetValid())
+
+	_, err = st.UserAdminClient.SetUserAdmin(ctx, &ssoextv1.SetUserAdminRequest{
+		UserId:  resp.GetClaims().GetUserId(),
+		IsAdmin: true,
+	})
+	require.NoError(t, err)
+
+	return token
+}
+
+func TestListAuthEvents_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddApp(t, ctx, st)
+	uid, email, pass := RegisterUser(t, ctx, st)
+
+	_, err := st.AuthClient.Login(ctx, &ssov1.LoginRequest{Email: email, Password: randomFakePassword(), AppId: appID})
+	require.Error(t, err)
+	Login(t, ctx, st, email, pass, appID)
+
+	adminToken := AdminLogin(t, ctx, st, appID)
+
+	resp, err := st.AuditClient.ListAuthEvents(ctx, &ssoextv1.ListAuthEventsRequest{
+		Token:  adminToken,
+		UserId: uid,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.GetEvents(), 3)
+	assert.Empty(t, resp.GetNextPageToken())
+
+	types := make([]string, 0, len(resp.GetEvents()))
+	for _, event := range resp.GetEvents() {
+		types = append(types, event.GetType())
+		assert.Equal(t, uid, event.GetUserId())
+		assert.Equal(t, email, event.GetEmail())
+		assert.NotEmpty(t, event.GetPeerAddr())
+		assert.Contains(t, event.GetUserAgent(), "grpc-go")
+		assert.NotZero(t, event.GetCreatedAt())
+	}
+	assert.Equal(t, []string{"login_success", "login_failure", "register"}, types)
+	assert.Equal(t, int64(appID), resp.GetEvents()[0].GetAppId())
+	assert.Zero(t, resp.GetEvents()[2].GetAppId())
+}
+
+func TestListAuthEvents_Pagination(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddApp(t, ctx, st)
+	uid, email, pass := RegisterUser(t, ctx, st)
+	Login(t, ctx, st, email, pass, appID)
+	Login(t, ctx, st, email, pass, appID)
+
+	adminToken := AdminLogin(t, ctx, st, appID)
+
+	var ids []int64
+	pageToken := ""
+	for page := 0; page < 3; page++ {
+		resp, err := st.AuditClient.ListAuthEvents(ctx, &ssoextv1.ListAuthEventsRequest{
+			Token:     adminToken,
+			UserId:    uid,
+			Types:     []string{"login_success"},
+			PageSize:  1,
+			PageToken: pageToken,
+		})
+		require.NoError(t, err)
+
+		for _, event := range resp.GetEvents() {
+			ids = append(ids, event.GetId())
+		}
+
+		pageToken = resp.GetNextPageToken()
+		if pageToken == "" {
+			break
+		}
+	}
+
+	require.Len(t, ids, 2)
+	assert.Greater(t, ids[0], ids[1])
+}
+
+func TestListAuthEvents_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddApp(t, ctx, st)
+	_, _, userToken := RegisterLogin(t, ctx, st, appID)
+	adminToken := AdminLogin(t, ctx, st, appID)
+
+	tests := []struct {
+		name         string
+		req          *ssoextv1.ListAuthEventsRequest
+		expectedCode codes.Code
+		expectedErr  string
+	}{
+		{
+			name:         "Without Token",
+			req:          &ssoextv1.ListAuthEventsRequest{},
+			expectedCode: codes.InvalidArgument,
+			expectedErr:  "token is required",
+		},
+		{
+			name:         "Invalid Token",
+			req:          &ssoextv1.ListAuthEventsRequest{Token: "not-a-token"},
+			expectedCode: codes.Unauthenticated,
+			expectedErr:  "invalid token",
+		},
+		{
+			name:         "Not Admin",
+			req:          &ssoextv1.ListAuthEventsRequest{Token: userToken},
+			expectedCode: codes.PermissionDenied,
+			expectedErr:  "permission denied",
+		},
+		{
+			name:         "Invalid Page Token",
+			req:          &ssoextv1.ListAuthEventsRequest{Token: adminToken, PageToken: "abc"},
+			expectedCode: codes.InvalidArgument,
+			expectedErr:  "invalid page_token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AuditClient.ListAuthEvents(ctx, tt.req)
+			require.Error(t, err)
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestSetUserAdmin_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	_, err := st.UserAdminClient.SetUserAdmin(ctx, &ssoextv1.SetUserAdminRequest{UserId: 0, IsAdmin: true})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = st.UserAdminClient.SetUserAdmin(ctx, &ssoextv1.SetUserAdminRequest{UserId: 1 << 40, IsAdmin: true})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
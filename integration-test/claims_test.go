@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/1kovalevskiy/sso/integration-test/suite"
+
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCustomClaims(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddNamedApp(t, ctx, st, gofakeit.UUID())
+
+	_, err := st.AdminClient.SetAppClaims(ctx, &ssoextv1.SetAppClaimsRequest{
+		AppId:  appID,
+		Claims: map[string]string{"exp": "0"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = st.AdminClient.SetAppClaims(ctx, &ssoextv1.SetAppClaimsRequest{
+		AppId:  appID,
+		Claims: map[string]string{"tenant": "{{tenant}}", "plan": "{{plan}}", "locale": "{{locale}}"},
+	})
+	require.NoError(t, err)
+
+	respApp, err := st.AdminClient.GetApp(ctx, &ssoextv1.GetAppRequest{AppId: appID})
+	require.NoError(t, err)
+	assert.Equal(t, "{{plan}}", respApp.GetApp().GetClaims()["plan"])
+
+	uid, email, pass := RegisterUser(t, ctx, st)
+
+	for key, value := range map[string]string{"tenant": "acme", "plan": "pro"} {
+		_, err = st.UserAdminClient.SetUserAttribute(ctx, &ssoextv1.SetUserAttributeRequest{
+			UserId: uid,
+			Key:    key,
+			Value:  value,
+		})
+		require.NoError(t, err)
+	}
+
+	respAttributes, err := st.UserAdminClient.GetUserAttributes(ctx, &ssoextv1.GetUserAttributesRequest{UserId: uid})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"tenant": "acme", "plan": "pro"}, respAttributes.GetAttributes())
+
+	token := Login(t, ctx, st, email, pass, appID)
+
+	respValidate, err := st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{Token: token})
+	require.NoError(t, err)
+	require.True(t, respValidate.GetValid())
+	assert.Equal(t, uid, respValidate.GetClaims().GetUserId())
+	assert.Equal(t, map[string]string{"tenant": "acme", "plan": "pro"}, respValidate.GetClaims().GetCustom())
+
+	_, err = st.UserAdminClient.DeleteUserAttribute(ctx, &ssoextv1.DeleteUserAttributeRequest{UserId: uid, Key: "plan"})
+	require.NoError(t, err)
+
+	respValidate, err = st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{
+		Token: Login(t, ctx, st, email, pass, appID),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"tenant": "acme"}, respValidate.GetClaims().GetCustom())
+
+	_, err = st.UserAdminClient.SetUserAttribute(ctx, &ssoextv1.SetUserAttributeRequest{
+		UserId: uid + 100000,
+		Key:    "tenant",
+		Value:  "acme",
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/1kovalevskiy/sso/integration-test/suite"
+
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIssueClientToken(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	name := gofakeit.UUID()
+	appID := AddNamedApp(t, ctx, st, name)
+
+	_, err := st.AdminClient.SetAppScopes(ctx, &ssoextv1.SetAppScopesRequest{
+		AppId:  appID,
+		Scopes: []string{"orders:read", "orders:write"},
+	})
+	require.NoError(t, err)
+
+	respApp, err := st.AdminClient.GetApp(ctx, &ssoextv1.GetAppRequest{AppId: appID})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders:read", "orders:write"}, respApp.GetApp().GetScopes())
+
+	resp, err := st.TokenClient.IssueClientToken(ctx, &ssoextv1.IssueClientTokenRequest{
+		Name:     name,
+		Password: appPassword,
+		Scopes:   []string{"orders:read"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders:read"}, resp.GetScopes())
+
+	respValidate, err := st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{Token: resp.GetAccessToken()})
+	require.NoError(t, err)
+	require.True(t, respValidate.GetValid())
+	assert.Equal(t, "app:"+strconv.Itoa(int(appID)), respValidate.GetClaims().GetSubject())
+	assert.Equal(t, appID, respValidate.GetClaims().GetAppId())
+	assert.Zero(t, respValidate.GetClaims().GetUserId())
+	assert.Equal(t, []string{"orders:read"}, respValidate.GetClaims().GetPermissions())
+
+	_, err = st.TokenClient.IssueClientToken(ctx, &ssoextv1.IssueClientTokenRequest{
+		Name:     name,
+		Password: "wrong-password",
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = st.TokenClient.IssueClientToken(ctx, &ssoextv1.IssueClientTokenRequest{
+		Name:     name,
+		Password: appPassword,
+		Scopes:   []string{"orders:delete"},
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = st.AdminClient.SetAppScopes(ctx, &ssoextv1.SetAppScopesRequest{
+		AppId:  appID,
+		Scopes: []string{"orders read"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestClientCredentialsGrant(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	name := gofakeit.UUID()
+	appID := AddNamedApp(t, ctx, st, name)
+
+	_, err := st.AdminClient.SetAppScopes(ctx, &ssoextv1.SetAppScopesRequest{
+		AppId:  appID,
+		Scopes: []string{"orders:read", "orders:write"},
+	})
+	require.NoError(t, err)
+
+	clientCredentials := func(clientID string, password string, scope string) (*http.Response, map[string]any) {
+		form := url.Values{}
+		form.Set("grant_type", "client_credentials")
+		form.Set("scope", scope)
+
+		req, err := http.NewRequest(http.MethodPost, st.HTTPURL("/token"), strings.NewReader(form.Encode()))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(password))
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+		return resp, body
+	}
+
+	// Like on the other grants, the client is identified by the app id.
+	clientID := strconv.Itoa(int(appID))
+
+	resp, body := clientCredentials(name, appPassword, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, "invalid_client", body["error"])
+
+	resp, body = clientCredentials(clientID, appPassword, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Equal(t, "Bearer", body["token_type"])
+	assert.Equal(t, "orders:read orders:write", body["scope"])
+	assert.NotContains(t, body, "refresh_token")
+
+	respValidate, err := st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{Token: body["access_token"].(string)})
+	require.NoError(t, err)
+	require.True(t, respValidate.GetValid())
+	assert.Equal(t, "app:"+strconv.Itoa(int(appID)), respValidate.GetClaims().GetSubject())
+
+	// App tokens do not act for any user.
+	req, err := http.NewRequest(http.MethodGet, st.HTTPURL("/userinfo"), nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+body["access_token"].(string))
+
+	userInfo, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	userInfo.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, userInfo.StatusCode)
+
+	resp, body = clientCredentials(clientID, appPassword, "orders:delete")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "invalid_scope", body["error"])
+
+	resp, body = clientCredentials(clientID, "wrong-password", "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, "invalid_client", body["error"])
+}
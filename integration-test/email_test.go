@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/1kovalevskiy/sso/integration-test/suite"
+
+	ssov1 "github.com/1kovalevskiy/proto_sso/gen/go/sso"
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRegister_InvalidEmail(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	for _, email := range []string{
+		"plainaddress",
+		"@example.com",
+		"bob@",
+		"bob@@example.com",
+		"Bob <bob@example.com>",
+		"<bob@example.com>",
+		"bob@example.com (Bob)",
+		"bob smith@example.com",
+	} {
+		t.Run(email, func(t *testing.T) {
+			_, err := st.AuthClient.Register(ctx, &ssov1.RegisterRequest{
+				Email:    email,
+				Password: randomFakePassword(),
+			})
+			require.Error(t, err)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Contains(t, err.Error(), "email is invalid")
+		})
+	}
+}
+
+func TestRegister_EmailIsCaseInsensitive(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddApp(t, ctx, st)
+
+	email := gofakeit.Email()
+	pass := randomFakePassword()
+
+	respReg, err := st.AuthClient.Register(ctx, &ssov1.RegisterRequest{
+		Email:    strings.ToUpper(email),
+		Password: pass,
+	})
+	require.NoError(t, err)
+
+	token := Login(t, ctx, st, email, pass, appID)
+
+	respUser, err := st.UsersClient.GetUser(ctx, &ssoextv1.GetUserRequest{UserId: respReg.GetUserId(), Token: token})
+	require.NoError(t, err)
+	assert.Equal(t, strings.ToLower(email), respUser.GetEmail())
+
+	_, err = st.AuthClient.Register(ctx, &ssov1.RegisterRequest{
+		Email:    strings.ToLower(email),
+		Password: pass,
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	Login(t, ctx, st, strings.ToLower(email), pass, appID)
+	Login(t, ctx, st, strings.ToUpper(email), pass, appID)
+}
@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/1kovalevskiy/sso/integration-test/suite"
+
+	ssov1 "github.com/1kovalevskiy/proto_sso/gen/go/sso"
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSetAppEmailPolicy_RefusesUnverified(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddNamedApp(t, ctx, st, gofakeit.UUID())
+	uid, email, pass := RegisterUser(t, ctx, st)
+
+	token := Login(t, ctx, st, email, pass, appID)
+
+	respUser, err := st.UsersClient.GetUser(ctx, &ssoextv1.GetUserRequest{UserId: uid, Token: token})
+	require.NoError(t, err)
+	assert.False(t, respUser.GetEmailVerified())
+
+	_, err = st.AdminClient.SetAppEmailPolicy(ctx, &ssoextv1.SetAppEmailPolicyRequest{AppId: appID, RequireVerifiedEmail: true})
+	require.NoError(t, err)
+
+	respGet, err := st.AdminClient.GetApp(ctx, &ssoextv1.GetAppRequest{AppId: appID})
+	require.NoError(t, err)
+	assert.True(t, respGet.GetApp().GetRequireVerifiedEmail())
+
+	_, err = st.AuthClient.Login(ctx, &ssov1.LoginRequest{Email: email, Password: pass, AppId: appID})
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Contains(t, err.Error(), "email is not verified")
+
+	_, err = st.AdminClient.SetAppEmailPolicy(ctx, &ssoextv1.SetAppEmailPolicyRequest{AppId: appID, RequireVerifiedEmail: false})
+	require.NoError(t, err)
+
+	Login(t, ctx, st, email, pass, appID)
+}
+
+func TestSetAppEmailPolicy_UnknownApp(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	_, err := st.AdminClient.SetAppEmailPolicy(ctx, &ssoextv1.SetAppEmailPolicyRequest{AppId: unknownAppID, RequireVerifiedEmail: true})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestVerifyEmail_InvalidToken(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	_, err := st.UsersClient.VerifyEmail(ctx, &ssoextv1.VerifyEmailRequest{Token: gofakeit.UUID()})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, err.Error(), "invalid or expired verification token")
+
+	_, err = st.UsersClient.VerifyEmail(ctx, &ssoextv1.VerifyEmailRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRequestEmailVerification(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	_, err := st.UsersClient.RequestEmailVerification(ctx, &ssoextv1.RequestEmailVerificationRequest{Email: gofakeit.Email()})
+	require.NoError(t, err)
+
+	_, email, _ := RegisterUser(t, ctx, st)
+	_, err = st.UsersClient.RequestEmailVerification(ctx, &ssoextv1.RequestEmailVerificationRequest{Email: email})
+	require.NoError(t, err)
+}
@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/1kovalevskiy/sso/integration-test/suite"
+
+	ssov1 "github.com/1kovalevskiy/proto_sso/gen/go/sso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLogin_Lockout(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddApp(t, ctx, st)
+	_, email, pass := RegisterUser(t, ctx, st)
+
+	wrongLogin := func() error {
+		_, err := st.AuthClient.Login(ctx, &ssov1.LoginRequest{
+			Email:    email,
+			Password: randomFakePassword(),
+			AppId:    appID,
+		})
+		return err
+	}
+
+	lockout := st.Cfg.Lockout
+	require.Equal(t, 3, lockout.DelayAfter, "test expects the default lockout policy")
+	require.Equal(t, 5, lockout.MaxFailures, "test expects the default lockout policy")
+
+	for i := 0; i < lockout.DelayAfter; i++ {
+		assert.Equal(t, codes.InvalidArgument, status.Code(wrongLogin()))
+	}
+
+	// Every attempt beyond DelayAfter failures has to wait longer.
+	err := wrongLogin()
+	require.Error(t, err)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	time.Sleep(1100 * time.Millisecond)
+	assert.Equal(t, codes.InvalidArgument, status.Code(wrongLogin()))
+
+	_, err = st.AuthClient.Login(ctx, &ssov1.LoginRequest{Email: email, Password: pass, AppId: appID})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	time.Sleep(2100 * time.Millisecond)
+	assert.Equal(t, codes.InvalidArgument, status.Code(wrongLogin()))
+
+	// MaxFailures reached, even the right password is refused now.
+	_, err = st.AuthClient.Login(ctx, &ssov1.LoginRequest{Email: email, Password: pass, AppId: appID})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Contains(t, err.Error(), "account is temporarily locked")
+}
+
+func TestLogin_SuccessResetsFailures(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddApp(t, ctx, st)
+	_, email, pass := RegisterUser(t, ctx, st)
+
+	for round := 0; round < 3; round++ {
+		for i := 0; i < st.Cfg.Lockout.DelayAfter-1; i++ {
+			_, err := st.AuthClient.Login(ctx, &ssov1.LoginRequest{
+				Email:    email,
+				Password: randomFakePassword(),
+				AppId:    appID,
+			})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		}
+
+		Login(t, ctx, st, email, pass, appID)
+	}
+}
@@ -24,19 +24,8 @@ const (
 	passDefaultLen = 10
 )
 
-// TODO: add token fail validation cases
-
-
 func AddApp(t *testing.T, ctx context.Context, st *suite.Suite) int32 {
-	respAppAdd, err := st.AuthClient.AddApp(ctx, &ssov1.AddAppRequest{
-		Name:    	appName,
-		Password: 	appPassword,
-		Secret: 	appSecret,
-		TtlHour: 	appTTL,
-	})
-	require.NoError(t, err)
-	assert.NotEmpty(t, respAppAdd.GetAppId())
-	return respAppAdd.GetAppId()
+	return AddNamedApp(t, ctx, st, appName)
 }
 
 func TestRegisterLogin_Login_HappyPath(t *testing.T) {
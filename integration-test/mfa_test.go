@@ -0,0 +1,169 @@
+package tests
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/1kovalevskiy/sso/integration-test/suite"
+
+	ssov1 "github.com/1kovalevskiy/proto_sso/gen/go/sso"
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// totpCode computes the RFC 6238 code of a base32 secret at t.
+func totpCode(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	require.NoError(t, err)
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(at.Unix()/30))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%06d", value%1_000_000)
+}
+
+// mfaChallenge returns the challenge of a login that asks for a second factor.
+func mfaChallenge(t *testing.T, err error) string {
+	t.Helper()
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.FailedPrecondition, st.Code())
+
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			require.Equal(t, "MFA_REQUIRED", info.GetReason())
+
+			expiresAt, err := strconv.ParseInt(info.GetMetadata()["expires_at"], 10, 64)
+			require.NoError(t, err)
+			assert.Greater(t, expiresAt, time.Now().Unix())
+
+			return info.GetMetadata()["challenge"]
+		}
+	}
+
+	t.Fatal("no MFA_REQUIRED error info")
+
+	return ""
+}
+
+// enableTOTP turns TOTP on for the owner of token and returns its secret and
+// recovery codes. The current code is used up by the confirmation.
+func enableTOTP(t *testing.T, ctx context.Context, st *suite.Suite, token string) (string, []string) {
+	t.Helper()
+
+	enrollment, err := st.MFAClient.EnrollTOTP(ctx, &ssoextv1.EnrollTOTPRequest{AccessToken: token})
+	require.NoError(t, err)
+
+	uri, err := url.Parse(enrollment.GetOtpauthUri())
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, enrollment.GetSecret(), uri.Query().Get("secret"))
+
+	confirmed, err := st.MFAClient.ConfirmTOTP(ctx, &ssoextv1.ConfirmTOTPRequest{
+		AccessToken: token,
+		Code:        totpCode(t, enrollment.GetSecret(), time.Now()),
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, confirmed.GetRecoveryCodes())
+
+	return enrollment.GetSecret(), confirmed.GetRecoveryCodes()
+}
+
+func TestMFA_Login(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddApp(t, ctx, st)
+	uid, email, pass := RegisterUser(t, ctx, st)
+	token := Login(t, ctx, st, email, pass, appID)
+
+	secret, recovery := enableTOTP(t, ctx, st, token)
+
+	_, err := st.AuthClient.Login(ctx, &ssov1.LoginRequest{Email: email, Password: pass, AppId: appID})
+	challenge := mfaChallenge(t, err)
+
+	_, err = st.MFAClient.VerifyLogin(ctx, &ssoextv1.VerifyLoginRequest{Challenge: challenge, Code: "000000"})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	code := totpCode(t, secret, time.Now().Add(30*time.Second))
+	resp, err := st.MFAClient.VerifyLogin(ctx, &ssoextv1.VerifyLoginRequest{Challenge: challenge, Code: code})
+	require.NoError(t, err)
+	assert.Empty(t, resp.GetRefreshToken())
+
+	validated, err := st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{Token: resp.GetAccessToken()})
+	require.NoError(t, err)
+	assert.Equal(t, uid, validated.GetClaims().GetUserId())
+
+	_, err = st.MFAClient.VerifyLogin(ctx, &ssoextv1.VerifyLoginRequest{Challenge: challenge, Code: code})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = st.TokenClient.Login(ctx, &ssoextv1.LoginRequest{Email: email, Password: pass, AppId: appID})
+	challenge = mfaChallenge(t, err)
+
+	// A code is accepted once, recovery codes work in its place.
+	_, err = st.MFAClient.VerifyLogin(ctx, &ssoextv1.VerifyLoginRequest{Challenge: challenge, Code: code})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	resp, err = st.MFAClient.VerifyLogin(ctx, &ssoextv1.VerifyLoginRequest{Challenge: challenge, Code: recovery[0]})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.GetRefreshToken())
+
+	_, err = st.MFAClient.DisableTOTP(ctx, &ssoextv1.DisableTOTPRequest{AccessToken: token, Code: recovery[0]})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = st.MFAClient.DisableTOTP(ctx, &ssoextv1.DisableTOTPRequest{AccessToken: token, Code: recovery[1]})
+	require.NoError(t, err)
+
+	Login(t, ctx, st, email, pass, appID)
+}
+
+func TestMFA_Fails(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddApp(t, ctx, st)
+	_, _, token := RegisterLogin(t, ctx, st, appID)
+
+	_, err := st.MFAClient.EnrollTOTP(ctx, &ssoextv1.EnrollTOTPRequest{AccessToken: "not-a-token"})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = st.MFAClient.ConfirmTOTP(ctx, &ssoextv1.ConfirmTOTPRequest{AccessToken: token, Code: "123456"})
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = st.MFAClient.VerifyLogin(ctx, &ssoextv1.VerifyLoginRequest{Challenge: "unknown", Code: "123456"})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	enableTOTP(t, ctx, st, token)
+
+	_, err = st.MFAClient.EnrollTOTP(ctx, &ssoextv1.EnrollTOTPRequest{AccessToken: token})
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
@@ -0,0 +1,342 @@
+package tests
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1kovalevskiy/sso/integration-test/suite"
+
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	redirectURI  = "https://app.example.com/callback"
+	codeVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+)
+
+var (
+	challengeInput = regexp.MustCompile(`name="challenge" value="([^"]+)"`)
+	csrfInput      = regexp.MustCompile(`name="csrf_token" value="([^"]+)"`)
+)
+
+// noRedirects is an HTTP client that hands redirects back to the test.
+var noRedirects = &http.Client{
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+	Timeout: 5 * time.Second,
+}
+
+// newBrowser returns an HTTP client that keeps cookies, like the browser of
+// a user signing in, and hands redirects back to the test.
+func newBrowser(t *testing.T) *http.Client {
+	t.Helper()
+
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+
+	return &http.Client{
+		Jar:           jar,
+		CheckRedirect: noRedirects.CheckRedirect,
+		Timeout:       noRedirects.Timeout,
+	}
+}
+
+// openLoginPage loads the login page of the authorization request params in
+// browser and returns the page and the fields its form posts.
+func openLoginPage(t *testing.T, st *suite.Suite, browser *http.Client, params url.Values) (string, url.Values) {
+	t.Helper()
+
+	resp, err := browser.Get(st.HTTPURL("/authorize?" + params.Encode()))
+	require.NoError(t, err)
+	page, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	match := csrfInput.FindStringSubmatch(string(page))
+	require.Len(t, match, 2)
+
+	form := url.Values{}
+	for key, values := range params {
+		form[key] = values
+	}
+	form.Set("csrf_token", match[1])
+
+	return string(page), form
+}
+
+// AddOAuthClient creates an RS256 app that may receive codes at redirectURI.
+func AddOAuthClient(t *testing.T, ctx context.Context, st *suite.Suite) (int32, string) {
+	name := gofakeit.UUID()
+	appID := AddNamedApp(t, ctx, st, name)
+
+	_, err := st.AppClient.SetSigningAlgorithm(ctx, &ssoextv1.SetSigningAlgorithmRequest{
+		Name:      name,
+		Password:  appPassword,
+		Algorithm: ssoextv1.SigningAlgorithm_SIGNING_ALGORITHM_RS256,
+	})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.SetAppRedirectURIs(ctx, &ssoextv1.SetAppRedirectURIsRequest{
+		AppId:        appID,
+		RedirectUris: []string{redirectURI},
+	})
+	require.NoError(t, err)
+
+	return appID, name
+}
+
+func authorizeParams(appID int32, scope string) url.Values {
+	sum := sha256.Sum256([]byte(codeVerifier))
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", strconv.Itoa(int(appID)))
+	params.Set("redirect_uri", redirectURI)
+	params.Set("scope", scope)
+	params.Set("state", "xyz")
+	params.Set("nonce", "n-0S6_WzA2Mj")
+	params.Set("code_challenge", base64.RawURLEncoding.EncodeToString(sum[:]))
+	params.Set("code_challenge_method", "S256")
+
+	return params
+}
+
+// authorizationCode returns the code of the redirect in resp.
+func authorizationCode(t *testing.T, resp *http.Response) string {
+	t.Helper()
+
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, redirectURI, location.Scheme+"://"+location.Host+location.Path)
+	assert.Equal(t, "xyz", location.Query().Get("state"))
+	require.NotEmpty(t, location.Query().Get("code"))
+
+	return location.Query().Get("code")
+}
+
+func exchangeCode(t *testing.T, st *suite.Suite, appID int32, code string) (*http.Response, map[string]any) {
+	t.Helper()
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURI)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequest(http.MethodPost, st.HTTPURL("/token"), strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(strconv.Itoa(int(appID)), appPassword)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+	return resp, body
+}
+
+func TestOIDC_Discovery(t *testing.T) {
+	_, st := suite.New(t)
+
+	resp, err := http.Get(st.HTTPURL("/.well-known/openid-configuration"))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var doc map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+
+	issuer, ok := doc["issuer"].(string)
+	require.True(t, ok)
+	assert.Equal(t, issuer+"/token", doc["token_endpoint"])
+	assert.Equal(t, issuer+"/.well-known/jwks.json", doc["jwks_uri"])
+	assert.Equal(t, []any{"S256"}, doc["code_challenge_methods_supported"])
+}
+
+func TestOIDC_AuthorizationCode(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID, name := AddOAuthClient(t, ctx, st)
+	uid, email, pass := RegisterUser(t, ctx, st)
+
+	params := authorizeParams(appID, "openid email")
+	browser := newBrowser(t)
+
+	page, login := openLoginPage(t, st, browser, params)
+	assert.Contains(t, page, "Sign in to "+name)
+
+	login.Set("email", email)
+	login.Set("password", "wrong-password")
+
+	resp, err := browser.PostForm(st.HTTPURL("/authorize"), login)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	login.Set("password", pass)
+
+	// A form posted from another site carries neither the cookie nor the
+	// token of the browser.
+	forged := url.Values{}
+	for key, values := range login {
+		forged[key] = values
+	}
+	forged.Del("csrf_token")
+
+	resp, err = noRedirects.PostForm(st.HTTPURL("/authorize"), forged)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp, err = browser.PostForm(st.HTTPURL("/authorize"), login)
+	require.NoError(t, err)
+	resp.Body.Close()
+	code := authorizationCode(t, resp)
+
+	tokenResp, body := exchangeCode(t, st, appID, code)
+	require.Equal(t, http.StatusOK, tokenResp.StatusCode, body)
+	assert.Equal(t, "no-store", tokenResp.Header.Get("Cache-Control"))
+	assert.Equal(t, "Bearer", body["token_type"])
+	assert.Equal(t, "openid email", body["scope"])
+	assert.NotContains(t, body, "refresh_token")
+
+	idToken := body["id_token"].(string)
+	parsed, err := jwt.Parse(idToken, func(token *jwt.Token) (interface{}, error) {
+		return publicKey(t, fetchJWK(t, st, token.Header["kid"].(string))), nil
+	}, jwt.WithAudience(strconv.Itoa(int(appID))))
+	require.NoError(t, err)
+
+	claims := parsed.Claims.(jwt.MapClaims)
+	assert.Equal(t, strconv.Itoa(int(uid)), claims["sub"])
+	assert.Equal(t, "n-0S6_WzA2Mj", claims["nonce"])
+	assert.Equal(t, email, claims["email"])
+
+	req, err := http.NewRequest(http.MethodGet, st.HTTPURL("/userinfo"), nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+body["access_token"].(string))
+
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	var info map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, strconv.Itoa(int(uid)), info["sub"])
+	assert.Equal(t, email, info["email"])
+
+	// Codes are single-use, and a reused code revokes the tokens it was
+	// exchanged for.
+	tokenResp, body = exchangeCode(t, st, appID, code)
+	assert.Equal(t, http.StatusBadRequest, tokenResp.StatusCode)
+	assert.Equal(t, "invalid_grant", body["error"])
+
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestOIDC_Fails(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID, _ := AddOAuthClient(t, ctx, st)
+
+	// An unregistered redirect uri is never redirected to.
+	params := authorizeParams(appID, "openid")
+	params.Set("redirect_uri", "https://evil.example.com/callback")
+
+	resp, err := noRedirects.Get(st.HTTPURL("/authorize?" + params.Encode()))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	params = authorizeParams(appID, "openid")
+	params.Del("code_challenge")
+
+	resp, err = noRedirects.Get(st.HTTPURL("/authorize?" + params.Encode()))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "invalid_request", location.Query().Get("error"))
+	assert.Equal(t, "xyz", location.Query().Get("state"))
+
+	tokenResp, body := exchangeCode(t, st, appID+1000, "unknown")
+	assert.Equal(t, http.StatusUnauthorized, tokenResp.StatusCode)
+	assert.Equal(t, "invalid_client", body["error"])
+
+	tokenResp, body = exchangeCode(t, st, appID, "unknown")
+	assert.Equal(t, http.StatusBadRequest, tokenResp.StatusCode)
+	assert.Equal(t, "invalid_grant", body["error"])
+
+	resp, err = http.Get(st.HTTPURL("/userinfo"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestOIDC_MFA(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID, _ := AddOAuthClient(t, ctx, st)
+	_, email, pass := RegisterUser(t, ctx, st)
+	secret, _ := enableTOTP(t, ctx, st, Login(t, ctx, st, email, pass, appID))
+
+	params := authorizeParams(appID, "openid")
+	browser := newBrowser(t)
+
+	_, login := openLoginPage(t, st, browser, params)
+	login.Set("email", email)
+	login.Set("password", pass)
+
+	resp, err := browser.PostForm(st.HTTPURL("/authorize"), login)
+	require.NoError(t, err)
+	page, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	match := challengeInput.FindStringSubmatch(string(page))
+	require.Len(t, match, 2)
+
+	verify := url.Values{}
+	for key, values := range params {
+		verify[key] = values
+	}
+	verify.Set("csrf_token", login.Get("csrf_token"))
+	verify.Set("challenge", match[1])
+	verify.Set("otp", totpCode(t, secret, time.Now().Add(30*time.Second)))
+
+	resp, err = browser.PostForm(st.HTTPURL("/authorize"), verify)
+	require.NoError(t, err)
+	resp.Body.Close()
+	code := authorizationCode(t, resp)
+
+	tokenResp, body := exchangeCode(t, st, appID, code)
+	require.Equal(t, http.StatusOK, tokenResp.StatusCode, body)
+	assert.NotEmpty(t, body["id_token"])
+}
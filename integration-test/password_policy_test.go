@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/1kovalevskiy/sso/integration-test/suite"
+
+	ssov1 "github.com/1kovalevskiy/proto_sso/gen/go/sso"
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// weakPasswordRules returns the failed password rules reported in err.
+func weakPasswordRules(t *testing.T, err error, field string) []string {
+	t.Helper()
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+
+	var rules []string
+	var violations int
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			assert.Equal(t, "WEAK_PASSWORD", d.GetReason())
+			for rule := range d.GetMetadata() {
+				rules = append(rules, rule)
+			}
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				assert.Equal(t, field, v.GetField())
+				assert.NotEmpty(t, v.GetDescription())
+				violations++
+			}
+		}
+	}
+	assert.Len(t, rules, violations)
+
+	return rules
+}
+
+func TestRegister_WeakPassword(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	tests := []struct {
+		name     string
+		password string
+		rules    []string
+	}{
+		{
+			name:     "Short lowercase",
+			password: "abc",
+			rules:    []string{"min_length", "uppercase", "digit"},
+		},
+		{
+			name:     "Over bcrypt limit",
+			password: "Aa1" + gofakeit.LetterN(70),
+			rules:    []string{"max_length"},
+		},
+		{
+			name:     "Denylisted",
+			password: "Password123",
+			rules:    []string{"denylist"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AuthClient.Register(ctx, &ssov1.RegisterRequest{
+				Email:    gofakeit.Email(),
+				Password: tt.password,
+			})
+			require.Error(t, err)
+			assert.ElementsMatch(t, tt.rules, weakPasswordRules(t, err, "password"))
+		})
+	}
+}
+
+func TestChangePassword_WeakPassword(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	_, email, pass := RegisterUser(t, ctx, st)
+
+	_, err := st.PassClient.ChangePassword(ctx, &ssoextv1.ChangePasswordRequest{
+		Email:           email,
+		CurrentPassword: pass,
+		NewPassword:     "password",
+	})
+	require.Error(t, err)
+	assert.ElementsMatch(t, []string{"uppercase", "digit", "denylist"}, weakPasswordRules(t, err, "new_password"))
+}
@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/1kovalevskiy/sso/integration-test/suite"
+
+	ssov1 "github.com/1kovalevskiy/proto_sso/gen/go/sso"
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestChangePassword_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddApp(t, ctx, st)
+	_, email, pass := RegisterUser(t, ctx, st)
+	respLogin, err := st.TokenClient.Login(ctx, &ssoextv1.LoginRequest{Email: email, Password: pass, AppId: appID})
+	require.NoError(t, err)
+
+	newPass := randomFakePassword()
+	_, err = st.PassClient.ChangePassword(ctx, &ssoextv1.ChangePasswordRequest{
+		Email:           email,
+		CurrentPassword: pass,
+		NewPassword:     newPass,
+	})
+	require.NoError(t, err)
+
+	_, err = st.AuthClient.Login(ctx, &ssov1.LoginRequest{Email: email, Password: pass, AppId: appID})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	Login(t, ctx, st, email, newPass, appID)
+
+	// sessions opened with the old password are revoked
+	_, err = st.TokenClient.Refresh(ctx, &ssoextv1.RefreshRequest{RefreshToken: respLogin.GetRefreshToken()})
+	require.Error(t, err)
+}
+
+func TestChangePassword_Fails(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	_, email, pass := RegisterUser(t, ctx, st)
+
+	tests := []struct {
+		name        string
+		email       string
+		current     string
+		newPassword string
+		expectedErr string
+	}{
+		{
+			name:        "Empty email",
+			current:     pass,
+			newPassword: randomFakePassword(),
+			expectedErr: "email is required",
+		},
+		{
+			name:        "Empty current password",
+			email:       email,
+			newPassword: randomFakePassword(),
+			expectedErr: "current_password is required",
+		},
+		{
+			name:        "Empty new password",
+			email:       email,
+			current:     pass,
+			expectedErr: "new_password is required",
+		},
+		{
+			name:        "Wrong current password",
+			email:       email,
+			current:     randomFakePassword(),
+			newPassword: randomFakePassword(),
+			expectedErr: "invalid email or password",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.PassClient.ChangePassword(ctx, &ssoextv1.ChangePasswordRequest{
+				Email:           tt.email,
+				CurrentPassword: tt.current,
+				NewPassword:     tt.newPassword,
+			})
+			require.Error(t, err)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestRequestPasswordReset_UnknownEmail(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	// unknown emails look the same as registered ones
+	_, err := st.PassClient.RequestPasswordReset(ctx, &ssoextv1.RequestPasswordResetRequest{Email: gofakeit.Email()})
+	require.NoError(t, err)
+
+	_, email, _ := RegisterUser(t, ctx, st)
+	_, err = st.PassClient.RequestPasswordReset(ctx, &ssoextv1.RequestPasswordResetRequest{Email: email})
+	require.NoError(t, err)
+}
+
+func TestConfirmPasswordReset_InvalidToken(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	_, err := st.PassClient.ConfirmPasswordReset(ctx, &ssoextv1.ConfirmPasswordResetRequest{
+		Token:       gofakeit.UUID(),
+		NewPassword: randomFakePassword(),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, err.Error(), "invalid or expired reset token")
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/1kovalevskiy/sso/config"
+	"github.com/1kovalevskiy/sso/integration-test/suite"
+
+	ssov1 "github.com/1kovalevskiy/proto_sso/gen/go/sso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const loginMethod = "/auth.Auth/Login"
+
+func rateLimit(t *testing.T, st *suite.Suite, method string) config.RateLimitMethod {
+	for _, m := range st.Cfg.RateLimit.Methods {
+		if m.Method == method {
+			return m
+		}
+	}
+
+	t.Fatalf("no rate limit configured for %s", method)
+
+	return config.RateLimitMethod{}
+}
+
+func TestRateLimit_LoginPerPeerApp(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	limit := rateLimit(t, st, loginMethod)
+	require.Equal(t, "peer_app", limit.Key)
+
+	appID := AddApp(t, ctx, st)
+	_, email, pass := RegisterUser(t, ctx, st)
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		limited   []error
+		trailers  []metadata.MD
+		succeeded int
+	)
+
+	for i := 0; i < 2*limit.Burst; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var trailer metadata.MD
+			_, err := st.AuthClient.Login(ctx, &ssov1.LoginRequest{
+				Email:    email,
+				Password: pass,
+				AppId:    appID,
+			}, grpc.Trailer(&trailer))
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err == nil {
+				succeeded++
+				return
+			}
+			limited = append(limited, err)
+			trailers = append(trailers, trailer)
+		}()
+	}
+	wg.Wait()
+
+	require.NotEmpty(t, limited)
+	assert.GreaterOrEqual(t, succeeded, limit.Burst)
+
+	err := limited[0]
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, []string{"1"}, trailers[0].Get("retry-after"))
+
+	var retryInfo *errdetails.RetryInfo
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			retryInfo = info
+		}
+	}
+	require.NotNil(t, retryInfo)
+	assert.Positive(t, retryInfo.GetRetryDelay().AsDuration())
+
+	// Other apps have their own buckets.
+	otherAppID := AddApp(t, ctx, st)
+	Login(t, ctx, st, email, pass, otherAppID)
+}
@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/1kovalevskiy/sso/integration-test/suite"
+
+	ssov1 "github.com/1kovalevskiy/proto_sso/gen/go/sso"
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func RegisterLoginWithRefresh(t *testing.T, ctx context.Context, st *suite.Suite, appID int32) *ssoextv1.LoginResponse {
+	email := gofakeit.Email()
+	pass := randomFakePassword()
+
+	_, err := st.AuthClient.Register(ctx, &ssov1.RegisterRequest{
+		Email:    email,
+		Password: pass,
+	})
+	require.NoError(t, err)
+
+	respLogin, err := st.TokenClient.Login(ctx, &ssoextv1.LoginRequest{
+		Email:    email,
+		Password: pass,
+		AppId:    appID,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, respLogin.GetAccessToken())
+	require.NotEmpty(t, respLogin.GetRefreshToken())
+
+	return respLogin
+}
+
+func TestRefresh_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddApp(t, ctx, st)
+	respLogin := RegisterLoginWithRefresh(t, ctx, st, appID)
+
+	respRefresh, err := st.TokenClient.Refresh(ctx, &ssoextv1.RefreshRequest{
+		RefreshToken: respLogin.GetRefreshToken(),
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, respRefresh.GetAccessToken())
+	assert.NotEqual(t, respLogin.GetRefreshToken(), respRefresh.GetRefreshToken())
+
+	respValidate, err := st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{
+		Token: respRefresh.GetAccessToken(),
+	})
+	require.NoError(t, err)
+	assert.True(t, respValidate.GetValid())
+
+	_, err = st.TokenClient.Refresh(ctx, &ssoextv1.RefreshRequest{
+		RefreshToken: respRefresh.GetRefreshToken(),
+	})
+	require.NoError(t, err)
+}
+
+func TestRefresh_ReuseRevokesFamily(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddApp(t, ctx, st)
+	respLogin := RegisterLoginWithRefresh(t, ctx, st, appID)
+
+	respRefresh, err := st.TokenClient.Refresh(ctx, &ssoextv1.RefreshRequest{
+		RefreshToken: respLogin.GetRefreshToken(),
+	})
+	require.NoError(t, err)
+
+	_, err = st.TokenClient.Refresh(ctx, &ssoextv1.RefreshRequest{
+		RefreshToken: respLogin.GetRefreshToken(),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Contains(t, err.Error(), "refresh token reuse detected")
+
+	_, err = st.TokenClient.Refresh(ctx, &ssoextv1.RefreshRequest{
+		RefreshToken: respRefresh.GetRefreshToken(),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Contains(t, err.Error(), "refresh token revoked")
+}
+
+func TestRefresh_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	tests := []struct {
+		name         string
+		refreshToken string
+		expectedErr  string
+	}{
+		{
+			name:         "Refresh with Empty Token",
+			refreshToken: "",
+			expectedErr:  "refresh_token is required",
+		},
+		{
+			name:         "Refresh with Unknown Token",
+			refreshToken: gofakeit.UUID(),
+			expectedErr:  "invalid refresh token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.TokenClient.Refresh(ctx, &ssoextv1.RefreshRequest{
+				RefreshToken: tt.refreshToken,
+			})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
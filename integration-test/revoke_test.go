@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/1kovalevskiy/sso/integration-test/suite"
+
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRevokeToken_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddApp(t, ctx, st)
+	_, _, token := RegisterLogin(t, ctx, st, appID)
+
+	respValidate, err := st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{Token: token})
+	require.NoError(t, err)
+	require.True(t, respValidate.GetValid())
+	assert.NotEmpty(t, respValidate.GetClaims().GetId())
+
+	_, err = st.TokenClient.RevokeToken(ctx, &ssoextv1.RevokeTokenRequest{Token: token})
+	require.NoError(t, err)
+
+	respValidate, err = st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{Token: token})
+	require.NoError(t, err)
+	assert.False(t, respValidate.GetValid())
+	assert.Equal(t, ssoextv1.TokenStatus_TOKEN_STATUS_REVOKED, respValidate.GetStatus())
+
+	// revoking twice is a no-op
+	_, err = st.TokenClient.RevokeToken(ctx, &ssoextv1.RevokeTokenRequest{Token: token})
+	require.NoError(t, err)
+}
+
+func TestLogout_RevokesAccessAndRefreshTokens(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddApp(t, ctx, st)
+	respLogin := RegisterLoginWithRefresh(t, ctx, st, appID)
+
+	_, err := st.TokenClient.Logout(ctx, &ssoextv1.LogoutRequest{
+		AccessToken:  respLogin.GetAccessToken(),
+		RefreshToken: respLogin.GetRefreshToken(),
+	})
+	require.NoError(t, err)
+
+	respValidate, err := st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{
+		Token: respLogin.GetAccessToken(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ssoextv1.TokenStatus_TOKEN_STATUS_REVOKED, respValidate.GetStatus())
+
+	_, err = st.TokenClient.Refresh(ctx, &ssoextv1.RefreshRequest{
+		RefreshToken: respLogin.GetRefreshToken(),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Contains(t, err.Error(), "refresh token revoked")
+}
+
+func TestLogout_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddApp(t, ctx, st)
+	respLogin := RegisterLoginWithRefresh(t, ctx, st, appID)
+	otherLogin := RegisterLoginWithRefresh(t, ctx, st, appID)
+
+	tests := []struct {
+		name         string
+		accessToken  string
+		refreshToken string
+		expectedErr  string
+	}{
+		{
+			name:        "Logout with Empty Access Token",
+			expectedErr: "access_token is required",
+		},
+		{
+			name:        "Logout with Invalid Access Token",
+			accessToken: "not-a-token",
+			expectedErr: "invalid token",
+		},
+		{
+			name:         "Logout with Refresh Token of Another User",
+			accessToken:  respLogin.GetAccessToken(),
+			refreshToken: otherLogin.GetRefreshToken(),
+			expectedErr:  "invalid refresh token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.TokenClient.Logout(ctx, &ssoextv1.LogoutRequest{
+				AccessToken:  tt.accessToken,
+				RefreshToken: tt.refreshToken,
+			})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+
+	respValidate, err := st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{
+		Token: respLogin.GetAccessToken(),
+	})
+	require.NoError(t, err)
+	assert.True(t, respValidate.GetValid())
+}
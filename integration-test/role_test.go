@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/1kovalevskiy/sso/integration-test/suite"
+
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoles_EmbeddedInToken(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	name := gofakeit.UUID()
+	appID := AddNamedApp(t, ctx, st, name)
+	otherName := gofakeit.UUID()
+	AddNamedApp(t, ctx, st, otherName)
+
+	for role, permissions := range map[string][]string{
+		"editor": {"posts.write", "posts.read"},
+		"viewer": {"posts.read"},
+	} {
+		_, err := st.RolesClient.SetRole(ctx, &ssoextv1.SetRoleRequest{
+			AppName:     name,
+			AppPassword: appPassword,
+			Role:        role,
+			Permissions: permissions,
+		})
+		require.NoError(t, err)
+	}
+
+	_, err := st.RolesClient.SetRole(ctx, &ssoextv1.SetRoleRequest{
+		AppName:     otherName,
+		AppPassword: appPassword,
+		Role:        "admin",
+		Permissions: []string{"everything"},
+	})
+	require.NoError(t, err)
+
+	uid, email, pass := RegisterUser(t, ctx, st)
+
+	for _, assignment := range []struct{ app, role string }{
+		{name, "editor"},
+		{name, "viewer"},
+		{otherName, "admin"},
+	} {
+		_, err := st.RolesClient.AssignRole(ctx, &ssoextv1.AssignRoleRequest{
+			AppName:     assignment.app,
+			AppPassword: appPassword,
+			UserId:      uid,
+			Role:        assignment.role,
+		})
+		require.NoError(t, err)
+	}
+
+	token := Login(t, ctx, st, email, pass, appID)
+
+	tokenParsed, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+		return []byte(appSecret), nil
+	})
+	require.NoError(t, err)
+
+	claims, ok := tokenParsed.Claims.(jwt.MapClaims)
+	require.True(t, ok)
+	assert.Equal(t, []interface{}{"editor", "viewer"}, claims["roles"])
+	assert.Equal(t, "posts.read posts.write", claims["scope"])
+
+	resp, err := st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{Token: token})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"editor", "viewer"}, resp.GetClaims().GetRoles())
+	assert.Equal(t, []string{"posts.read", "posts.write"}, resp.GetClaims().GetPermissions())
+
+	_, err = st.RolesClient.UnassignRole(ctx, &ssoextv1.UnassignRoleRequest{
+		AppName:     name,
+		AppPassword: appPassword,
+		UserId:      uid,
+		Role:        "editor",
+	})
+	require.NoError(t, err)
+
+	resp, err = st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{Token: Login(t, ctx, st, email, pass, appID)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"viewer"}, resp.GetClaims().GetRoles())
+	assert.Equal(t, []string{"posts.read"}, resp.GetClaims().GetPermissions())
+}
+
+func TestAssignRole_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	name := gofakeit.UUID()
+	AddNamedApp(t, ctx, st, name)
+
+	_, err := st.RolesClient.SetRole(ctx, &ssoextv1.SetRoleRequest{
+		AppName:     name,
+		AppPassword: appPassword,
+		Role:        "viewer",
+	})
+	require.NoError(t, err)
+
+	uid, _, _ := RegisterUser(t, ctx, st)
+
+	tests := []struct {
+		name        string
+		appPassword string
+		userID      int64
+		role        string
+		expectedErr string
+	}{
+		{
+			name:        "Assign with Wrong App Password",
+			appPassword: "wrong-password",
+			userID:      uid,
+			role:        "viewer",
+			expectedErr: "invalid app name or password",
+		},
+		{
+			name:        "Assign Unknown Role",
+			appPassword: appPassword,
+			userID:      uid,
+			role:        "unknown",
+			expectedErr: "role not found",
+		},
+		{
+			name:        "Assign to Unknown User",
+			appPassword: appPassword,
+			userID:      unknownUserID,
+			role:        "viewer",
+			expectedErr: "user not found",
+		},
+		{
+			name:        "Assign without User",
+			appPassword: appPassword,
+			role:        "viewer",
+			expectedErr: "user_id is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.RolesClient.AssignRole(ctx, &ssoextv1.AssignRoleRequest{
+				AppName:     name,
+				AppPassword: tt.appPassword,
+				UserId:      tt.userID,
+				Role:        tt.role,
+			})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
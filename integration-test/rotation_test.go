@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/1kovalevskiy/sso/integration-test/suite"
+
+	ssov1 "github.com/1kovalevskiy/proto_sso/gen/go/sso"
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotateSigningKey_OldTokensStillVerify(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	name := gofakeit.UUID()
+	appID := AddNamedApp(t, ctx, st, name)
+
+	_, err := st.AppClient.SetSigningAlgorithm(ctx, &ssoextv1.SetSigningAlgorithmRequest{
+		Name:      name,
+		Password:  appPassword,
+		Algorithm: ssoextv1.SigningAlgorithm_SIGNING_ALGORITHM_EDDSA,
+	})
+	require.NoError(t, err)
+
+	_, _, oldToken := RegisterLogin(t, ctx, st, appID)
+
+	respRotate, err := st.AppClient.RotateSigningKey(ctx, &ssoextv1.RotateSigningKeyRequest{
+		Name:     name,
+		Password: appPassword,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, respRotate.GetKid())
+
+	_, _, newToken := RegisterLogin(t, ctx, st, appID)
+	assert.Equal(t, respRotate.GetKid(), keyID(t, newToken))
+	assert.NotEqual(t, keyID(t, oldToken), keyID(t, newToken))
+
+	for _, token := range []string{oldToken, newToken} {
+		resp, err := st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{Token: token})
+		require.NoError(t, err)
+		assert.True(t, resp.GetValid())
+
+		fetchJWK(t, st, keyID(t, token))
+	}
+}
+
+func TestAddApp_SecretChangeKeepsOldTokensValid(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	name := gofakeit.UUID()
+	appID := AddNamedApp(t, ctx, st, name)
+
+	_, _, oldToken := RegisterLogin(t, ctx, st, appID)
+
+	_, err := st.AuthClient.AddApp(ctx, &ssov1.AddAppRequest{
+		Name:     name,
+		Password: appPassword,
+		Secret:   "rotated-secret",
+		TtlHour:  appTTL,
+	})
+	require.NoError(t, err)
+
+	_, _, newToken := RegisterLogin(t, ctx, st, appID)
+	assert.NotEqual(t, keyID(t, oldToken), keyID(t, newToken))
+
+	for _, token := range []string{oldToken, newToken} {
+		resp, err := st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{Token: token})
+		require.NoError(t, err)
+		assert.True(t, resp.GetValid())
+	}
+}
@@ -0,0 +1,226 @@
+package tests
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/1kovalevskiy/sso/integration-test/suite"
+
+	ssov1 "github.com/1kovalevskiy/proto_sso/gen/go/sso"
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+}
+
+// AddNamedApp creates the app through the admin API, or reconfigures it
+// through the public AddApp if it already exists.
+func AddNamedApp(t *testing.T, ctx context.Context, st *suite.Suite, name string) int32 {
+	respCreate, err := st.AdminClient.CreateApp(ctx, &ssoextv1.CreateAppRequest{
+		Name:     name,
+		Password: appPassword,
+		Secret:   appSecret,
+		TtlHour:  appTTL,
+	})
+	if status.Code(err) != codes.AlreadyExists {
+		require.NoError(t, err)
+		require.NotEmpty(t, respCreate.GetAppId())
+		return respCreate.GetAppId()
+	}
+
+	respAppAdd, err := st.AuthClient.AddApp(ctx, &ssov1.AddAppRequest{
+		Name:     name,
+		Password: appPassword,
+		Secret:   appSecret,
+		TtlHour:  appTTL,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, respAppAdd.GetAppId())
+	return respAppAdd.GetAppId()
+}
+
+func TestAsymmetricSigning_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	tests := []struct {
+		name      string
+		algorithm ssoextv1.SigningAlgorithm
+		alg       string
+	}{
+		{
+			name:      "RS256",
+			algorithm: ssoextv1.SigningAlgorithm_SIGNING_ALGORITHM_RS256,
+			alg:       "RS256",
+		},
+		{
+			name:      "EdDSA",
+			algorithm: ssoextv1.SigningAlgorithm_SIGNING_ALGORITHM_EDDSA,
+			alg:       "EdDSA",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := gofakeit.UUID()
+			appID := AddNamedApp(t, ctx, st, name)
+
+			_, err := st.AppClient.SetSigningAlgorithm(ctx, &ssoextv1.SetSigningAlgorithmRequest{
+				Name:      name,
+				Password:  appPassword,
+				Algorithm: tt.algorithm,
+			})
+			require.NoError(t, err)
+
+			uid, _, token := RegisterLogin(t, ctx, st, appID)
+
+			key := fetchJWK(t, st, keyID(t, token))
+			assert.Equal(t, tt.alg, key.Alg)
+
+			tokenParsed, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+				return publicKey(t, key), nil
+			}, jwt.WithValidMethods([]string{tt.alg}))
+			require.NoError(t, err)
+
+			claims, ok := tokenParsed.Claims.(jwt.MapClaims)
+			require.True(t, ok)
+			assert.Equal(t, uid, int64(claims["uid"].(float64)))
+
+			resp, err := st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{Token: token})
+			require.NoError(t, err)
+			assert.True(t, resp.GetValid())
+		})
+	}
+}
+
+func TestAsymmetricSigning_RejectsSharedSecretToken(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	name := gofakeit.UUID()
+	appID := AddNamedApp(t, ctx, st, name)
+
+	_, err := st.AppClient.SetSigningAlgorithm(ctx, &ssoextv1.SetSigningAlgorithmRequest{
+		Name:      name,
+		Password:  appPassword,
+		Algorithm: ssoextv1.SigningAlgorithm_SIGNING_ALGORITHM_RS256,
+	})
+	require.NoError(t, err)
+
+	_, _, issued := RegisterLogin(t, ctx, st, appID)
+
+	token := signToken(t, appSecret, keyID(t, issued), int(appID), time.Now().Add(time.Hour))
+
+	resp, err := st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{Token: token})
+	require.NoError(t, err)
+	assert.False(t, resp.GetValid())
+	assert.Equal(t, ssoextv1.TokenStatus_TOKEN_STATUS_INVALID_SIGNATURE, resp.GetStatus())
+}
+
+func TestSetSigningAlgorithm_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	name := gofakeit.UUID()
+	AddNamedApp(t, ctx, st, name)
+
+	tests := []struct {
+		name        string
+		appName     string
+		password    string
+		algorithm   ssoextv1.SigningAlgorithm
+		expectedErr string
+	}{
+		{
+			name:        "Without Algorithm",
+			appName:     name,
+			password:    appPassword,
+			algorithm:   ssoextv1.SigningAlgorithm_SIGNING_ALGORITHM_UNSPECIFIED,
+			expectedErr: "algorithm is required",
+		},
+		{
+			name:        "With Wrong Password",
+			appName:     name,
+			password:    "wrong-password",
+			algorithm:   ssoextv1.SigningAlgorithm_SIGNING_ALGORITHM_RS256,
+			expectedErr: "invalid name or password",
+		},
+		{
+			name:        "Unknown App",
+			appName:     gofakeit.UUID(),
+			password:    appPassword,
+			algorithm:   ssoextv1.SigningAlgorithm_SIGNING_ALGORITHM_RS256,
+			expectedErr: "invalid name or password",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AppClient.SetSigningAlgorithm(ctx, &ssoextv1.SetSigningAlgorithmRequest{
+				Name:      tt.appName,
+				Password:  tt.password,
+				Algorithm: tt.algorithm,
+			})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func fetchJWK(t *testing.T, st *suite.Suite, kid string) jwk {
+	resp, err := http.Get(st.HTTPURL("/.well-known/jwks.json"))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&jwks))
+
+	for _, key := range jwks.Keys {
+		if key.Kid == kid {
+			return key
+		}
+	}
+
+	t.Fatalf("key %s not found in jwks", kid)
+	return jwk{}
+}
+
+func publicKey(t *testing.T, key jwk) interface{} {
+	switch key.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		require.NoError(t, err)
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		require.NoError(t, err)
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(key.X)
+		require.NoError(t, err)
+
+		return ed25519.PublicKey(x)
+	}
+
+	t.Fatalf("unsupported key type %s", key.Kty)
+	return nil
+}
@@ -8,16 +8,28 @@ import (
 	"time"
 
 	"github.com/1kovalevskiy/sso/config"
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"github.com/1kovalevskiy/sso/internal/interceptor"
 
 	ssov1 "github.com/1kovalevskiy/proto_sso/gen/go/sso"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 )
 
 type Suite struct {
-	*testing.T                  // Потребуется для вызова методов *testing.T внутри Suite
-	Cfg        *config.Config   // Конфигурация приложения
-	AuthClient ssov1.AuthClient // Клиент для взаимодействия с gRPC-сервером
+	*testing.T                               // Потребуется для вызова методов *testing.T внутри Suite
+	Cfg             *config.Config           // Конфигурация приложения
+	AuthClient      ssov1.AuthClient         // Клиент для взаимодействия с gRPC-сервером
+	TokenClient     ssoextv1.TokenClient     // Клиент для проверки токенов
+	AppClient       ssoextv1.AppClient       // Клиент для настройки приложений
+	RolesClient     ssoextv1.RolesClient     // Клиент для управления ролями
+	UsersClient     ssoextv1.UsersClient     // Клиент для получения данных пользователей
+	AuditClient     ssoextv1.AuditClient     // Клиент для чтения журнала аудита
+	AdminClient     ssoextv1.AppAdminClient  // Клиент admin API для управления приложениями
+	PassClient      ssoextv1.PasswordClient  // Клиент для смены и сброса пароля
+	MFAClient       ssoextv1.MFAClient       // Клиент для двухфакторной аутентификации
+	UserAdminClient ssoextv1.UserAdminClient // Клиент admin API для управления пользователями
 }
 
 const (
@@ -50,13 +62,55 @@ func New(t *testing.T) (context.Context, *Suite) {
 		t.Fatalf("grpc server connection failed: %v", err)
 	}
 
+	if cfg.AdminAPI.APIKey == "" {
+		t.Fatalf("ADMIN_API_KEY is required for integration tests")
+	}
+
+	adminCC, err := grpc.DialContext(context.Background(),
+		adminAddress(cfg),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(apiKeyInterceptor(cfg.AdminAPI.APIKey)))
+	if err != nil {
+		t.Fatalf("admin grpc server connection failed: %v", err)
+	}
+
 	return ctx, &Suite{
-		T:          t,
-		Cfg:        cfg,
-		AuthClient: ssov1.NewAuthClient(cc),
+		T:               t,
+		Cfg:             cfg,
+		AuthClient:      ssov1.NewAuthClient(cc),
+		TokenClient:     ssoextv1.NewTokenClient(cc),
+		AppClient:       ssoextv1.NewAppClient(cc),
+		RolesClient:     ssoextv1.NewRolesClient(cc),
+		UsersClient:     ssoextv1.NewUsersClient(cc),
+		AuditClient:     ssoextv1.NewAuditClient(cc),
+		AdminClient:     ssoextv1.NewAppAdminClient(adminCC),
+		PassClient:      ssoextv1.NewPasswordClient(cc),
+		MFAClient:       ssoextv1.NewMFAClient(cc),
+		UserAdminClient: ssoextv1.NewUserAdminClient(adminCC),
 	}
 }
 
+// HTTPURL returns the url of path on the app's HTTP server.
+func (s *Suite) HTTPURL(path string) string {
+	return "http://" + net.JoinHostPort(grpcHost, strconv.Itoa(s.Cfg.HTTP.Port)) + path
+}
+
 func grpcAddress(cfg *config.Config) string {
 	return net.JoinHostPort(grpcHost, strconv.Itoa(cfg.GRPC.Port))
 }
+
+// AdminAddress returns the address of the app's admin gRPC server.
+func (s *Suite) AdminAddress() string {
+	return adminAddress(s.Cfg)
+}
+
+func adminAddress(cfg *config.Config) string {
+	return net.JoinHostPort(grpcHost, strconv.Itoa(cfg.AdminAPI.Port))
+}
+
+// apiKeyInterceptor attaches the admin API key to every call.
+func apiKeyInterceptor(key string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(metadata.AppendToOutgoingContext(ctx, interceptor.APIKeyMetadata, key), method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/1kovalevskiy/sso/integration-test/suite"
+
+	ssov1 "github.com/1kovalevskiy/proto_sso/gen/go/sso"
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const unknownAppID = 1 << 30
+
+func RegisterUser(t *testing.T, ctx context.Context, st *suite.Suite) (int64, string, string) {
+	email := gofakeit.Email()
+	pass := randomFakePassword()
+
+	respReg, err := st.AuthClient.Register(ctx, &ssov1.RegisterRequest{
+		Email:    email,
+		Password: pass,
+	})
+	require.NoError(t, err)
+
+	return respReg.GetUserId(), email, pass
+}
+
+func Login(t *testing.T, ctx context.Context, st *suite.Suite, email string, pass string, appID int32) string {
+	respLogin, err := st.AuthClient.Login(ctx, &ssov1.LoginRequest{
+		Email:    email,
+		Password: pass,
+		AppId:    appID,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, respLogin.GetToken())
+
+	return respLogin.GetToken()
+}
+
+func RegisterLogin(t *testing.T, ctx context.Context, st *suite.Suite, appID int32) (int64, string, string) {
+	uid, email, pass := RegisterUser(t, ctx, st)
+
+	return uid, email, Login(t, ctx, st, email, pass, appID)
+}
+
+func TestValidateToken_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddApp(t, ctx, st)
+	uid, email, token := RegisterLogin(t, ctx, st, appID)
+
+	loginTime := time.Now()
+
+	resp, err := st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{Token: token})
+	require.NoError(t, err)
+	require.True(t, resp.GetValid())
+	assert.Equal(t, ssoextv1.TokenStatus_TOKEN_STATUS_VALID, resp.GetStatus())
+
+	claims := resp.GetClaims()
+	require.NotNil(t, claims)
+	assert.Equal(t, uid, claims.GetUserId())
+	assert.Equal(t, email, claims.GetEmail())
+	assert.Equal(t, appID, claims.GetAppId())
+
+	const deltaSeconds = 1
+
+	assert.InDelta(t, loginTime.Add(time.Hour).Unix(), claims.GetExpiresAt(), deltaSeconds)
+}
+
+func TestValidateToken_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddApp(t, ctx, st)
+	_, _, token := RegisterLogin(t, ctx, st, appID)
+	kid := keyID(t, token)
+
+	tests := []struct {
+		name           string
+		token          string
+		expectedStatus ssoextv1.TokenStatus
+	}{
+		{
+			name:           "Expired token",
+			token:          signToken(t, appSecret, kid, int(appID), time.Now().Add(-time.Hour)),
+			expectedStatus: ssoextv1.TokenStatus_TOKEN_STATUS_EXPIRED,
+		},
+		{
+			name:           "Token signed with wrong secret",
+			token:          signToken(t, "wrong-secret", kid, int(appID), time.Now().Add(time.Hour)),
+			expectedStatus: ssoextv1.TokenStatus_TOKEN_STATUS_INVALID_SIGNATURE,
+		},
+		{
+			name:           "Token for unknown app",
+			token:          signToken(t, appSecret, kid, unknownAppID, time.Now().Add(time.Hour)),
+			expectedStatus: ssoextv1.TokenStatus_TOKEN_STATUS_UNKNOWN_APP,
+		},
+		{
+			name:           "Token with unknown key",
+			token:          signToken(t, appSecret, gofakeit.UUID(), int(appID), time.Now().Add(time.Hour)),
+			expectedStatus: ssoextv1.TokenStatus_TOKEN_STATUS_UNKNOWN_KEY,
+		},
+		{
+			name:           "Malformed token",
+			token:          "not-a-token",
+			expectedStatus: ssoextv1.TokenStatus_TOKEN_STATUS_MALFORMED,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{Token: tt.token})
+			require.NoError(t, err)
+			assert.False(t, resp.GetValid())
+			assert.Equal(t, tt.expectedStatus, resp.GetStatus())
+			assert.Nil(t, resp.GetClaims())
+		})
+	}
+}
+
+func TestValidateToken_EmptyToken(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	_, err := st.TokenClient.ValidateToken(ctx, &ssoextv1.ValidateTokenRequest{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "token is required")
+}
+
+func signToken(t *testing.T, secret string, kid string, appID int, exp time.Time) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"uid":    1,
+		"email":  gofakeit.Email(),
+		"exp":    exp.Unix(),
+		"app_id": appID,
+	})
+	token.Header["kid"] = kid
+
+	tokenString, err := token.SignedString([]byte(secret))
+	require.NoError(t, err)
+
+	return tokenString
+}
+
+func keyID(t *testing.T, token string) string {
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	require.NoError(t, err)
+
+	kid, ok := parsed.Header["kid"].(string)
+	require.True(t, ok)
+
+	return kid
+}
@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/1kovalevskiy/sso/integration-test/suite"
+
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const unknownUserID = 1 << 40
+
+func TestGetUser_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddApp(t, ctx, st)
+	uid, email, token := RegisterLogin(t, ctx, st, appID)
+
+	respUser, err := st.UsersClient.GetUser(ctx, &ssoextv1.GetUserRequest{UserId: uid, Token: token})
+	require.NoError(t, err)
+	assert.Equal(t, uid, respUser.GetUserId())
+	assert.Equal(t, email, respUser.GetEmail())
+	assert.False(t, respUser.GetIsAdmin())
+
+	respAdmin, err := st.UsersClient.IsAdmin(ctx, &ssoextv1.IsAdminRequest{UserId: uid, Token: token})
+	require.NoError(t, err)
+	assert.False(t, respAdmin.GetIsAdmin())
+}
+
+func TestGetUser_Admin(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddApp(t, ctx, st)
+	uid, email, _ := RegisterLogin(t, ctx, st, appID)
+	adminToken := AdminLogin(t, ctx, st, appID)
+
+	respUser, err := st.UsersClient.GetUser(ctx, &ssoextv1.GetUserRequest{UserId: uid, Token: adminToken})
+	require.NoError(t, err)
+	assert.Equal(t, email, respUser.GetEmail())
+
+	respAdmin, err := st.UsersClient.IsAdmin(ctx, &ssoextv1.IsAdminRequest{UserId: uid, Token: adminToken})
+	require.NoError(t, err)
+	assert.False(t, respAdmin.GetIsAdmin())
+}
+
+func TestGetUser_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	appID := AddApp(t, ctx, st)
+	uid, _, token := RegisterLogin(t, ctx, st, appID)
+	_, _, otherToken := RegisterLogin(t, ctx, st, appID)
+	adminToken := AdminLogin(t, ctx, st, appID)
+
+	tests := []struct {
+		name         string
+		userID       int64
+		token        string
+		expectedCode codes.Code
+		expectedErr  string
+	}{
+		{
+			name:         "Without User ID",
+			userID:       0,
+			token:        token,
+			expectedCode: codes.InvalidArgument,
+			expectedErr:  "user_id is required",
+		},
+		{
+			name:         "Without Token",
+			userID:       uid,
+			token:        "",
+			expectedCode: codes.Unauthenticated,
+			expectedErr:  "token is required",
+		},
+		{
+			name:         "Invalid Token",
+			userID:       uid,
+			token:        "not-a-token",
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "Other User Token",
+			userID:       uid,
+			token:        otherToken,
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "Unknown User",
+			userID:       unknownUserID,
+			token:        adminToken,
+			expectedCode: codes.NotFound,
+			expectedErr:  "user not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.UsersClient.GetUser(ctx, &ssoextv1.GetUserRequest{UserId: tt.userID, Token: tt.token})
+			require.Error(t, err)
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Contains(t, err.Error(), tt.expectedErr)
+
+			_, err = st.UsersClient.IsAdmin(ctx, &ssoextv1.IsAdminRequest{UserId: tt.userID, Token: tt.token})
+			require.Error(t, err)
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
@@ -1,42 +1,313 @@
 package app
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/1kovalevskiy/sso/config"
 	error_ "github.com/1kovalevskiy/sso/internal/error"
 	authgrpc "github.com/1kovalevskiy/sso/internal/grpc"
+	authhttp "github.com/1kovalevskiy/sso/internal/http"
 	"github.com/1kovalevskiy/sso/internal/interceptor"
 	"github.com/1kovalevskiy/sso/internal/usecase"
+	"github.com/1kovalevskiy/sso/internal/usecase/notifier"
+	repomem "github.com/1kovalevskiy/sso/internal/usecase/repo_memory"
+	repopg "github.com/1kovalevskiy/sso/internal/usecase/repo_postgres"
 	repo "github.com/1kovalevskiy/sso/internal/usecase/repo_sqlite"
 	"github.com/1kovalevskiy/sso/pkg/grpcserver"
+	"github.com/1kovalevskiy/sso/pkg/httpserver"
 	"github.com/1kovalevskiy/sso/pkg/logger"
+	postgres_ "github.com/1kovalevskiy/sso/pkg/postgres"
 	sqlite_ "github.com/1kovalevskiy/sso/pkg/sqlite"
+
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
 )
 
 func Run(cfg *config.Config) {
 	const op = "internal - app - Run"
 	l := logger.New("local")
 
-	sqlite, err := sqlite_.New(cfg.SQL.URL, cfg.SQL.Timeout)
-	if err != nil {
-		l.Error(op+" - sql.New", error_.Err(err))
+	var authRepo usecase.AuthRepo
+	var queryTimeout time.Duration
+	switch cfg.SQL.Driver {
+	case config.DriverSQLite:
+		busyTimeout, err := time.ParseDuration(cfg.SQL.BusyTimeout)
+		if err != nil {
+			l.Error(op+" - time.ParseDuration", error_.Err(err))
+			return
+		}
+
+		sqlite, err := sqlite_.New(cfg.SQL.URL, cfg.SQL.Timeout,
+			sqlite_.JournalMode(cfg.SQL.JournalMode),
+			sqlite_.BusyTimeout(busyTimeout),
+			sqlite_.MaxOpenConns(cfg.SQL.MaxOpenConns),
+		)
+		if err != nil {
+			l.Error(op+" - sql.New", error_.Err(err))
+			return
+		}
+		defer sqlite.Close()
+		queryTimeout = sqlite.Timeout
+
+		authRepo, err = repo.New(sqlite)
+		if err != nil {
+			l.Error(op+" - repo.New", error_.Err(err))
+			return
+		}
+	case config.DriverPostgres:
+		pg, err := postgres_.New(cfg.SQL.URL, cfg.SQL.Timeout)
+		if err != nil {
+			l.Error(op+" - postgres.New", error_.Err(err))
+			return
+		}
+		defer pg.Close()
+		queryTimeout = pg.Timeout
+
+		authRepo = repopg.New(pg)
+	case config.DriverMemory:
+		l.Warn(op + " - using in-memory storage, data is lost on restart")
+
+		authRepo = repomem.New()
+	default:
+		l.Error(op + " - unknown sql driver: " + cfg.SQL.Driver)
 		return
 	}
-	defer sqlite.Close()
 
-	interceptor := interceptor.NewInterceptor(l)
+	rateLimits := make([]interceptor.RateLimit, 0, len(cfg.RateLimit.Methods))
+	for _, m := range cfg.RateLimit.Methods {
+		rateLimits = append(rateLimits, interceptor.RateLimit{
+			Method: m.Method,
+			Rate:   m.Rate,
+			Burst:  m.Burst,
+			Key:    m.Key,
+		})
+	}
+
+	adminInterceptor := interceptor.NewAdminInterceptor(l, cfg.AdminAPI.APIKey)
+
+	interceptor, err := interceptor.NewInterceptor(l, rateLimits...)
+	if err != nil {
+		l.Error(op+" - interceptor.NewInterceptor", error_.Err(err))
+		return
+	}
 
 	server := grpcserver.New(l, cfg.GRPC.Port, interceptor)
 
-	authUseCase := usecase.New(l, repo.New(sqlite))
+	refreshTTL, err := time.ParseDuration(cfg.Token.RefreshTTL)
+	if err != nil {
+		l.Error(op+" - time.ParseDuration", error_.Err(err))
+		return
+	}
+
+	keyOverlap, err := time.ParseDuration(cfg.Token.KeyOverlap)
+	if err != nil {
+		l.Error(op+" - time.ParseDuration", error_.Err(err))
+		return
+	}
+
+	sweepInterval, err := time.ParseDuration(cfg.Token.SweepInterval)
+	if err != nil {
+		l.Error(op+" - time.ParseDuration", error_.Err(err))
+		return
+	}
+
+	lockoutWindow, err := time.ParseDuration(cfg.Lockout.Window)
+	if err != nil {
+		l.Error(op+" - time.ParseDuration", error_.Err(err))
+		return
+	}
+
+	lockoutDuration, err := time.ParseDuration(cfg.Lockout.Duration)
+	if err != nil {
+		l.Error(op+" - time.ParseDuration", error_.Err(err))
+		return
+	}
+
+	lockoutBaseDelay, err := time.ParseDuration(cfg.Lockout.BaseDelay)
+	if err != nil {
+		l.Error(op+" - time.ParseDuration", error_.Err(err))
+		return
+	}
+
+	lockoutMaxDelay, err := time.ParseDuration(cfg.Lockout.MaxDelay)
+	if err != nil {
+		l.Error(op+" - time.ParseDuration", error_.Err(err))
+		return
+	}
+
+	slowQuery, err := time.ParseDuration(cfg.SQL.SlowQuery)
+	if err != nil {
+		l.Error(op+" - time.ParseDuration", error_.Err(err))
+		return
+	}
+
+	resetTTL, err := time.ParseDuration(cfg.Password.ResetTTL)
+	if err != nil {
+		l.Error(op+" - time.ParseDuration", error_.Err(err))
+		return
+	}
+
+	verificationTTL, err := time.ParseDuration(cfg.Email.VerificationTTL)
+	if err != nil {
+		l.Error(op+" - time.ParseDuration", error_.Err(err))
+		return
+	}
+
+	challengeTTL, err := time.ParseDuration(cfg.MFA.ChallengeTTL)
+	if err != nil {
+		l.Error(op+" - time.ParseDuration", error_.Err(err))
+		return
+	}
+
+	codeTTL, err := time.ParseDuration(cfg.OIDC.CodeTTL)
+	if err != nil {
+		l.Error(op+" - time.ParseDuration", error_.Err(err))
+		return
+	}
+
+	var passwordDenylist map[string]struct{}
+	if cfg.Password.DenylistPath != "" {
+		passwordDenylist, err = usecase.LoadPasswordDenylist(cfg.Password.DenylistPath)
+		if err != nil {
+			l.Error(op+" - usecase.LoadPasswordDenylist", error_.Err(err))
+			return
+		}
+	}
+
+	var hasher usecase.PasswordHasher
+	switch cfg.Password.Hasher {
+	case config.HasherArgon2id:
+		if cfg.Password.Argon2Memory == 0 || cfg.Password.Argon2Iterations == 0 || cfg.Password.Argon2Parallelism == 0 {
+			l.Error(op + " - password.argon2_memory, argon2_iterations and argon2_parallelism must be positive")
+			return
+		}
+
+		hasher = usecase.Argon2idHasher{
+			Memory:      cfg.Password.Argon2Memory,
+			Iterations:  cfg.Password.Argon2Iterations,
+			Parallelism: cfg.Password.Argon2Parallelism,
+			SaltLength:  16,
+			KeyLength:   32,
+		}
+	case config.HasherBcrypt:
+		if cfg.Password.BcryptCost < bcrypt.MinCost || cfg.Password.BcryptCost > bcrypt.MaxCost {
+			l.Error(fmt.Sprintf("%s - password.bcrypt_cost must be between %d and %d", op, bcrypt.MinCost, bcrypt.MaxCost))
+			return
+		}
+
+		hasher = usecase.BcryptHasher{Cost: cfg.Password.BcryptCost}
+	default:
+		l.Error(op + " - unknown password hasher: " + cfg.Password.Hasher)
+		return
+	}
+
+	var notify usecase.Notifier
+	switch cfg.Notifier.Type {
+	case config.NotifierLog:
+		notify = notifier.NewLog(l)
+	case config.NotifierFile:
+		if cfg.Notifier.Path == "" {
+			l.Error(op + " - notifier.path is required for the file notifier")
+			return
+		}
+
+		notify = notifier.NewFile(cfg.Notifier.Path)
+	default:
+		l.Error(op + " - unknown notifier type: " + cfg.Notifier.Type)
+		return
+	}
+
+	authUseCase := usecase.New(l, authRepo,
+		usecase.QueryTimeout(queryTimeout),
+		usecase.SlowQueryThreshold(slowQuery),
+		usecase.RefreshTokenTTL(refreshTTL),
+		usecase.SigningKeyOverlap(keyOverlap),
+		usecase.Lockout(usecase.LockoutPolicy{
+			MaxFailures:   cfg.Lockout.MaxFailures,
+			MaxIPFailures: cfg.Lockout.MaxIPFailures,
+			Window:        lockoutWindow,
+			Duration:      lockoutDuration,
+			DelayAfter:    cfg.Lockout.DelayAfter,
+			BaseDelay:     lockoutBaseDelay,
+			MaxDelay:      lockoutMaxDelay,
+		}),
+		usecase.Passwords(usecase.PasswordPolicy{
+			MinLength:     cfg.Password.MinLength,
+			MaxLength:     cfg.Password.MaxLength,
+			RequireLower:  cfg.Password.RequireLower,
+			RequireUpper:  cfg.Password.RequireUpper,
+			RequireDigit:  cfg.Password.RequireDigit,
+			RequireSymbol: cfg.Password.RequireSymbol,
+			Denylist:      passwordDenylist,
+		}),
+		usecase.Hasher(hasher),
+		usecase.WithNotifier(notify),
+		usecase.PasswordResetTTL(resetTTL),
+		usecase.EmailVerificationTTL(verificationTTL),
+		usecase.TOTPIssuer(cfg.MFA.Issuer),
+		usecase.MFAChallengeTTL(challengeTTL),
+		usecase.Issuer(cfg.OIDC.Issuer),
+		usecase.AuthorizationCodeTTL(codeTTL),
+	)
+
+	if _, err := authUseCase.NormalizeEmails(context.Background()); err != nil {
+		l.Error(op+" - authUseCase.NormalizeEmails", error_.Err(err))
+		return
+	}
+
+	if _, err := authUseCase.ReportEmailCollisions(context.Background()); err != nil {
+		l.Error(op+" - authUseCase.ReportEmailCollisions", error_.Err(err))
+		return
+	}
+
+	sweeperCtx, stopSweeper := context.WithCancel(context.Background())
+	defer stopSweeper()
+
+	go authUseCase.RunSweeper(sweeperCtx, sweepInterval)
 
 	server.Register(authgrpc.New(authUseCase))
+	server.Register(authgrpc.NewToken(authUseCase))
+	server.Register(authgrpc.NewApp(authUseCase))
+	server.Register(authgrpc.NewRoles(authUseCase))
+	server.Register(authgrpc.NewUsers(authUseCase))
+	server.Register(authgrpc.NewAudit(authUseCase))
+	server.Register(authgrpc.NewPassword(authUseCase))
+	server.Register(authgrpc.NewMFA(authUseCase))
 
 	server.Start()
 
+	var adminNotify <-chan error
+	if cfg.AdminAPI.APIKey == "" && cfg.AdminAPI.ClientCA == "" {
+		l.Warn(op + " - admin api disabled, set admin_api.api_key or admin_api.client_ca")
+	} else {
+		adminOpts := []grpc.ServerOption{adminInterceptor}
+		if cfg.AdminAPI.TLSCert != "" || cfg.AdminAPI.ClientCA != "" {
+			creds, err := grpcserver.TLS(cfg.AdminAPI.TLSCert, cfg.AdminAPI.TLSKey, cfg.AdminAPI.ClientCA)
+			if err != nil {
+				l.Error(op+" - grpcserver.TLS", error_.Err(err))
+				return
+			}
+			adminOpts = append(adminOpts, creds)
+		}
+
+		adminServer := grpcserver.New(l, cfg.AdminAPI.Port, adminOpts...)
+		adminServer.Register(authgrpc.NewAppAdmin(authUseCase))
+		adminServer.Register(authgrpc.NewUserAdmin(authUseCase))
+		adminServer.Start()
+		defer adminServer.Shutdown()
+
+		adminNotify = adminServer.Notify()
+	}
+
+	httpServer := httpserver.New(l, cfg.HTTP.Port, authhttp.New(l, authUseCase, authUseCase))
+
+	httpServer.Start()
+
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
 
@@ -45,7 +316,15 @@ func Run(cfg *config.Config) {
 		l.Info(op+" - signal: " + s.String())
 	case err = <-server.Notify():
 		l.Error(op+" - grpcServer.Notify:", error_.Err(err))
+	case err = <-adminNotify:
+		l.Error(op+" - adminServer.Notify:", error_.Err(err))
+	case err = <-httpServer.Notify():
+		l.Error(op+" - httpServer.Notify:", error_.Err(err))
 	}
 
 	server.Shutdown()
+
+	if err := httpServer.Shutdown(); err != nil {
+		l.Error(op+" - httpServer.Shutdown:", error_.Err(err))
+	}
 }
@@ -1,10 +1,22 @@
 package entity
 
-import "errors"
+import (
+	"crypto"
+	"errors"
+)
 
 var (
 	ErrAppExists   = errors.New("app already exists")
 	ErrAppNotFound = errors.New("app not found")
+	ErrAppDisabled = errors.New("app is disabled")
+
+	ErrUnsupportedSigningAlg = errors.New("unsupported signing algorithm")
+)
+
+const (
+	SigningAlgHS256 = "HS256"
+	SigningAlgRS256 = "RS256"
+	SigningAlgEdDSA = "EdDSA"
 )
 
 type App struct {
@@ -13,4 +25,24 @@ type App struct {
 	PassHash	[]byte
 	Secret		string
 	TTLHours	int
+	SigningAlg	string
+	Disabled	bool
+	// RequireVerifiedEmail refuses logins of users with unverified emails.
+	RequireVerifiedEmail	bool
+	// RedirectURIs are where the app may receive authorization codes as an
+	// OAuth client. Only GetApp loads them.
+	RedirectURIs	[]string
+	// Scopes can be granted to tokens the app receives for itself by the
+	// client credentials grant. Only GetApp loads them.
+	Scopes	[]string
+	// Claims maps custom access token claims to their templates, which may
+	// reference user attributes as {{key}}. Only GetApp loads them.
+	Claims	map[string]string
+}
+
+// JWK is a public verification key published in the JWKS document.
+type JWK struct {
+	KeyID		string
+	Algorithm	string
+	PublicKey	crypto.PublicKey
 }
@@ -0,0 +1,49 @@
+package entity
+
+import "time"
+
+const (
+	EventLoginSuccess = "login_success"
+	EventLoginFailure = "login_failure"
+	EventRegister     = "register"
+	EventAppCreated   = "app_created"
+	EventAppUpdated   = "app_updated"
+	EventAppDeleted   = "app_deleted"
+	EventAdminChanged = "admin_changed"
+
+	EventPasswordChanged        = "password_changed"
+	EventPasswordResetRequested = "password_reset_requested"
+	EventPasswordReset          = "password_reset"
+	EventEmailVerified          = "email_verified"
+	EventMFAEnabled             = "mfa_enabled"
+	EventMFADisabled            = "mfa_disabled"
+	EventRecoveryCodeUsed       = "recovery_code_used"
+	EventClientTokenIssued      = "client_token_issued"
+)
+
+// AuthEvent is a persisted record of a security relevant action. Zero AppID
+// and UserID mean the action was not tied to a known app or user.
+type AuthEvent struct {
+	ID        int
+	Type      string
+	CreatedAt time.Time
+	AppID     int
+	UserID    int
+	Email     string
+	PeerAddr  string
+	UserAgent string
+	Details   string
+}
+
+// AuthEventFilter narrows down ListAuthEvents. Zero fields match everything;
+// BeforeID continues a listing after the last event of the previous page.
+type AuthEventFilter struct {
+	UserID   int
+	AppID    int
+	Types    []string
+	Email    string
+	Since    time.Time
+	Until    time.Time
+	BeforeID int
+	Limit    int
+}
@@ -0,0 +1,10 @@
+package entity
+
+import "errors"
+
+var (
+	ErrReservedClaim        = errors.New("claim name is reserved")
+	ErrInvalidClaimName     = errors.New("invalid claim name")
+	ErrInvalidClaimTemplate = errors.New("invalid claim template")
+	ErrInvalidAttribute     = errors.New("invalid user attribute")
+)
@@ -0,0 +1,21 @@
+package entity
+
+import "context"
+
+// Client describes the caller of the current request.
+type Client struct {
+	Addr      string
+	UserAgent string
+}
+
+type clientKey struct{}
+
+func ContextWithClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+func ClientFromContext(ctx context.Context) Client {
+	client, _ := ctx.Value(clientKey{}).(Client)
+
+	return client
+}
@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidScope   = errors.New("scope is not granted to the app")
+	ErrMalformedScope = errors.New("scope must be printable ascii without spaces, quotes and backslashes")
+)
+
+// AppSubjectPrefix starts the sub claim of tokens issued to apps themselves
+// by the client credentials grant, followed by the app id.
+const AppSubjectPrefix = "app:"
+
+// ClientToken is an access token of an app acting on its own behalf. Scope
+// is the part of the app scopes the token was granted.
+type ClientToken struct {
+	AccessToken string
+	Scope       []string
+	ExpiresAt   time.Time
+}
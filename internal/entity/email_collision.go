@@ -0,0 +1,10 @@
+package entity
+
+// EmailCollision is a group of accounts whose emails normalize to the same
+// address. They were found when emails were normalized and have to be merged
+// or renamed by an operator, until then only the account already holding the
+// normalized address can log in.
+type EmailCollision struct {
+	Email   string
+	UserIDs []int
+}
@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrEmailVerificationNotFound = errors.New("email verification token is invalid or expired")
+
+// EmailVerification is an outstanding single-use token proving the ownership
+// of a user's email. Only the hash of the token is stored.
+type EmailVerification struct {
+	TokenHash []byte
+	UserID    int
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
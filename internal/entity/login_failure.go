@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrLoginThrottled = errors.New("too many login attempts")
+	ErrLoginLocked    = errors.New("login temporarily locked")
+)
+
+// LoginFailure counts failed logins for a key such as an email or a peer IP.
+type LoginFailure struct {
+	Key          string
+	Failures     int
+	LastFailedAt time.Time
+	LockedUntil  time.Time
+}
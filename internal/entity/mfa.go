@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrMFARequired          = errors.New("second factor required")
+	ErrMFAChallengeNotFound = errors.New("mfa challenge is invalid or expired")
+	ErrInvalidMFACode       = errors.New("mfa code is invalid")
+	ErrTOTPNotFound         = errors.New("totp is not enrolled")
+	ErrTOTPEnabled          = errors.New("totp is already enabled")
+)
+
+// TOTP is the authenticator app enrollment of a user. It guards logins once
+// Confirmed. LastStep is the time step of the last accepted code; codes of it
+// and earlier steps are refused, so an observed code can't be replayed.
+type TOTP struct {
+	UserID    int
+	Secret    []byte
+	Confirmed bool
+	LastStep  int64
+	CreatedAt time.Time
+}
+
+// TOTPEnrollment is handed to the user to set up an authenticator app, either
+// by the base32 Secret or by scanning URI as a QR code.
+type TOTPEnrollment struct {
+	Secret string
+	URI    string
+}
+
+// MFAChallenge is issued by a password login of a user with TOTP enabled and
+// exchanged for tokens together with a code. Only the hash of the challenge
+// token is stored. WithRefresh is set when the login asked for a refresh
+// token.
+type MFAChallenge struct {
+	TokenHash   []byte
+	UserID      int
+	AppID       int
+	WithRefresh bool
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// MFARequiredError is returned instead of tokens by a login of a user with
+// TOTP enabled. It matches ErrMFARequired.
+type MFARequiredError struct {
+	Challenge string
+	ExpiresAt time.Time
+}
+
+func (e *MFARequiredError) Error() string {
+	return ErrMFARequired.Error()
+}
+
+func (e *MFARequiredError) Is(target error) bool {
+	return target == ErrMFARequired
+}
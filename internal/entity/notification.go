@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+const (
+	NotificationPasswordReset     = "password_reset"
+	NotificationEmailVerification = "email_verification"
+)
+
+// Notification is a message with a secret token to be delivered to the owner
+// of Email, e.g. a password reset link.
+type Notification struct {
+	Type      string
+	Email     string
+	Token     string
+	ExpiresAt time.Time
+}
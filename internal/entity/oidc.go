@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidClient             = errors.New("client authentication failed")
+	ErrInvalidRedirectURI        = errors.New("redirect uri must be absolute and have no fragment")
+	ErrRedirectURINotAllowed     = errors.New("redirect uri is not registered for the client")
+	ErrAuthorizationCodeNotFound = errors.New("authorization code is invalid or expired")
+	ErrInvalidGrant              = errors.New("authorization code was issued for another client, redirect uri or code verifier")
+)
+
+// OpenID Connect scopes the provider understands. Other requested scopes are
+// dropped from the grant.
+const (
+	ScopeOpenID        = "openid"
+	ScopeEmail         = "email"
+	ScopeOfflineAccess = "offline_access"
+)
+
+// AuthorizationRequest is an OAuth 2.0 authorization code request of an app
+// acting as a client. ClientID is the app id. CodeChallenge is the S256 PKCE
+// challenge the token request has to prove.
+type AuthorizationRequest struct {
+	ClientID      int
+	RedirectURI   string
+	Scope         []string
+	Nonce         string
+	CodeChallenge string
+}
+
+// AuthorizationCode is issued to the redirect uri of an app after the user
+// logged in and exchanged for tokens once. Only the hash of the code is
+// stored. CreatedAt is the time of the login.
+//
+// Used is set once the code was exchanged. TokenID and TokenExpiresAt then
+// identify the access token issued for it and FamilyID its refresh tokens, so
+// they can be revoked when the code is presented again.
+type AuthorizationCode struct {
+	CodeHash       []byte
+	AppID          int
+	UserID         int
+	RedirectURI    string
+	CodeChallenge  string
+	Scope          string
+	Nonce          string
+	CreatedAt      time.Time
+	ExpiresAt      time.Time
+	Used           bool
+	TokenID        string
+	TokenExpiresAt time.Time
+	FamilyID       string
+}
+
+// OIDCTokens is the token response of an authorization code exchange. The
+// refresh token is only set if offline_access was granted, the ID token only
+// if openid was.
+type OIDCTokens struct {
+	TokenPair
+	IDToken string
+	Scope   string
+}
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrWeakPassword = errors.New("password does not satisfy the password policy")
+
+const (
+	PasswordRuleMinLength = "min_length"
+	PasswordRuleMaxLength = "max_length"
+	PasswordRuleLower     = "lowercase"
+	PasswordRuleUpper     = "uppercase"
+	PasswordRuleDigit     = "digit"
+	PasswordRuleSymbol    = "symbol"
+	PasswordRuleDenylist  = "denylist"
+)
+
+// PasswordViolation is a password policy rule a password failed.
+type PasswordViolation struct {
+	Rule        string
+	Description string
+}
+
+// PasswordPolicyError lists every rule a password failed. It matches
+// ErrWeakPassword.
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	descriptions := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		descriptions = append(descriptions, v.Description)
+	}
+
+	return ErrWeakPassword.Error() + ": " + strings.Join(descriptions, ", ")
+}
+
+func (e *PasswordPolicyError) Is(target error) bool {
+	return target == ErrWeakPassword
+}
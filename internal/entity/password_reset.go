@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrPasswordResetNotFound = errors.New("password reset token is invalid or expired")
+
+// PasswordReset is an outstanding single-use password reset token. Only the
+// hash of the token is stored.
+type PasswordReset struct {
+	TokenHash []byte
+	UserID    int
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
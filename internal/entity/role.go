@@ -0,0 +1,20 @@
+package entity
+
+import "errors"
+
+var (
+	ErrRoleNotFound = errors.New("role not found")
+)
+
+type Role struct {
+	ID          int
+	AppID       int
+	Name        string
+	Permissions []string
+}
+
+// Access is what a user is allowed to do within one app.
+type Access struct {
+	Roles       []string
+	Permissions []string
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrSigningKeyNotFound = errors.New("signing key not found")
+)
+
+// SigningKey is one of the keys an app signs tokens with. Only the key with a
+// zero RetiresAt signs new tokens; retired keys keep verifying until RetiresAt.
+type SigningKey struct {
+	ID         int
+	KeyID      string
+	AppID      int
+	Alg        string
+	Secret     string
+	PrivateKey []byte
+	PublicKey  []byte
+	CreatedAt  time.Time
+	RetiresAt  time.Time
+}
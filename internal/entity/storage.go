@@ -0,0 +1,6 @@
+package entity
+
+import "errors"
+
+// ErrQueryTimeout is returned when a storage call runs out of its deadline.
+var ErrQueryTimeout = errors.New("storage query timed out")
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrTokenExpired          = errors.New("token expired")
+	ErrTokenInvalidSignature = errors.New("token signature is invalid")
+	ErrTokenUnknownApp       = errors.New("token issued for unknown app")
+	ErrTokenUnknownKey       = errors.New("token signed with unknown or retired key")
+	ErrTokenMalformed        = errors.New("token is malformed")
+	ErrTokenRevoked          = errors.New("token revoked")
+	ErrTokenNotRevocable     = errors.New("token has no id and cannot be revoked")
+)
+
+// Claims of a validated access token. Tokens an app received for itself by
+// the client credentials grant have a Subject of AppSubjectPrefix and the app
+// id, no user and the granted scopes as Permissions.
+type Claims struct {
+	ID        string
+	Subject   string
+	UserID    int
+	Email     string
+	AppID     int
+	ExpiresAt time.Time
+	Access
+	// Custom holds the string claims rendered from the app claim templates.
+	Custom map[string]string
+}
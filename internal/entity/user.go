@@ -5,10 +5,15 @@ import "errors"
 var (
 	ErrUserExists   = errors.New("user already exists")
 	ErrUserNotFound = errors.New("user not found")
+
+	ErrInvalidEmail     = errors.New("invalid email")
+	ErrEmailNotVerified = errors.New("email is not verified")
 )
 
 type User struct {
 	ID			int
 	Email		string
 	PassHash	[]byte
+	IsAdmin		bool
+	EmailVerified	bool
 }
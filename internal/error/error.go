@@ -7,6 +7,7 @@ import (
 
 var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrPermissionDenied   = errors.New("permission denied")
 )
 
 func Err(err error) slog.Attr {
@@ -0,0 +1,91 @@
+package authgrpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type App interface {
+	SetSigningAlgorithm(ctx context.Context, name string, password string, alg string) (int, error)
+	RotateSigningKey(ctx context.Context, name string, password string) (string, error)
+}
+
+type appAPI struct {
+	ssoextv1.UnimplementedAppServer
+	app App
+}
+
+func NewApp(app App) func(gRPCServer *grpc.Server) {
+	return func(gRPCServer *grpc.Server) {
+		ssoextv1.RegisterAppServer(gRPCServer, &appAPI{app: app})
+	}
+}
+
+var signingAlgorithms = map[ssoextv1.SigningAlgorithm]string{
+	ssoextv1.SigningAlgorithm_SIGNING_ALGORITHM_HS256: entity.SigningAlgHS256,
+	ssoextv1.SigningAlgorithm_SIGNING_ALGORITHM_RS256: entity.SigningAlgRS256,
+	ssoextv1.SigningAlgorithm_SIGNING_ALGORITHM_EDDSA: entity.SigningAlgEdDSA,
+}
+
+func (s *appAPI) SetSigningAlgorithm(ctx context.Context, in *ssoextv1.SetSigningAlgorithmRequest) (*ssoextv1.SetSigningAlgorithmResponse, error) {
+	if in.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	if in.Password == "" {
+		return nil, status.Error(codes.InvalidArgument, "password is required")
+	}
+
+	alg, ok := signingAlgorithms[in.GetAlgorithm()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "algorithm is required")
+	}
+
+	id, err := s.app.SetSigningAlgorithm(ctx, in.GetName(), in.GetPassword(), alg)
+	if err != nil {
+		if errors.Is(err, entity.ErrLoginLocked) {
+			return nil, status.Error(codes.PermissionDenied, "too many failed attempts, try again later")
+		}
+
+		if errors.Is(err, error_.ErrInvalidCredentials) || errors.Is(err, entity.ErrAppNotFound) {
+			return nil, status.Error(codes.InvalidArgument, "invalid name or password")
+		}
+
+		return nil, internalError(err, "failed to set signing algorithm")
+	}
+
+	return &ssoextv1.SetSigningAlgorithmResponse{AppId: int32(id)}, nil
+}
+
+func (s *appAPI) RotateSigningKey(ctx context.Context, in *ssoextv1.RotateSigningKeyRequest) (*ssoextv1.RotateSigningKeyResponse, error) {
+	if in.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	if in.Password == "" {
+		return nil, status.Error(codes.InvalidArgument, "password is required")
+	}
+
+	kid, err := s.app.RotateSigningKey(ctx, in.GetName(), in.GetPassword())
+	if err != nil {
+		if errors.Is(err, entity.ErrLoginLocked) {
+			return nil, status.Error(codes.PermissionDenied, "too many failed attempts, try again later")
+		}
+
+		if errors.Is(err, error_.ErrInvalidCredentials) || errors.Is(err, entity.ErrAppNotFound) {
+			return nil, status.Error(codes.InvalidArgument, "invalid name or password")
+		}
+
+		return nil, internalError(err, "failed to rotate signing key")
+	}
+
+	return &ssoextv1.RotateSigningKeyResponse{Kid: kid}, nil
+}
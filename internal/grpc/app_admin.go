@@ -0,0 +1,252 @@
+package authgrpc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type AppAdmin interface {
+	CreateApp(ctx context.Context, name string, password string, secret string, ttlHour int) (int, error)
+	UpdateApp(ctx context.Context, id_ int, password string, secret string, ttlHour int) (entity.App, error)
+	DeleteApp(ctx context.Context, id_ int) error
+	SetAppDisabled(ctx context.Context, id_ int, disabled bool) error
+	SetAppEmailPolicy(ctx context.Context, id_ int, requireVerified bool) error
+	SetAppRedirectURIs(ctx context.Context, id_ int, uris []string) error
+	SetAppScopes(ctx context.Context, id_ int, scopes []string) error
+	SetAppClaims(ctx context.Context, id_ int, claims map[string]string) error
+	ListApps(ctx context.Context, afterID int, limit int) ([]entity.App, int, error)
+	GetApp(ctx context.Context, id_ int) (entity.App, error)
+}
+
+type appAdminAPI struct {
+	ssoextv1.UnimplementedAppAdminServer
+	admin AppAdmin
+}
+
+// NewAppAdmin registers the app management service. It must only be served
+// on the admin server.
+func NewAppAdmin(admin AppAdmin) func(gRPCServer *grpc.Server) {
+	return func(gRPCServer *grpc.Server) {
+		ssoextv1.RegisterAppAdminServer(gRPCServer, &appAdminAPI{admin: admin})
+	}
+}
+
+func (s *appAdminAPI) CreateApp(ctx context.Context, in *ssoextv1.CreateAppRequest) (*ssoextv1.CreateAppResponse, error) {
+	if in.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	if in.Password == "" {
+		return nil, status.Error(codes.InvalidArgument, "password is required")
+	}
+
+	if in.Secret == "" {
+		return nil, status.Error(codes.InvalidArgument, "secret is required")
+	}
+
+	if in.GetTtlHour() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "ttl_hour is required")
+	}
+
+	id, err := s.admin.CreateApp(ctx, in.GetName(), in.GetPassword(), in.GetSecret(), int(in.GetTtlHour()))
+	if err != nil {
+		if errors.Is(err, entity.ErrAppExists) {
+			return nil, status.Error(codes.AlreadyExists, "app already exists")
+		}
+
+		return nil, internalError(err, "failed to create app")
+	}
+
+	return &ssoextv1.CreateAppResponse{AppId: int32(id)}, nil
+}
+
+func (s *appAdminAPI) UpdateApp(ctx context.Context, in *ssoextv1.UpdateAppRequest) (*ssoextv1.UpdateAppResponse, error) {
+	if in.GetAppId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if in.Secret == "" {
+		return nil, status.Error(codes.InvalidArgument, "secret is required")
+	}
+
+	if in.GetTtlHour() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "ttl_hour is required")
+	}
+
+	app, err := s.admin.UpdateApp(ctx, int(in.GetAppId()), in.GetPassword(), in.GetSecret(), int(in.GetTtlHour()))
+	if err != nil {
+		return nil, appAdminError(err, "failed to update app")
+	}
+
+	return &ssoextv1.UpdateAppResponse{App: appInfo(app)}, nil
+}
+
+func (s *appAdminAPI) DeleteApp(ctx context.Context, in *ssoextv1.DeleteAppRequest) (*ssoextv1.DeleteAppResponse, error) {
+	if in.GetAppId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if err := s.admin.DeleteApp(ctx, int(in.GetAppId())); err != nil {
+		return nil, appAdminError(err, "failed to delete app")
+	}
+
+	return &ssoextv1.DeleteAppResponse{}, nil
+}
+
+func (s *appAdminAPI) SetAppDisabled(ctx context.Context, in *ssoextv1.SetAppDisabledRequest) (*ssoextv1.SetAppDisabledResponse, error) {
+	if in.GetAppId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if err := s.admin.SetAppDisabled(ctx, int(in.GetAppId()), in.GetDisabled()); err != nil {
+		return nil, appAdminError(err, "failed to change app state")
+	}
+
+	return &ssoextv1.SetAppDisabledResponse{}, nil
+}
+
+func (s *appAdminAPI) SetAppEmailPolicy(ctx context.Context, in *ssoextv1.SetAppEmailPolicyRequest) (*ssoextv1.SetAppEmailPolicyResponse, error) {
+	if in.GetAppId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if err := s.admin.SetAppEmailPolicy(ctx, int(in.GetAppId()), in.GetRequireVerifiedEmail()); err != nil {
+		return nil, appAdminError(err, "failed to change app email policy")
+	}
+
+	return &ssoextv1.SetAppEmailPolicyResponse{}, nil
+}
+
+func (s *appAdminAPI) SetAppRedirectURIs(ctx context.Context, in *ssoextv1.SetAppRedirectURIsRequest) (*ssoextv1.SetAppRedirectURIsResponse, error) {
+	if in.GetAppId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if err := s.admin.SetAppRedirectURIs(ctx, int(in.GetAppId()), in.GetRedirectUris()); err != nil {
+		if errors.Is(err, entity.ErrInvalidRedirectURI) {
+			return nil, status.Error(codes.InvalidArgument, "redirect uris must be absolute and have no fragment")
+		}
+
+		return nil, appAdminError(err, "failed to change app redirect uris")
+	}
+
+	return &ssoextv1.SetAppRedirectURIsResponse{}, nil
+}
+
+func (s *appAdminAPI) SetAppScopes(ctx context.Context, in *ssoextv1.SetAppScopesRequest) (*ssoextv1.SetAppScopesResponse, error) {
+	if in.GetAppId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if err := s.admin.SetAppScopes(ctx, int(in.GetAppId()), in.GetScopes()); err != nil {
+		if errors.Is(err, entity.ErrMalformedScope) {
+			return nil, status.Error(codes.InvalidArgument, entity.ErrMalformedScope.Error())
+		}
+
+		return nil, appAdminError(err, "failed to change app scopes")
+	}
+
+	return &ssoextv1.SetAppScopesResponse{}, nil
+}
+
+func (s *appAdminAPI) SetAppClaims(ctx context.Context, in *ssoextv1.SetAppClaimsRequest) (*ssoextv1.SetAppClaimsResponse, error) {
+	if in.GetAppId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if err := s.admin.SetAppClaims(ctx, int(in.GetAppId()), in.GetClaims()); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrReservedClaim):
+			return nil, status.Error(codes.InvalidArgument, "claim name is reserved")
+		case errors.Is(err, entity.ErrInvalidClaimName):
+			return nil, status.Error(codes.InvalidArgument, "claim names must be up to 64 letters, digits, _ - . or :")
+		case errors.Is(err, entity.ErrInvalidClaimTemplate):
+			return nil, status.Error(codes.InvalidArgument, "claim template is invalid")
+		}
+
+		return nil, appAdminError(err, "failed to change app claims")
+	}
+
+	return &ssoextv1.SetAppClaimsResponse{}, nil
+}
+
+func (s *appAdminAPI) ListApps(ctx context.Context, in *ssoextv1.ListAppsRequest) (*ssoextv1.ListAppsResponse, error) {
+	if in.GetPageSize() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "page_size must not be negative")
+	}
+
+	afterID := 0
+	if in.GetPageToken() != "" {
+		var err error
+		afterID, err = strconv.Atoi(in.GetPageToken())
+		if err != nil || afterID <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+
+	apps, next, err := s.admin.ListApps(ctx, afterID, int(in.GetPageSize()))
+	if err != nil {
+		return nil, internalError(err, "failed to list apps")
+	}
+
+	resp := &ssoextv1.ListAppsResponse{Apps: make([]*ssoextv1.AppInfo, 0, len(apps))}
+	for _, app := range apps {
+		resp.Apps = append(resp.Apps, appInfo(app))
+	}
+
+	if next != 0 {
+		resp.NextPageToken = strconv.Itoa(next)
+	}
+
+	return resp, nil
+}
+
+func (s *appAdminAPI) GetApp(ctx context.Context, in *ssoextv1.GetAppRequest) (*ssoextv1.GetAppResponse, error) {
+	if in.GetAppId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	app, err := s.admin.GetApp(ctx, int(in.GetAppId()))
+	if err != nil {
+		return nil, appAdminError(err, "failed to get app")
+	}
+
+	return &ssoextv1.GetAppResponse{App: appInfo(app)}, nil
+}
+
+func appAdminError(err error, internalMsg string) error {
+	if errors.Is(err, entity.ErrAppNotFound) {
+		return status.Error(codes.NotFound, "app not found")
+	}
+
+	return internalError(err, internalMsg)
+}
+
+func appInfo(app entity.App) *ssoextv1.AppInfo {
+	info := &ssoextv1.AppInfo{
+		Id:                   int32(app.ID),
+		Name:                 app.Name,
+		TtlHour:              int32(app.TTLHours),
+		Disabled:             app.Disabled,
+		RequireVerifiedEmail: app.RequireVerifiedEmail,
+		RedirectUris:         app.RedirectURIs,
+		Scopes:               app.Scopes,
+		Claims:               app.Claims,
+	}
+
+	for alg, name := range signingAlgorithms {
+		if name == app.SigningAlg {
+			info.SigningAlgorithm = alg
+		}
+	}
+
+	return info
+}
@@ -0,0 +1,102 @@
+package authgrpc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Audit interface {
+	ListAuthEvents(ctx context.Context, token string, filter entity.AuthEventFilter) ([]entity.AuthEvent, int, error)
+}
+
+type auditAPI struct {
+	ssoextv1.UnimplementedAuditServer
+	audit Audit
+}
+
+func NewAudit(audit Audit) func(gRPCServer *grpc.Server) {
+	return func(gRPCServer *grpc.Server) {
+		ssoextv1.RegisterAuditServer(gRPCServer, &auditAPI{audit: audit})
+	}
+}
+
+func (s *auditAPI) ListAuthEvents(ctx context.Context, in *ssoextv1.ListAuthEventsRequest) (*ssoextv1.ListAuthEventsResponse, error) {
+	if in.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if in.GetPageSize() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "page_size must not be negative")
+	}
+
+	filter := entity.AuthEventFilter{
+		UserID: int(in.GetUserId()),
+		AppID:  int(in.GetAppId()),
+		Types:  in.GetTypes(),
+		Email:  in.GetEmail(),
+		Limit:  int(in.GetPageSize()),
+	}
+
+	if in.GetSince() != 0 {
+		filter.Since = time.Unix(in.GetSince(), 0)
+	}
+
+	if in.GetUntil() != 0 {
+		filter.Until = time.Unix(in.GetUntil(), 0)
+	}
+
+	if in.GetPageToken() != "" {
+		beforeID, err := strconv.Atoi(in.GetPageToken())
+		if err != nil || beforeID <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+		filter.BeforeID = beforeID
+	}
+
+	events, next, err := s.audit.ListAuthEvents(ctx, in.GetToken(), filter)
+	if err != nil {
+		return nil, adminError(err, "failed to list auth events")
+	}
+
+	resp := &ssoextv1.ListAuthEventsResponse{
+		Events: make([]*ssoextv1.AuthEvent, 0, len(events)),
+	}
+	for _, event := range events {
+		resp.Events = append(resp.Events, &ssoextv1.AuthEvent{
+			Id:        int64(event.ID),
+			Type:      event.Type,
+			CreatedAt: event.CreatedAt.Unix(),
+			AppId:     int64(event.AppID),
+			UserId:    int64(event.UserID),
+			Email:     event.Email,
+			PeerAddr:  event.PeerAddr,
+			UserAgent: event.UserAgent,
+			Details:   event.Details,
+		})
+	}
+
+	if next != 0 {
+		resp.NextPageToken = strconv.Itoa(next)
+	}
+
+	return resp, nil
+}
+
+// adminError maps errors of calls authorized by an admin access token.
+func adminError(err error, internalMsg string) error {
+	if errors.Is(err, error_.ErrPermissionDenied) {
+		return status.Error(codes.PermissionDenied, "permission denied")
+	}
+
+	return revokeError(err, internalMsg)
+}
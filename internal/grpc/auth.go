@@ -3,18 +3,20 @@ package authgrpc
 import (
 	"context"
 	"errors"
+	"strconv"
 
 	"github.com/1kovalevskiy/sso/internal/entity"
 	error_ "github.com/1kovalevskiy/sso/internal/error"
 
 	ssov1 "github.com/1kovalevskiy/proto_sso/gen/go/sso"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 type Auth interface {
-	GetCreateApp(ctx context.Context, name string, password string, secret string, ttlHour int) (int, error)
+	ConfigureApp(ctx context.Context, name string, password string, secret string, ttlHour int) (int, error)
 	Login(ctx context.Context, email string, password string, appID int) (string, error)
 	RegisterNewUser(ctx context.Context, email string, pass string) (int, error)
 }
@@ -47,21 +49,30 @@ func (s *serverAPI) AddApp(ctx context.Context, in *ssov1.AddAppRequest) (*ssov1
 		return nil, status.Error(codes.InvalidArgument, "ttl_hour is required")
 	}
 
-	id, err := s.auth.GetCreateApp(ctx, in.GetName(), in.GetPassword(), in.GetSecret(), int(in.GetTtlHour()))
+	// Apps are created through the admin API, AddApp only reconfigures them.
+	id, err := s.auth.ConfigureApp(ctx, in.GetName(), in.GetPassword(), in.GetSecret(), int(in.GetTtlHour()))
 	if err != nil {
+		if errors.Is(err, entity.ErrLoginLocked) {
+			return nil, status.Error(codes.PermissionDenied, "too many failed attempts, try again later")
+		}
+
 		if errors.Is(err, error_.ErrInvalidCredentials) {
 			return nil, status.Error(codes.InvalidArgument, "invalid name or password")
 		}
 
-		return nil, status.Error(codes.Internal, "failed to login")
+		if errors.Is(err, entity.ErrAppNotFound) {
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, internalError(err, "failed to login")
 	}
 
 	return &ssov1.AddAppResponse{AppId: int32(id)}, nil
 }
 
 func (s *serverAPI) Login(ctx context.Context, in *ssov1.LoginRequest) (*ssov1.LoginResponse, error) {
-	if in.Email == "" {
-		return nil, status.Error(codes.InvalidArgument, "email is required")
+	if err := validateEmail(in.GetEmail()); err != nil {
+		return nil, err
 	}
 
 	if in.Password == "" {
@@ -74,19 +85,15 @@ func (s *serverAPI) Login(ctx context.Context, in *ssov1.LoginRequest) (*ssov1.L
 
 	token, err := s.auth.Login(ctx, in.GetEmail(), in.GetPassword(), int(in.GetAppId()))
 	if err != nil {
-		if errors.Is(err, error_.ErrInvalidCredentials) {
-			return nil, status.Error(codes.InvalidArgument, "invalid email or password")
-		}
-
-		return nil, status.Error(codes.Internal, "failed to login")
+		return nil, loginError(err)
 	}
 
 	return &ssov1.LoginResponse{Token: token}, nil
 }
 
 func (s *serverAPI) Register(ctx context.Context, in *ssov1.RegisterRequest) (*ssov1.RegisterResponse, error) {
-	if in.Email == "" {
-		return nil, status.Error(codes.InvalidArgument, "email is required")
+	if err := validateEmail(in.GetEmail()); err != nil {
+		return nil, err
 	}
 
 	if in.Password == "" {
@@ -99,8 +106,100 @@ func (s *serverAPI) Register(ctx context.Context, in *ssov1.RegisterRequest) (*s
 			return nil, status.Error(codes.AlreadyExists, "user already exists")
 		}
 
-		return nil, status.Error(codes.Internal, "failed to register user")
+		if errors.Is(err, entity.ErrInvalidEmail) {
+			return nil, status.Error(codes.InvalidArgument, "email is invalid")
+		}
+
+		var policyErr *entity.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			return nil, weakPasswordError("password", policyErr)
+		}
+
+		return nil, internalError(err, "failed to register user")
 	}
 
 	return &ssov1.RegisterResponse{UserId: int64(uid)}, nil
 }
+
+func loginError(err error) error {
+	var mfaErr *entity.MFARequiredError
+	if errors.As(err, &mfaErr) {
+		return mfaRequiredError(mfaErr)
+	}
+
+	switch {
+	case errors.Is(err, error_.ErrInvalidCredentials):
+		return status.Error(codes.InvalidArgument, "invalid email or password")
+	case errors.Is(err, entity.ErrLoginThrottled):
+		return status.Error(codes.ResourceExhausted, "too many login attempts, try again later")
+	case errors.Is(err, entity.ErrLoginLocked):
+		return status.Error(codes.PermissionDenied, "account is temporarily locked")
+	case errors.Is(err, entity.ErrAppDisabled):
+		return status.Error(codes.FailedPrecondition, "app is disabled")
+	case errors.Is(err, entity.ErrEmailNotVerified):
+		return status.Error(codes.FailedPrecondition, "email is not verified")
+	case errors.Is(err, entity.ErrInvalidEmail):
+		return status.Error(codes.InvalidArgument, "email is invalid")
+	}
+
+	return internalError(err, "failed to login")
+}
+
+// weakPasswordError reports the rules a password in field failed as
+// codes.InvalidArgument. Every rule is listed as a field violation of a
+// BadRequest detail and as metadata of an ErrorInfo detail keyed by rule name.
+func weakPasswordError(field string, err *entity.PasswordPolicyError) error {
+	st := status.New(codes.InvalidArgument, err.Error())
+
+	badRequest := &errdetails.BadRequest{}
+	info := &errdetails.ErrorInfo{
+		Reason:   "WEAK_PASSWORD",
+		Domain:   "sso",
+		Metadata: make(map[string]string, len(err.Violations)),
+	}
+	for _, v := range err.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: v.Description,
+		})
+		info.Metadata[v.Rule] = v.Description
+	}
+
+	detailed, detailsErr := st.WithDetails(badRequest, info)
+	if detailsErr != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+// mfaRequiredError reports a login waiting for a second factor as
+// codes.FailedPrecondition. The challenge to pass to MFA.VerifyLogin and its
+// expiry as unix seconds are in the metadata of an ErrorInfo detail.
+func mfaRequiredError(err *entity.MFARequiredError) error {
+	st := status.New(codes.FailedPrecondition, "second factor required")
+
+	detailed, detailsErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: "MFA_REQUIRED",
+		Domain: "sso",
+		Metadata: map[string]string{
+			"challenge":  err.Challenge,
+			"expires_at": strconv.FormatInt(err.ExpiresAt.Unix(), 10),
+		},
+	})
+	if detailsErr != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+// internalError reports err as codes.Internal with msg, unless the storage
+// ran out of time.
+func internalError(err error, msg string) error {
+	if errors.Is(err, entity.ErrQueryTimeout) {
+		return status.Error(codes.DeadlineExceeded, "storage timeout")
+	}
+
+	return status.Error(codes.Internal, msg)
+}
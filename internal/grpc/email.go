@@ -0,0 +1,23 @@
+package authgrpc
+
+import (
+	"net/mail"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// validateEmail accepts a bare RFC 5322 address like bob@example.com and
+// rejects display names, angle brackets and comments.
+func validateEmail(email string) error {
+	if email == "" {
+		return status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Name != "" || addr.Address != email {
+		return status.Error(codes.InvalidArgument, "email is invalid")
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package authgrpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	"github.com/1kovalevskiy/sso/internal/usecase"
+	repo "github.com/1kovalevskiy/sso/internal/usecase/repo_memory"
+	"github.com/1kovalevskiy/sso/pkg/logger/slogdiscard"
+
+	ssov1 "github.com/1kovalevskiy/proto_sso/gen/go/sso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLogin_IPLockout(t *testing.T) {
+	const (
+		email    = "user@example.com"
+		password = "s3cret-Passw0rd"
+	)
+
+	ctx := context.Background()
+	auth := usecase.New(slogdiscard.NewDiscardLogger(), repo.New(), usecase.Lockout(usecase.LockoutPolicy{
+		MaxIPFailures: 2,
+		Window:        time.Minute,
+		Duration:      time.Minute,
+	}))
+	server := &serverAPI{auth: auth}
+
+	appID, err := auth.CreateApp(ctx, "test-app", "app-password", "app-secret", 1)
+	require.NoError(t, err)
+	_, err = auth.RegisterNewUser(ctx, email, password)
+	require.NoError(t, err)
+
+	peer := entity.ContextWithClient(ctx, entity.Client{Addr: "203.0.113.7:5000"})
+	for _, wrong := range []string{"first@example.com", "second@example.com"} {
+		_, err := server.Login(peer, &ssov1.LoginRequest{Email: wrong, Password: password, AppId: int32(appID)})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+
+	_, err = server.Login(peer, &ssov1.LoginRequest{Email: email, Password: password, AppId: int32(appID)})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Contains(t, err.Error(), "account is temporarily locked")
+}
+
+func TestAddApp_IPLockout(t *testing.T) {
+	ctx := context.Background()
+	auth := usecase.New(slogdiscard.NewDiscardLogger(), repo.New(), usecase.Lockout(usecase.LockoutPolicy{
+		MaxIPFailures: 2,
+		Window:        time.Minute,
+		Duration:      time.Minute,
+	}))
+	server := &serverAPI{auth: auth}
+
+	_, err := auth.CreateApp(ctx, "test-app", "app-password", "app-secret", 1)
+	require.NoError(t, err)
+
+	peer := entity.ContextWithClient(ctx, entity.Client{Addr: "203.0.113.7:5000"})
+	for i := 0; i < 2; i++ {
+		_, err := server.AddApp(peer, &ssov1.AddAppRequest{Name: "test-app", Password: "wrong", Secret: "app-secret", TtlHour: 1})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+
+	_, err = server.AddApp(peer, &ssov1.AddAppRequest{Name: "test-app", Password: "app-password", Secret: "app-secret", TtlHour: 1})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Contains(t, err.Error(), "too many failed attempts")
+}
@@ -0,0 +1,124 @@
+package authgrpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type MFA interface {
+	EnrollTOTP(ctx context.Context, accessToken string) (entity.TOTPEnrollment, error)
+	ConfirmTOTP(ctx context.Context, accessToken string, code string) ([]string, error)
+	DisableTOTP(ctx context.Context, accessToken string, code string) error
+	VerifyMFALogin(ctx context.Context, challenge string, code string) (entity.TokenPair, error)
+}
+
+type mfaAPI struct {
+	ssoextv1.UnimplementedMFAServer
+	mfa MFA
+}
+
+func NewMFA(mfa MFA) func(gRPCServer *grpc.Server) {
+	return func(gRPCServer *grpc.Server) {
+		ssoextv1.RegisterMFAServer(gRPCServer, &mfaAPI{mfa: mfa})
+	}
+}
+
+func (s *mfaAPI) EnrollTOTP(ctx context.Context, in *ssoextv1.EnrollTOTPRequest) (*ssoextv1.EnrollTOTPResponse, error) {
+	if in.GetAccessToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "access_token is required")
+	}
+
+	enrollment, err := s.mfa.EnrollTOTP(ctx, in.GetAccessToken())
+	if err != nil {
+		return nil, mfaError(err, "failed to enroll totp")
+	}
+
+	return &ssoextv1.EnrollTOTPResponse{
+		Secret:     enrollment.Secret,
+		OtpauthUri: enrollment.URI,
+	}, nil
+}
+
+func (s *mfaAPI) ConfirmTOTP(ctx context.Context, in *ssoextv1.ConfirmTOTPRequest) (*ssoextv1.ConfirmTOTPResponse, error) {
+	if in.GetAccessToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "access_token is required")
+	}
+
+	if in.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	recoveryCodes, err := s.mfa.ConfirmTOTP(ctx, in.GetAccessToken(), in.GetCode())
+	if err != nil {
+		return nil, mfaError(err, "failed to confirm totp")
+	}
+
+	return &ssoextv1.ConfirmTOTPResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (s *mfaAPI) DisableTOTP(ctx context.Context, in *ssoextv1.DisableTOTPRequest) (*ssoextv1.DisableTOTPResponse, error) {
+	if in.GetAccessToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "access_token is required")
+	}
+
+	if in.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	if err := s.mfa.DisableTOTP(ctx, in.GetAccessToken(), in.GetCode()); err != nil {
+		return nil, mfaError(err, "failed to disable totp")
+	}
+
+	return &ssoextv1.DisableTOTPResponse{}, nil
+}
+
+func (s *mfaAPI) VerifyLogin(ctx context.Context, in *ssoextv1.VerifyLoginRequest) (*ssoextv1.VerifyLoginResponse, error) {
+	if in.GetChallenge() == "" {
+		return nil, status.Error(codes.InvalidArgument, "challenge is required")
+	}
+
+	if in.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	pair, err := s.mfa.VerifyMFALogin(ctx, in.GetChallenge(), in.GetCode())
+	if err != nil {
+		if errors.Is(err, entity.ErrMFAChallengeNotFound) {
+			return nil, status.Error(codes.Unauthenticated, "invalid or expired challenge")
+		}
+
+		return nil, mfaError(err, "failed to verify login")
+	}
+
+	return &ssoextv1.VerifyLoginResponse{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresAt:    pair.ExpiresAt.Unix(),
+	}, nil
+}
+
+func mfaError(err error, internalMsg string) error {
+	switch {
+	case errors.Is(err, entity.ErrInvalidMFACode):
+		return status.Error(codes.InvalidArgument, "invalid code")
+	case errors.Is(err, entity.ErrTOTPEnabled):
+		return status.Error(codes.FailedPrecondition, "totp is already enabled")
+	case errors.Is(err, entity.ErrTOTPNotFound):
+		return status.Error(codes.FailedPrecondition, "totp is not enrolled")
+	case errors.Is(err, entity.ErrLoginThrottled),
+		errors.Is(err, entity.ErrLoginLocked),
+		errors.Is(err, entity.ErrAppDisabled):
+		return loginError(err)
+	case errors.Is(err, entity.ErrUserNotFound):
+		return status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	return revokeError(err, internalMsg)
+}
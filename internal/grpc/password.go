@@ -0,0 +1,108 @@
+package authgrpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Password interface {
+	ChangePassword(ctx context.Context, email string, currentPassword string, newPassword string, mfaCode string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ConfirmPasswordReset(ctx context.Context, token string, newPassword string) error
+}
+
+type passwordAPI struct {
+	ssoextv1.UnimplementedPasswordServer
+	password Password
+}
+
+func NewPassword(password Password) func(gRPCServer *grpc.Server) {
+	return func(gRPCServer *grpc.Server) {
+		ssoextv1.RegisterPasswordServer(gRPCServer, &passwordAPI{password: password})
+	}
+}
+
+func (s *passwordAPI) ChangePassword(ctx context.Context, in *ssoextv1.ChangePasswordRequest) (*ssoextv1.ChangePasswordResponse, error) {
+	if err := validateEmail(in.GetEmail()); err != nil {
+		return nil, err
+	}
+
+	if in.GetCurrentPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "current_password is required")
+	}
+
+	if in.GetNewPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "new_password is required")
+	}
+
+	err := s.password.ChangePassword(ctx, in.GetEmail(), in.GetCurrentPassword(), in.GetNewPassword(), in.GetMfaCode())
+	if err != nil {
+		switch {
+		case errors.Is(err, error_.ErrInvalidCredentials),
+			errors.Is(err, entity.ErrLoginThrottled),
+			errors.Is(err, entity.ErrLoginLocked),
+			errors.Is(err, entity.ErrInvalidEmail):
+			return nil, loginError(err)
+		case errors.Is(err, entity.ErrInvalidMFACode):
+			return nil, status.Error(codes.InvalidArgument, "invalid mfa_code")
+		}
+
+		var policyErr *entity.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			return nil, weakPasswordError("new_password", policyErr)
+		}
+
+		return nil, internalError(err, "failed to change password")
+	}
+
+	return &ssoextv1.ChangePasswordResponse{}, nil
+}
+
+func (s *passwordAPI) RequestPasswordReset(ctx context.Context, in *ssoextv1.RequestPasswordResetRequest) (*ssoextv1.RequestPasswordResetResponse, error) {
+	if err := validateEmail(in.GetEmail()); err != nil {
+		return nil, err
+	}
+
+	if err := s.password.RequestPasswordReset(ctx, in.GetEmail()); err != nil {
+		if errors.Is(err, entity.ErrInvalidEmail) {
+			return nil, status.Error(codes.InvalidArgument, "email is invalid")
+		}
+
+		return nil, internalError(err, "failed to request password reset")
+	}
+
+	return &ssoextv1.RequestPasswordResetResponse{}, nil
+}
+
+func (s *passwordAPI) ConfirmPasswordReset(ctx context.Context, in *ssoextv1.ConfirmPasswordResetRequest) (*ssoextv1.ConfirmPasswordResetResponse, error) {
+	if in.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if in.GetNewPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "new_password is required")
+	}
+
+	if err := s.password.ConfirmPasswordReset(ctx, in.GetToken(), in.GetNewPassword()); err != nil {
+		if errors.Is(err, entity.ErrPasswordResetNotFound) {
+			return nil, status.Error(codes.InvalidArgument, "invalid or expired reset token")
+		}
+
+		var policyErr *entity.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			return nil, weakPasswordError("new_password", policyErr)
+		}
+
+		return nil, internalError(err, "failed to reset password")
+	}
+
+	return &ssoextv1.ConfirmPasswordResetResponse{}, nil
+}
@@ -0,0 +1,122 @@
+package authgrpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Roles interface {
+	SetRole(ctx context.Context, appName string, appPassword string, role string, permissions []string) (int, error)
+	AssignRole(ctx context.Context, appName string, appPassword string, userID int, role string) error
+	UnassignRole(ctx context.Context, appName string, appPassword string, userID int, role string) error
+}
+
+type rolesAPI struct {
+	ssoextv1.UnimplementedRolesServer
+	roles Roles
+}
+
+func NewRoles(roles Roles) func(gRPCServer *grpc.Server) {
+	return func(gRPCServer *grpc.Server) {
+		ssoextv1.RegisterRolesServer(gRPCServer, &rolesAPI{roles: roles})
+	}
+}
+
+func (s *rolesAPI) SetRole(ctx context.Context, in *ssoextv1.SetRoleRequest) (*ssoextv1.SetRoleResponse, error) {
+	if err := validateAppCredentials(in.GetAppName(), in.GetAppPassword()); err != nil {
+		return nil, err
+	}
+
+	if in.Role == "" {
+		return nil, status.Error(codes.InvalidArgument, "role is required")
+	}
+
+	for _, permission := range in.GetPermissions() {
+		if permission == "" || strings.ContainsAny(permission, " \t\n") {
+			return nil, status.Error(codes.InvalidArgument, "permissions must be non-empty and contain no whitespace")
+		}
+	}
+
+	id, err := s.roles.SetRole(ctx, in.GetAppName(), in.GetAppPassword(), in.GetRole(), in.GetPermissions())
+	if err != nil {
+		return nil, roleError(err, "failed to set role")
+	}
+
+	return &ssoextv1.SetRoleResponse{RoleId: int64(id)}, nil
+}
+
+func (s *rolesAPI) AssignRole(ctx context.Context, in *ssoextv1.AssignRoleRequest) (*ssoextv1.AssignRoleResponse, error) {
+	if err := validateAppCredentials(in.GetAppName(), in.GetAppPassword()); err != nil {
+		return nil, err
+	}
+
+	if in.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if in.Role == "" {
+		return nil, status.Error(codes.InvalidArgument, "role is required")
+	}
+
+	if err := s.roles.AssignRole(ctx, in.GetAppName(), in.GetAppPassword(), int(in.GetUserId()), in.GetRole()); err != nil {
+		return nil, roleError(err, "failed to assign role")
+	}
+
+	return &ssoextv1.AssignRoleResponse{}, nil
+}
+
+func (s *rolesAPI) UnassignRole(ctx context.Context, in *ssoextv1.UnassignRoleRequest) (*ssoextv1.UnassignRoleResponse, error) {
+	if err := validateAppCredentials(in.GetAppName(), in.GetAppPassword()); err != nil {
+		return nil, err
+	}
+
+	if in.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if in.Role == "" {
+		return nil, status.Error(codes.InvalidArgument, "role is required")
+	}
+
+	if err := s.roles.UnassignRole(ctx, in.GetAppName(), in.GetAppPassword(), int(in.GetUserId()), in.GetRole()); err != nil {
+		return nil, roleError(err, "failed to unassign role")
+	}
+
+	return &ssoextv1.UnassignRoleResponse{}, nil
+}
+
+func validateAppCredentials(name string, password string) error {
+	if name == "" {
+		return status.Error(codes.InvalidArgument, "app_name is required")
+	}
+
+	if password == "" {
+		return status.Error(codes.InvalidArgument, "app_password is required")
+	}
+
+	return nil
+}
+
+func roleError(err error, internalMsg string) error {
+	switch {
+	case errors.Is(err, error_.ErrInvalidCredentials), errors.Is(err, entity.ErrAppNotFound):
+		return status.Error(codes.InvalidArgument, "invalid app name or password")
+	case errors.Is(err, entity.ErrLoginLocked):
+		return status.Error(codes.PermissionDenied, "too many failed attempts, try again later")
+	case errors.Is(err, entity.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, entity.ErrRoleNotFound):
+		return status.Error(codes.NotFound, "role not found")
+	}
+
+	return internalError(err, internalMsg)
+}
@@ -0,0 +1,205 @@
+package authgrpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Token interface {
+	ValidateToken(ctx context.Context, token string) (entity.Claims, error)
+	LoginWithRefresh(ctx context.Context, email string, password string, appID int) (entity.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (entity.TokenPair, error)
+	RevokeToken(ctx context.Context, token string) error
+	Logout(ctx context.Context, accessToken string, refreshToken string) error
+	IssueClientToken(ctx context.Context, name string, password string, scope []string) (entity.ClientToken, error)
+}
+
+type tokenAPI struct {
+	ssoextv1.UnimplementedTokenServer
+	token Token
+}
+
+func NewToken(token Token) func(gRPCServer *grpc.Server) {
+	return func(gRPCServer *grpc.Server) {
+		ssoextv1.RegisterTokenServer(gRPCServer, &tokenAPI{token: token})
+	}
+}
+
+func (s *tokenAPI) ValidateToken(ctx context.Context, in *ssoextv1.ValidateTokenRequest) (*ssoextv1.ValidateTokenResponse, error) {
+	if in.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	claims, err := s.token.ValidateToken(ctx, in.GetToken())
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrTokenExpired):
+			return &ssoextv1.ValidateTokenResponse{Status: ssoextv1.TokenStatus_TOKEN_STATUS_EXPIRED}, nil
+		case errors.Is(err, entity.ErrTokenInvalidSignature):
+			return &ssoextv1.ValidateTokenResponse{Status: ssoextv1.TokenStatus_TOKEN_STATUS_INVALID_SIGNATURE}, nil
+		case errors.Is(err, entity.ErrTokenUnknownApp):
+			return &ssoextv1.ValidateTokenResponse{Status: ssoextv1.TokenStatus_TOKEN_STATUS_UNKNOWN_APP}, nil
+		case errors.Is(err, entity.ErrTokenUnknownKey):
+			return &ssoextv1.ValidateTokenResponse{Status: ssoextv1.TokenStatus_TOKEN_STATUS_UNKNOWN_KEY}, nil
+		case errors.Is(err, entity.ErrTokenRevoked):
+			return &ssoextv1.ValidateTokenResponse{Status: ssoextv1.TokenStatus_TOKEN_STATUS_REVOKED}, nil
+		case errors.Is(err, entity.ErrTokenMalformed):
+			return &ssoextv1.ValidateTokenResponse{Status: ssoextv1.TokenStatus_TOKEN_STATUS_MALFORMED}, nil
+		}
+
+		return nil, internalError(err, "failed to validate token")
+	}
+
+	return &ssoextv1.ValidateTokenResponse{
+		Valid:  true,
+		Status: ssoextv1.TokenStatus_TOKEN_STATUS_VALID,
+		Claims: &ssoextv1.Claims{
+			Id:          claims.ID,
+			UserId:      int64(claims.UserID),
+			Email:       claims.Email,
+			AppId:       int32(claims.AppID),
+			ExpiresAt:   claims.ExpiresAt.Unix(),
+			Roles:       claims.Roles,
+			Permissions: claims.Permissions,
+			Subject:     claims.Subject,
+			Custom:      claims.Custom,
+		},
+	}, nil
+}
+
+func (s *tokenAPI) Login(ctx context.Context, in *ssoextv1.LoginRequest) (*ssoextv1.LoginResponse, error) {
+	if err := validateEmail(in.GetEmail()); err != nil {
+		return nil, err
+	}
+
+	if in.Password == "" {
+		return nil, status.Error(codes.InvalidArgument, "password is required")
+	}
+
+	if in.GetAppId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	pair, err := s.token.LoginWithRefresh(ctx, in.GetEmail(), in.GetPassword(), int(in.GetAppId()))
+	if err != nil {
+		return nil, loginError(err)
+	}
+
+	return &ssoextv1.LoginResponse{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresAt:    pair.ExpiresAt.Unix(),
+	}, nil
+}
+
+func (s *tokenAPI) Refresh(ctx context.Context, in *ssoextv1.RefreshRequest) (*ssoextv1.RefreshResponse, error) {
+	if in.RefreshToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh_token is required")
+	}
+
+	pair, err := s.token.Refresh(ctx, in.GetRefreshToken())
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrRefreshTokenNotFound):
+			return nil, status.Error(codes.Unauthenticated, "invalid refresh token")
+		case errors.Is(err, entity.ErrRefreshTokenExpired):
+			return nil, status.Error(codes.Unauthenticated, "refresh token expired")
+		case errors.Is(err, entity.ErrRefreshTokenRevoked):
+			return nil, status.Error(codes.Unauthenticated, "refresh token revoked")
+		case errors.Is(err, entity.ErrRefreshTokenReused):
+			return nil, status.Error(codes.Unauthenticated, "refresh token reuse detected")
+		case errors.Is(err, entity.ErrAppDisabled):
+			return nil, status.Error(codes.FailedPrecondition, "app is disabled")
+		}
+
+		return nil, internalError(err, "failed to refresh token")
+	}
+
+	return &ssoextv1.RefreshResponse{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresAt:    pair.ExpiresAt.Unix(),
+	}, nil
+}
+
+func (s *tokenAPI) RevokeToken(ctx context.Context, in *ssoextv1.RevokeTokenRequest) (*ssoextv1.RevokeTokenResponse, error) {
+	if in.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if err := s.token.RevokeToken(ctx, in.GetToken()); err != nil {
+		return nil, revokeError(err, "failed to revoke token")
+	}
+
+	return &ssoextv1.RevokeTokenResponse{}, nil
+}
+
+func (s *tokenAPI) Logout(ctx context.Context, in *ssoextv1.LogoutRequest) (*ssoextv1.LogoutResponse, error) {
+	if in.AccessToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "access_token is required")
+	}
+
+	if err := s.token.Logout(ctx, in.GetAccessToken(), in.GetRefreshToken()); err != nil {
+		return nil, revokeError(err, "failed to logout")
+	}
+
+	return &ssoextv1.LogoutResponse{}, nil
+}
+
+func (s *tokenAPI) IssueClientToken(ctx context.Context, in *ssoextv1.IssueClientTokenRequest) (*ssoextv1.IssueClientTokenResponse, error) {
+	if in.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	if in.GetPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "password is required")
+	}
+
+	token, err := s.token.IssueClientToken(ctx, in.GetName(), in.GetPassword(), in.GetScopes())
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrInvalidClient):
+			return nil, status.Error(codes.Unauthenticated, "invalid app name or password")
+		case errors.Is(err, entity.ErrLoginLocked):
+			return nil, status.Error(codes.PermissionDenied, "too many failed attempts, try again later")
+		case errors.Is(err, entity.ErrInvalidScope):
+			return nil, status.Error(codes.PermissionDenied, "scope is not granted to the app")
+		case errors.Is(err, entity.ErrAppDisabled):
+			return nil, status.Error(codes.FailedPrecondition, "app is disabled")
+		}
+
+		return nil, internalError(err, "failed to issue client token")
+	}
+
+	return &ssoextv1.IssueClientTokenResponse{
+		AccessToken: token.AccessToken,
+		ExpiresAt:   token.ExpiresAt.Unix(),
+		Scopes:      token.Scope,
+	}, nil
+}
+
+func revokeError(err error, internalMsg string) error {
+	switch {
+	case errors.Is(err, entity.ErrTokenRevoked):
+		return status.Error(codes.Unauthenticated, "token revoked")
+	case errors.Is(err, entity.ErrTokenNotRevocable):
+		return status.Error(codes.FailedPrecondition, "token cannot be revoked")
+	case errors.Is(err, entity.ErrRefreshTokenNotFound):
+		return status.Error(codes.Unauthenticated, "invalid refresh token")
+	case errors.Is(err, entity.ErrTokenExpired),
+		errors.Is(err, entity.ErrTokenInvalidSignature),
+		errors.Is(err, entity.ErrTokenUnknownApp),
+		errors.Is(err, entity.ErrTokenUnknownKey),
+		errors.Is(err, entity.ErrTokenMalformed):
+		return status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	return internalError(err, internalMsg)
+}
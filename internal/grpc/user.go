@@ -0,0 +1,112 @@
+package authgrpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Users interface {
+	GetUser(ctx context.Context, accessToken string, userID int) (entity.User, error)
+	IsAdmin(ctx context.Context, accessToken string, userID int) (bool, error)
+	VerifyEmail(ctx context.Context, token string) error
+	RequestEmailVerification(ctx context.Context, email string) error
+}
+
+type usersAPI struct {
+	ssoextv1.UnimplementedUsersServer
+	users Users
+}
+
+func NewUsers(users Users) func(gRPCServer *grpc.Server) {
+	return func(gRPCServer *grpc.Server) {
+		ssoextv1.RegisterUsersServer(gRPCServer, &usersAPI{users: users})
+	}
+}
+
+func (s *usersAPI) GetUser(ctx context.Context, in *ssoextv1.GetUserRequest) (*ssoextv1.GetUserResponse, error) {
+	if in.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if in.GetToken() == "" {
+		return nil, status.Error(codes.Unauthenticated, "token is required")
+	}
+
+	user, err := s.users.GetUser(ctx, in.GetToken(), int(in.GetUserId()))
+	if err != nil {
+		return nil, usersError(err, "failed to get user")
+	}
+
+	return &ssoextv1.GetUserResponse{
+		UserId:        int64(user.ID),
+		Email:         user.Email,
+		IsAdmin:       user.IsAdmin,
+		EmailVerified: user.EmailVerified,
+	}, nil
+}
+
+func (s *usersAPI) IsAdmin(ctx context.Context, in *ssoextv1.IsAdminRequest) (*ssoextv1.IsAdminResponse, error) {
+	if in.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if in.GetToken() == "" {
+		return nil, status.Error(codes.Unauthenticated, "token is required")
+	}
+
+	isAdmin, err := s.users.IsAdmin(ctx, in.GetToken(), int(in.GetUserId()))
+	if err != nil {
+		return nil, usersError(err, "failed to check admin status")
+	}
+
+	return &ssoextv1.IsAdminResponse{IsAdmin: isAdmin}, nil
+}
+
+// usersError maps errors of lookups that need the token of the looked up user
+// or of an admin. Only admins get to tell unknown users apart.
+func usersError(err error, internalMsg string) error {
+	if errors.Is(err, entity.ErrUserNotFound) {
+		return status.Error(codes.NotFound, "user not found")
+	}
+
+	return adminError(err, internalMsg)
+}
+
+func (s *usersAPI) VerifyEmail(ctx context.Context, in *ssoextv1.VerifyEmailRequest) (*ssoextv1.VerifyEmailResponse, error) {
+	if in.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if err := s.users.VerifyEmail(ctx, in.GetToken()); err != nil {
+		if errors.Is(err, entity.ErrEmailVerificationNotFound) {
+			return nil, status.Error(codes.InvalidArgument, "invalid or expired verification token")
+		}
+
+		return nil, internalError(err, "failed to verify email")
+	}
+
+	return &ssoextv1.VerifyEmailResponse{}, nil
+}
+
+func (s *usersAPI) RequestEmailVerification(ctx context.Context, in *ssoextv1.RequestEmailVerificationRequest) (*ssoextv1.RequestEmailVerificationResponse, error) {
+	if err := validateEmail(in.GetEmail()); err != nil {
+		return nil, err
+	}
+
+	if err := s.users.RequestEmailVerification(ctx, in.GetEmail()); err != nil {
+		if errors.Is(err, entity.ErrInvalidEmail) {
+			return nil, status.Error(codes.InvalidArgument, "email is invalid")
+		}
+
+		return nil, internalError(err, "failed to request email verification")
+	}
+
+	return &ssoextv1.RequestEmailVerificationResponse{}, nil
+}
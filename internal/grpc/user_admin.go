@@ -0,0 +1,97 @@
+package authgrpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+
+	ssoextv1 "github.com/1kovalevskiy/sso/gen/go/ssoext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type UserAdmin interface {
+	SetUserAdmin(ctx context.Context, userID int, isAdmin bool) error
+	SetUserAttribute(ctx context.Context, userID int, key string, value string) error
+	DeleteUserAttribute(ctx context.Context, userID int, key string) error
+	GetUserAttributes(ctx context.Context, userID int) (map[string]string, error)
+}
+
+type userAdminAPI struct {
+	ssoextv1.UnimplementedUserAdminServer
+	admin UserAdmin
+}
+
+// NewUserAdmin registers the user management service. It must only be served
+// on the admin server.
+func NewUserAdmin(admin UserAdmin) func(gRPCServer *grpc.Server) {
+	return func(gRPCServer *grpc.Server) {
+		ssoextv1.RegisterUserAdminServer(gRPCServer, &userAdminAPI{admin: admin})
+	}
+}
+
+func (s *userAdminAPI) SetUserAdmin(ctx context.Context, in *ssoextv1.SetUserAdminRequest) (*ssoextv1.SetUserAdminResponse, error) {
+	if in.GetUserId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if err := s.admin.SetUserAdmin(ctx, int(in.GetUserId()), in.GetIsAdmin()); err != nil {
+		return nil, userAdminError(err, "failed to change user admin rights")
+	}
+
+	return &ssoextv1.SetUserAdminResponse{}, nil
+}
+
+func (s *userAdminAPI) SetUserAttribute(ctx context.Context, in *ssoextv1.SetUserAttributeRequest) (*ssoextv1.SetUserAttributeResponse, error) {
+	if in.GetUserId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if err := s.admin.SetUserAttribute(ctx, int(in.GetUserId()), in.GetKey(), in.GetValue()); err != nil {
+		return nil, userAdminError(err, "failed to set user attribute")
+	}
+
+	return &ssoextv1.SetUserAttributeResponse{}, nil
+}
+
+func (s *userAdminAPI) DeleteUserAttribute(ctx context.Context, in *ssoextv1.DeleteUserAttributeRequest) (*ssoextv1.DeleteUserAttributeResponse, error) {
+	if in.GetUserId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if in.GetKey() == "" {
+		return nil, status.Error(codes.InvalidArgument, "key is required")
+	}
+
+	if err := s.admin.DeleteUserAttribute(ctx, int(in.GetUserId()), in.GetKey()); err != nil {
+		return nil, userAdminError(err, "failed to delete user attribute")
+	}
+
+	return &ssoextv1.DeleteUserAttributeResponse{}, nil
+}
+
+func (s *userAdminAPI) GetUserAttributes(ctx context.Context, in *ssoextv1.GetUserAttributesRequest) (*ssoextv1.GetUserAttributesResponse, error) {
+	if in.GetUserId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	attributes, err := s.admin.GetUserAttributes(ctx, int(in.GetUserId()))
+	if err != nil {
+		return nil, userAdminError(err, "failed to get user attributes")
+	}
+
+	return &ssoextv1.GetUserAttributesResponse{Attributes: attributes}, nil
+}
+
+func userAdminError(err error, internalMsg string) error {
+	switch {
+	case errors.Is(err, entity.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, entity.ErrInvalidAttribute):
+		return status.Error(codes.InvalidArgument, "attribute keys must be up to 64 letters, digits, _ - . or : and values up to 1024 bytes")
+	}
+
+	return internalError(err, internalMsg)
+}
@@ -0,0 +1,84 @@
+package authhttp_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	authhttp "github.com/1kovalevskiy/sso/internal/http"
+	"github.com/1kovalevskiy/sso/internal/usecase"
+	repo "github.com/1kovalevskiy/sso/internal/usecase/repo_memory"
+	"github.com/1kovalevskiy/sso/pkg/logger/slogdiscard"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var csrfInput = regexp.MustCompile(`name="csrf_token" value="([^"]+)"`)
+
+func TestAuthorize_CSRF(t *testing.T) {
+	ctx := context.Background()
+	log := slogdiscard.NewDiscardLogger()
+	auth := usecase.New(log, repo.New())
+
+	appID, err := auth.CreateApp(ctx, "test-app", "app-password", "app-secret", 1)
+	require.NoError(t, err)
+	require.NoError(t, auth.SetAppRedirectURIs(ctx, appID, []string{"https://app.example.com/cb"}))
+
+	_, err = auth.RegisterNewUser(ctx, "user@example.com", "Passw0rd-1234")
+	require.NoError(t, err)
+
+	handler := authhttp.New(log, auth, auth)
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {strconv.Itoa(appID)},
+		"redirect_uri":          {"https://app.example.com/cb"},
+		"state":                 {"first"},
+		"code_challenge":        {"E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"},
+		"code_challenge_method": {"S256"},
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/authorize?"+params.Encode(), nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.True(t, cookies[0].HttpOnly)
+
+	match := csrfInput.FindStringSubmatch(rec.Body.String())
+	require.Len(t, match, 2)
+
+	post := func(state string, token string, cookie *http.Cookie) int {
+		form := url.Values{}
+		for key, values := range params {
+			form[key] = values
+		}
+		form.Set("state", state)
+		form.Set("csrf_token", token)
+		form.Set("email", "user@example.com")
+		form.Set("password", "Passw0rd-1234")
+
+		req := httptest.NewRequest(http.MethodPost, "/authorize", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, post("first", match[1], nil))
+	assert.Equal(t, http.StatusForbidden, post("first", "", cookies[0]))
+	// The token is only good for the authorization request it was served for.
+	assert.Equal(t, http.StatusForbidden, post("second", match[1], cookies[0]))
+	assert.Equal(t, http.StatusFound, post("first", match[1], cookies[0]))
+}
@@ -0,0 +1,78 @@
+package authhttp
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"net/http"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+)
+
+type JWKS interface {
+	JWKS(ctx context.Context) ([]entity.JWK, error)
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type jwksResponse struct {
+	Keys []jwk `json:"keys"`
+}
+
+func (h *handler) jwks(w http.ResponseWriter, r *http.Request) {
+	const op = "internal - http - handler.jwks"
+
+	keys, err := h.jwksProvider.JWKS(r.Context())
+	if err != nil {
+		h.log.Error("failed to get keys", slog.String("op", op), error_.Err(err))
+		http.Error(w, "failed to get keys", http.StatusInternalServerError)
+		return
+	}
+
+	resp := jwksResponse{Keys: make([]jwk, 0, len(keys))}
+	for _, key := range keys {
+		k := jwk{
+			Use: "sig",
+			Kid: key.KeyID,
+			Alg: key.Algorithm,
+		}
+
+		switch pub := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			k.Kty = "RSA"
+			k.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			k.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			k.Kty = "OKP"
+			k.Crv = "Ed25519"
+			k.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+
+		resp.Keys = append(resp.Keys, k)
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
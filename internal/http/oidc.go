@@ -0,0 +1,584 @@
+package authhttp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+)
+
+type OIDC interface {
+	CheckAuthorizationRequest(ctx context.Context, req entity.AuthorizationRequest) (entity.App, error)
+	Authorize(ctx context.Context, req entity.AuthorizationRequest, email string, password string) (string, error)
+	AuthorizeMFA(ctx context.Context, req entity.AuthorizationRequest, challenge string, code string) (string, error)
+	ExchangeAuthorizationCode(ctx context.Context, clientID int, clientSecret string, code string, redirectURI string, codeVerifier string) (entity.OIDCTokens, error)
+	ExchangeRefreshToken(ctx context.Context, clientID int, clientSecret string, refreshToken string) (entity.TokenPair, error)
+	UserInfo(ctx context.Context, accessToken string) (entity.User, error)
+	ExchangeClientCredentials(ctx context.Context, clientID int, clientSecret string, scope []string) (entity.ClientToken, error)
+	Issuer() string
+}
+
+// _authorizeParams are carried from the authorization request through the
+// login form.
+var _authorizeParams = []string{
+	"response_type", "client_id", "redirect_uri", "scope", "state", "nonce", "code_challenge", "code_challenge_method",
+}
+
+// _csrfCookie holds the secret the login form tokens of a browser are derived
+// from.
+const _csrfCookie = "sso_authorize_csrf"
+
+var _authorizePage = template.Must(template.New("authorize").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Sign in to {{.AppName}}</title></head>
+<body>
+<h1>Sign in to {{.AppName}}</h1>
+{{with .Error}}<p role="alert">{{.}}</p>{{end}}
+<form method="post" action="/authorize">
+<input type="hidden" name="csrf_token" value="{{.CSRFToken}}">
+{{range $name, $value := .Params}}<input type="hidden" name="{{$name}}" value="{{$value}}">
+{{end}}{{if .Challenge}}<input type="hidden" name="challenge" value="{{.Challenge}}">
+<label>Authentication or recovery code <input name="otp" autocomplete="one-time-code" required autofocus></label>
+{{else}}<label>Email <input type="email" name="email" value="{{.Email}}" autocomplete="username" required autofocus></label>
+<label>Password <input type="password" name="password" autocomplete="current-password" required></label>
+{{end}}<button type="submit">Continue</button>
+</form>
+</body>
+</html>
+`))
+
+var _errorPage = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Sign in failed</title></head>
+<body>
+<h1>Sign in failed</h1>
+<p>{{.}}</p>
+</body>
+</html>
+`))
+
+type authorizePage struct {
+	AppName   string
+	Params    map[string]string
+	CSRFToken string
+	Email     string
+	Challenge string
+	Error     string
+}
+
+type oauthError struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+type userInfoResponse struct {
+	Sub           string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+}
+
+type discoveryDocument struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+func (h *handler) discovery(w http.ResponseWriter, _ *http.Request) {
+	issuer := strings.TrimSuffix(h.oidc.Issuer(), "/")
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	writeJSON(w, http.StatusOK, discoveryDocument{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             issuer + "/authorize",
+		TokenEndpoint:                     issuer + "/token",
+		UserinfoEndpoint:                  issuer + "/userinfo",
+		JWKSURI:                           issuer + "/.well-known/jwks.json",
+		ScopesSupported:                   []string{entity.ScopeOpenID, entity.ScopeEmail, entity.ScopeOfflineAccess},
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code", "refresh_token", "client_credentials"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{entity.SigningAlgRS256, entity.SigningAlgEdDSA, entity.SigningAlgHS256},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		ClaimsSupported:                   []string{"iss", "sub", "aud", "exp", "iat", "auth_time", "nonce", "email", "email_verified"},
+	})
+}
+
+// authorize serves the login page of the authorization code flow on GET and
+// handles its password and TOTP steps on POST. A POST has to carry the CSRF
+// token the page was served with for the same authorization request. Errors
+// about the client or the redirect uri are shown to the user, other protocol
+// errors are sent to the redirect uri.
+func (h *handler) authorize(w http.ResponseWriter, r *http.Request) {
+	const op = "internal - http - handler.authorize"
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; frame-ancestors 'none'")
+
+	if err := r.ParseForm(); err != nil {
+		h.errorPage(w, http.StatusBadRequest, "The request is malformed.")
+		return
+	}
+
+	params := make(map[string]string, len(_authorizeParams))
+	for _, name := range _authorizeParams {
+		if value := r.Form.Get(name); value != "" {
+			params[name] = value
+		}
+	}
+
+	clientID, err := strconv.Atoi(params["client_id"])
+	if err != nil {
+		h.errorPage(w, http.StatusBadRequest, "The client_id is missing or invalid.")
+		return
+	}
+
+	req := entity.AuthorizationRequest{
+		ClientID:      clientID,
+		RedirectURI:   params["redirect_uri"],
+		Scope:         strings.Fields(params["scope"]),
+		Nonce:         params["nonce"],
+		CodeChallenge: params["code_challenge"],
+	}
+
+	app, err := h.oidc.CheckAuthorizationRequest(r.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrInvalidClient), errors.Is(err, entity.ErrAppDisabled):
+			h.errorPage(w, http.StatusBadRequest, "The client is unknown or disabled.")
+		case errors.Is(err, entity.ErrRedirectURINotAllowed):
+			h.errorPage(w, http.StatusBadRequest, "The redirect_uri is not registered for the client.")
+		default:
+			h.log.Error("failed to check authorization request", slog.String("op", op), error_.Err(err))
+			h.errorPage(w, http.StatusInternalServerError, "Something went wrong, please try again later.")
+		}
+		return
+	}
+
+	state := params["state"]
+
+	switch {
+	case params["response_type"] != "code":
+		redirectError(w, r, req.RedirectURI, state, "unsupported_response_type", "response_type must be code")
+		return
+	case req.CodeChallenge == "":
+		redirectError(w, r, req.RedirectURI, state, "invalid_request", "code_challenge is required")
+		return
+	case params["code_challenge_method"] != "S256":
+		redirectError(w, r, req.RedirectURI, state, "invalid_request", "code_challenge_method must be S256")
+		return
+	}
+
+	page := authorizePage{AppName: app.Name, Params: params}
+
+	if r.Method == http.MethodGet {
+		secret, err := h.csrfSecret(w, r)
+		if err != nil {
+			h.log.Error("failed to generate csrf secret", slog.String("op", op), error_.Err(err))
+			h.errorPage(w, http.StatusInternalServerError, "Something went wrong, please try again later.")
+			return
+		}
+
+		page.CSRFToken = csrfToken(secret, params)
+		h.authorizePage(w, http.StatusOK, page)
+		return
+	}
+
+	page.CSRFToken = r.PostForm.Get("csrf_token")
+	if !validCSRFToken(r, params, page.CSRFToken) {
+		h.log.Warn("invalid csrf token", slog.String("op", op), slog.Int("app_id", clientID))
+		h.errorPage(w, http.StatusForbidden, "The sign in form expired, please start over.")
+		return
+	}
+
+	var code string
+	if challenge := r.PostForm.Get("challenge"); challenge != "" {
+		page.Challenge = challenge
+		code, err = h.oidc.AuthorizeMFA(r.Context(), req, challenge, r.PostForm.Get("otp"))
+	} else {
+		page.Email = r.PostForm.Get("email")
+		code, err = h.oidc.Authorize(r.Context(), req, page.Email, r.PostForm.Get("password"))
+	}
+
+	if err != nil {
+		var mfaErr *entity.MFARequiredError
+
+		switch {
+		case errors.As(err, &mfaErr):
+			page.Challenge = mfaErr.Challenge
+			h.authorizePage(w, http.StatusOK, page)
+		case errors.Is(err, error_.ErrInvalidCredentials), errors.Is(err, entity.ErrInvalidEmail):
+			page.Error = "Invalid email or password."
+			h.authorizePage(w, http.StatusUnauthorized, page)
+		case errors.Is(err, entity.ErrInvalidMFACode):
+			page.Error = "Invalid code."
+			h.authorizePage(w, http.StatusUnauthorized, page)
+		case errors.Is(err, entity.ErrMFAChallengeNotFound), errors.Is(err, entity.ErrTOTPNotFound):
+			page.Challenge = ""
+			page.Error = "The sign in expired, please start over."
+			h.authorizePage(w, http.StatusUnauthorized, page)
+		case errors.Is(err, entity.ErrLoginLocked), errors.Is(err, entity.ErrLoginThrottled):
+			page.Error = "Too many attempts, please try again later."
+			h.authorizePage(w, http.StatusTooManyRequests, page)
+		case errors.Is(err, entity.ErrEmailNotVerified):
+			redirectError(w, r, req.RedirectURI, state, "access_denied", "email is not verified")
+		case errors.Is(err, entity.ErrInvalidClient),
+			errors.Is(err, entity.ErrAppDisabled),
+			errors.Is(err, entity.ErrAppNotFound),
+			errors.Is(err, entity.ErrRedirectURINotAllowed):
+			h.errorPage(w, http.StatusBadRequest, "The client is unknown or disabled.")
+		default:
+			h.log.Error("failed to authorize", slog.String("op", op), error_.Err(err))
+			redirectError(w, r, req.RedirectURI, state, "server_error", "")
+		}
+		return
+	}
+
+	query := url.Values{}
+	query.Set("code", code)
+	if state != "" {
+		query.Set("state", state)
+	}
+
+	redirect(w, r, req.RedirectURI, query)
+}
+
+// csrfSecret returns the CSRF secret of the browser, setting a new cookie if
+// it has none yet.
+func (h *handler) csrfSecret(w http.ResponseWriter, r *http.Request) (string, error) {
+	if cookie, err := r.Cookie(_csrfCookie); err == nil && cookie.Value != "" {
+		return cookie.Value, nil
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	secret := base64.RawURLEncoding.EncodeToString(b)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     _csrfCookie,
+		Value:    secret,
+		Path:     "/authorize",
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(h.oidc.Issuer(), "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return secret, nil
+}
+
+// csrfToken derives the login form token of an authorization request from
+// the CSRF secret of the browser. Another site can neither read the secret
+// nor reuse a token for a request of its own.
+func csrfToken(secret string, params map[string]string) string {
+	values := url.Values{}
+	for name, value := range params {
+		values.Set(name, value)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(values.Encode()))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func validCSRFToken(r *http.Request, params map[string]string, token string) bool {
+	cookie, err := r.Cookie(_csrfCookie)
+	if err != nil || cookie.Value == "" || token == "" {
+		return false
+	}
+
+	return hmac.Equal([]byte(csrfToken(cookie.Value, params)), []byte(token))
+}
+
+// token is the token endpoint. Clients authenticate with their app id and
+// password, by HTTP Basic auth or in the form.
+func (h *handler) token(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "malformed request body")
+		return
+	}
+
+	clientID, clientSecret, basic := r.BasicAuth()
+	if basic {
+		// RFC 6749 section 2.3.1 form-encodes the credentials before Basic auth.
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID = r.PostForm.Get("client_id")
+		clientSecret = r.PostForm.Get("client_secret")
+	}
+
+	id, err := strconv.Atoi(clientID)
+	if err != nil || clientSecret == "" {
+		invalidClient(w, basic)
+		return
+	}
+
+	switch grantType := r.PostForm.Get("grant_type"); grantType {
+	case "authorization_code":
+		h.exchangeCode(w, r, id, clientSecret, basic)
+	case "refresh_token":
+		h.exchangeRefreshToken(w, r, id, clientSecret, basic)
+	case "client_credentials":
+		h.clientCredentials(w, r, id, clientSecret, basic)
+	case "":
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "grant_type is required")
+	default:
+		writeOAuthError(w, http.StatusBadRequest, "unsupported_grant_type", "")
+	}
+}
+
+func (h *handler) exchangeCode(w http.ResponseWriter, r *http.Request, clientID int, clientSecret string, basic bool) {
+	const op = "internal - http - handler.exchangeCode"
+
+	code := r.PostForm.Get("code")
+	redirectURI := r.PostForm.Get("redirect_uri")
+	verifier := r.PostForm.Get("code_verifier")
+
+	switch {
+	case code == "":
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "code is required")
+		return
+	case redirectURI == "":
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "redirect_uri is required")
+		return
+	case verifier == "":
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "code_verifier is required")
+		return
+	}
+
+	tokens, err := h.oidc.ExchangeAuthorizationCode(r.Context(), clientID, clientSecret, code, redirectURI, verifier)
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrInvalidClient):
+			invalidClient(w, basic)
+		case errors.Is(err, entity.ErrLoginLocked):
+			writeOAuthError(w, http.StatusTooManyRequests, "invalid_client", "too many failed attempts, try again later")
+		case errors.Is(err, entity.ErrAppDisabled):
+			writeOAuthError(w, http.StatusBadRequest, "unauthorized_client", "client is disabled")
+		case errors.Is(err, entity.ErrAuthorizationCodeNotFound), errors.Is(err, entity.ErrInvalidGrant):
+			writeOAuthError(w, http.StatusBadRequest, "invalid_grant", "")
+		default:
+			h.log.Error("failed to exchange authorization code", slog.String("op", op), error_.Err(err))
+			writeOAuthError(w, http.StatusInternalServerError, "server_error", "")
+		}
+		return
+	}
+
+	resp := newTokenResponse(tokens.TokenPair)
+	resp.IDToken = tokens.IDToken
+	resp.Scope = tokens.Scope
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *handler) exchangeRefreshToken(w http.ResponseWriter, r *http.Request, clientID int, clientSecret string, basic bool) {
+	const op = "internal - http - handler.exchangeRefreshToken"
+
+	refreshToken := r.PostForm.Get("refresh_token")
+	if refreshToken == "" {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "refresh_token is required")
+		return
+	}
+
+	pair, err := h.oidc.ExchangeRefreshToken(r.Context(), clientID, clientSecret, refreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrInvalidClient):
+			invalidClient(w, basic)
+		case errors.Is(err, entity.ErrLoginLocked):
+			writeOAuthError(w, http.StatusTooManyRequests, "invalid_client", "too many failed attempts, try again later")
+		case errors.Is(err, entity.ErrAppDisabled):
+			writeOAuthError(w, http.StatusBadRequest, "unauthorized_client", "client is disabled")
+		case errors.Is(err, entity.ErrInvalidGrant),
+			errors.Is(err, entity.ErrRefreshTokenNotFound),
+			errors.Is(err, entity.ErrRefreshTokenExpired),
+			errors.Is(err, entity.ErrRefreshTokenRevoked),
+			errors.Is(err, entity.ErrRefreshTokenReused):
+			writeOAuthError(w, http.StatusBadRequest, "invalid_grant", "")
+		default:
+			h.log.Error("failed to refresh tokens", slog.String("op", op), error_.Err(err))
+			writeOAuthError(w, http.StatusInternalServerError, "server_error", "")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newTokenResponse(pair))
+}
+
+func (h *handler) clientCredentials(w http.ResponseWriter, r *http.Request, clientID int, clientSecret string, basic bool) {
+	const op = "internal - http - handler.clientCredentials"
+
+	token, err := h.oidc.ExchangeClientCredentials(r.Context(), clientID, clientSecret, strings.Fields(r.PostForm.Get("scope")))
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrInvalidClient):
+			invalidClient(w, basic)
+		case errors.Is(err, entity.ErrLoginLocked):
+			writeOAuthError(w, http.StatusTooManyRequests, "invalid_client", "too many failed attempts, try again later")
+		case errors.Is(err, entity.ErrAppDisabled):
+			writeOAuthError(w, http.StatusBadRequest, "unauthorized_client", "client is disabled")
+		case errors.Is(err, entity.ErrInvalidScope):
+			writeOAuthError(w, http.StatusBadRequest, "invalid_scope", "")
+		default:
+			h.log.Error("failed to issue client token", slog.String("op", op), error_.Err(err))
+			writeOAuthError(w, http.StatusInternalServerError, "server_error", "")
+		}
+		return
+	}
+
+	resp := newTokenResponse(entity.TokenPair{AccessToken: token.AccessToken, ExpiresAt: token.ExpiresAt})
+	resp.Scope = strings.Join(token.Scope, " ")
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// userInfo returns the claims of the owner of the bearer access token.
+func (h *handler) userInfo(w http.ResponseWriter, r *http.Request) {
+	const op = "internal - http - handler.userInfo"
+
+	w.Header().Set("Cache-Control", "no-store")
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="sso"`)
+		writeOAuthError(w, http.StatusUnauthorized, "invalid_request", "bearer token is required")
+		return
+	}
+
+	user, err := h.oidc.UserInfo(r.Context(), token)
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrTokenExpired),
+			errors.Is(err, entity.ErrTokenInvalidSignature),
+			errors.Is(err, entity.ErrTokenUnknownApp),
+			errors.Is(err, entity.ErrTokenUnknownKey),
+			errors.Is(err, entity.ErrTokenMalformed),
+			errors.Is(err, entity.ErrTokenRevoked),
+			errors.Is(err, entity.ErrUserNotFound):
+			w.Header().Set("WWW-Authenticate", `Bearer realm="sso", error="invalid_token"`)
+			writeOAuthError(w, http.StatusUnauthorized, "invalid_token", "")
+		default:
+			h.log.Error("failed to get user info", slog.String("op", op), error_.Err(err))
+			writeOAuthError(w, http.StatusInternalServerError, "server_error", "")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, userInfoResponse{
+		Sub:           strconv.Itoa(user.ID),
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+	})
+}
+
+func (h *handler) authorizePage(w http.ResponseWriter, code int, page authorizePage) {
+	const op = "internal - http - handler.authorizePage"
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	if err := _authorizePage.Execute(w, page); err != nil {
+		h.log.Error("failed to render page", slog.String("op", op), error_.Err(err))
+	}
+}
+
+func (h *handler) errorPage(w http.ResponseWriter, code int, message string) {
+	const op = "internal - http - handler.errorPage"
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	if err := _errorPage.Execute(w, message); err != nil {
+		h.log.Error("failed to render page", slog.String("op", op), error_.Err(err))
+	}
+}
+
+// redirect sends the browser to the registered redirectURI with query added
+// to the query it already has.
+func redirect(w http.ResponseWriter, r *http.Request, redirectURI string, query url.Values) {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	values := u.Query()
+	for name, value := range query {
+		values[name] = value
+	}
+	u.RawQuery = values.Encode()
+
+	http.Redirect(w, r, u.String(), http.StatusFound)
+}
+
+func redirectError(w http.ResponseWriter, r *http.Request, redirectURI string, state string, code string, description string) {
+	query := url.Values{}
+	query.Set("error", code)
+	if description != "" {
+		query.Set("error_description", description)
+	}
+	if state != "" {
+		query.Set("state", state)
+	}
+
+	redirect(w, r, redirectURI, query)
+}
+
+// clientAppID parses the app id a client authenticated with, answering
+// invalid_client if it is not one.
+func invalidClient(w http.ResponseWriter, basic bool) {
+	if basic {
+		w.Header().Set("WWW-Authenticate", `Basic realm="sso"`)
+	}
+
+	writeOAuthError(w, http.StatusUnauthorized, "invalid_client", "")
+}
+
+func writeOAuthError(w http.ResponseWriter, code int, err string, description string) {
+	writeJSON(w, code, oauthError{Error: err, Description: description})
+}
+
+func newTokenResponse(pair entity.TokenPair) tokenResponse {
+	return tokenResponse{
+		AccessToken:  pair.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(time.Until(pair.ExpiresAt).Seconds()),
+		RefreshToken: pair.RefreshToken,
+	}
+}
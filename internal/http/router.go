@@ -0,0 +1,55 @@
+package authhttp
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+type handler struct {
+	log          *slog.Logger
+	jwksProvider JWKS
+	oidc         OIDC
+}
+
+func New(log *slog.Logger, jwks JWKS, oidc OIDC) http.Handler {
+	h := &handler{
+		log:          log,
+		jwksProvider: jwks,
+		oidc:         oidc,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/jwks.json", allowMethods(h.jwks, http.MethodGet))
+	mux.HandleFunc("/.well-known/openid-configuration", allowMethods(h.discovery, http.MethodGet))
+	mux.HandleFunc("/authorize", allowMethods(h.authorize, http.MethodGet, http.MethodPost))
+	mux.HandleFunc("/token", allowMethods(h.token, http.MethodPost))
+	mux.HandleFunc("/userinfo", allowMethods(h.userInfo, http.MethodGet, http.MethodPost))
+
+	return withClient(mux)
+}
+
+// withClient stores the caller address and user agent in the request context,
+// like the gRPC client interceptor does.
+func withClient(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client := entity.Client{Addr: r.RemoteAddr, UserAgent: r.UserAgent()}
+		next.ServeHTTP(w, r.WithContext(entity.ContextWithClient(r.Context(), client)))
+	})
+}
+
+func allowMethods(next http.HandlerFunc, methods ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, m := range methods {
+			if r.Method == m {
+				next(w, r)
+				return
+			}
+		}
+
+		w.Header().Set("Allow", strings.Join(methods, ", "))
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
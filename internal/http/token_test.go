@@ -0,0 +1,58 @@
+package authhttp_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	authhttp "github.com/1kovalevskiy/sso/internal/http"
+	"github.com/1kovalevskiy/sso/internal/usecase"
+	repo "github.com/1kovalevskiy/sso/internal/usecase/repo_memory"
+	"github.com/1kovalevskiy/sso/pkg/logger/slogdiscard"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToken_PeerLockout(t *testing.T) {
+	log := slogdiscard.NewDiscardLogger()
+	auth := usecase.New(log, repo.New(), usecase.Lockout(usecase.LockoutPolicy{
+		MaxIPFailures: 2,
+		Window:        time.Minute,
+		Duration:      time.Minute,
+	}))
+	appID, err := auth.CreateApp(context.Background(), "test-app", "app-password", "app-secret", 1)
+	require.NoError(t, err)
+
+	handler := authhttp.New(log, auth, auth)
+	token := func(secret string) *httptest.ResponseRecorder {
+		form := url.Values{
+			"grant_type":    {"authorization_code"},
+			"client_id":     {strconv.Itoa(appID)},
+			"client_secret": {secret},
+			"code":          {"code"},
+			"redirect_uri":  {"https://app.example.com/cb"},
+			"code_verifier": {"verifier"},
+		}
+		req := httptest.NewRequest(http.MethodPost, "/token", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusUnauthorized, token("wrong").Code)
+	}
+
+	rec := token("app-password")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Contains(t, rec.Body.String(), "too many failed attempts")
+}
@@ -0,0 +1,29 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeyMetadata is the metadata key carrying the admin API key.
+const APIKeyMetadata = "x-api-key"
+
+// APIKeyUnaryServerInterceptor rejects calls that don't carry key in the
+// x-api-key metadata.
+func APIKeyUnaryServerInterceptor(key string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		values := md.Get(APIKeyMetadata)
+		if len(values) != 1 || subtle.ConstantTimeCompare([]byte(values[0]), []byte(key)) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "invalid api key")
+		}
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,35 @@
+package interceptor
+
+import (
+	"context"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// ClientUnaryServerInterceptor stores the caller address and user agent in the
+// request context so use cases can read them without depending on gRPC.
+func ClientUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(entity.ContextWithClient(ctx, clientFromContext(ctx)), req)
+	}
+}
+
+func clientFromContext(ctx context.Context) entity.Client {
+	var client entity.Client
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		client.Addr = p.Addr.String()
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("user-agent"); len(values) > 0 {
+			client.UserAgent = values[0]
+		}
+	}
+
+	return client
+}
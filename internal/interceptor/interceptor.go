@@ -11,7 +11,29 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-func NewInterceptor(log *slog.Logger) grpc.ServerOption {
+func NewInterceptor(log *slog.Logger, rateLimits ...RateLimit) (grpc.ServerOption, error) {
+	limiter, err := newRateLimiter(rateLimits)
+	if err != nil {
+		return nil, err
+	}
+
+	interceptors := append(baseInterceptors(log), limiter.RateLimitUnaryServerInterceptor())
+
+	return grpc.ChainUnaryInterceptor(interceptors...), nil
+}
+
+// NewAdminInterceptor builds the interceptor chain of the admin server. Calls
+// must carry apiKey unless it is empty, then the server relies on mTLS alone.
+func NewAdminInterceptor(log *slog.Logger, apiKey string) grpc.ServerOption {
+	interceptors := baseInterceptors(log)
+	if apiKey != "" {
+		interceptors = append(interceptors, APIKeyUnaryServerInterceptor(apiKey))
+	}
+
+	return grpc.ChainUnaryInterceptor(interceptors...)
+}
+
+func baseInterceptors(log *slog.Logger) []grpc.UnaryServerInterceptor {
 	loggingOpts := []logging.Option{
 		logging.WithLogOnEvents(
 			logging.StartCall,
@@ -28,11 +50,11 @@ func NewInterceptor(log *slog.Logger) grpc.ServerOption {
 		}),
 	}
 
-	interceptor := grpc.ChainUnaryInterceptor(
+	return []grpc.UnaryServerInterceptor{
 		recovery.UnaryServerInterceptor(recoveryOpts...),
+		ClientUnaryServerInterceptor(),
 		logging.UnaryServerInterceptor(InterceptorLogger(log), loggingOpts...),
-	)
-	return interceptor
+	}
 }
 
 func InterceptorLogger(l *slog.Logger) logging.Logger {
@@ -0,0 +1,197 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	RateLimitKeyPeer    = "peer"
+	RateLimitKeyApp     = "app"
+	RateLimitKeyPeerApp = "peer_app"
+
+	_bucketPruneEvery  = time.Minute
+	_retryAfterTrailer = "retry-after"
+)
+
+// RateLimit is a token bucket policy of a single gRPC method. Rate is the
+// number of calls per second refilled into a bucket holding up to Burst calls.
+type RateLimit struct {
+	Method string
+	Rate   float64
+	Burst  int
+	Key    string
+}
+
+type appRequest interface {
+	GetAppId() int32
+}
+
+type appRequest64 interface {
+	GetAppId() int64
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// refill is how long an empty bucket takes to fill up to its burst.
+	refill time.Duration
+}
+
+type rateLimiter struct {
+	mu        sync.Mutex
+	policies  map[string]RateLimit
+	buckets   map[string]*bucket
+	lastPrune time.Time
+}
+
+func newRateLimiter(limits []RateLimit) (*rateLimiter, error) {
+	policies := make(map[string]RateLimit, len(limits))
+	for _, limit := range limits {
+		switch {
+		case limit.Method == "":
+			return nil, fmt.Errorf("rate limit: method is required")
+		case limit.Rate <= 0 || limit.Burst <= 0:
+			return nil, fmt.Errorf("rate limit %s: rate and burst must be positive", limit.Method)
+		}
+
+		switch limit.Key {
+		case "":
+			limit.Key = RateLimitKeyPeer
+		case RateLimitKeyPeer, RateLimitKeyApp, RateLimitKeyPeerApp:
+		default:
+			return nil, fmt.Errorf("rate limit %s: unknown key %q", limit.Method, limit.Key)
+		}
+
+		policies[limit.Method] = limit
+	}
+
+	return &rateLimiter{
+		policies: policies,
+		buckets:  make(map[string]*bucket),
+	}, nil
+}
+
+// RateLimitUnaryServerInterceptor rejects calls exceeding the policy of their
+// method with codes.ResourceExhausted. The delay before the next call may
+// succeed is sent as RetryInfo details and in the retry-after trailer.
+func (l *rateLimiter) RateLimitUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		limit, ok := l.policies[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		wait := l.take(limit, info.FullMethod+"|"+rateLimitKey(ctx, req, limit.Key), time.Now())
+		if wait == 0 {
+			return handler(ctx, req)
+		}
+
+		seconds := int(math.Ceil(wait.Seconds()))
+		_ = grpc.SetTrailer(ctx, metadata.Pairs(_retryAfterTrailer, strconv.Itoa(seconds)))
+
+		st, err := status.New(codes.ResourceExhausted, "rate limit exceeded").
+			WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(wait)})
+		if err != nil {
+			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+		}
+
+		return nil, st.Err()
+	}
+}
+
+// take consumes a token from the bucket of key. It returns zero when the call
+// is allowed and otherwise how long it takes for a token to become available.
+func (l *rateLimiter) take(limit RateLimit, key string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prune(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{
+			tokens: float64(limit.Burst),
+			last:   now,
+			refill: time.Duration(float64(limit.Burst) / limit.Rate * float64(time.Second)),
+		}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+
+		return 0
+	}
+
+	return time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+}
+
+// prune forgets buckets idle long enough to have refilled completely. A new
+// bucket starts full, so dropping them changes no decision.
+func (l *rateLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < _bucketPruneEvery {
+		return
+	}
+	l.lastPrune = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= b.refill {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func rateLimitKey(ctx context.Context, req any, key string) string {
+	switch key {
+	case RateLimitKeyApp:
+		return "app:" + requestAppID(ctx, req)
+	case RateLimitKeyPeerApp:
+		return "peer:" + peerHost(ctx) + "|app:" + requestAppID(ctx, req)
+	}
+
+	return "peer:" + peerHost(ctx)
+}
+
+// requestAppID returns the app a request is addressed to, falling back to the
+// peer for requests that do not carry an app id.
+func requestAppID(ctx context.Context, req any) string {
+	switch r := req.(type) {
+	case appRequest:
+		if r.GetAppId() != 0 {
+			return strconv.FormatInt(int64(r.GetAppId()), 10)
+		}
+	case appRequest64:
+		if r.GetAppId() != 0 {
+			return strconv.FormatInt(r.GetAppId(), 10)
+		}
+	}
+
+	return "peer:" + peerHost(ctx)
+}
+
+func peerHost(ctx context.Context) string {
+	addr := entity.ClientFromContext(ctx).Addr
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
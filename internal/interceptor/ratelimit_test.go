@@ -0,0 +1,31 @@
+package interceptor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_PruneKeepsRefillingBuckets(t *testing.T) {
+	// A bucket of 2 calls refilled once every 10 minutes takes 20 minutes
+	// to fill up again.
+	limit := RateLimit{Method: "/test/Slow", Rate: 1.0 / 600, Burst: 2, Key: RateLimitKeyPeer}
+	l, err := newRateLimiter([]RateLimit{limit})
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.Zero(t, l.take(limit, "key", now))
+	require.Zero(t, l.take(limit, "key", now))
+	require.NotZero(t, l.take(limit, "key", now))
+
+	// Long idle, but the bucket is still short of a second call.
+	now = now.Add(15 * time.Minute)
+	require.Zero(t, l.take(limit, "key", now))
+	assert.NotZero(t, l.take(limit, "key", now))
+
+	now = now.Add(20 * time.Minute)
+	l.prune(now)
+	assert.Empty(t, l.buckets)
+}
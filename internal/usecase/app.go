@@ -8,9 +8,12 @@ import (
 
 	"github.com/1kovalevskiy/sso/internal/entity"
 	error_ "github.com/1kovalevskiy/sso/internal/error"
-	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	_defaultAppsLimit = 50
+	_maxAppsLimit     = 500
+)
 
 func (a *AuthUseCase) createApp(ctx context.Context, name string, pass string, secret string, ttlHour int) (int, error) {
 	const op = "internal - usecase - Auth.createApp"
@@ -22,7 +25,7 @@ func (a *AuthUseCase) createApp(ctx context.Context, name string, pass string, s
 
 	log.Info("registering app")
 
-	passHash, err := bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
+	passHash, err := a.hasher.Hash(pass)
 	if err != nil {
 		log.Error("failed to generate password hash", error_.Err(err))
 
@@ -36,9 +39,21 @@ func (a *AuthUseCase) createApp(ctx context.Context, name string, pass string, s
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
+	app := entity.App{ID: id, Name: name, Secret: secret, TTLHours: ttlHour, SigningAlg: entity.SigningAlgHS256}
+	if _, err := a.rotateSigningKey(ctx, app, app.SigningAlg); err != nil {
+		log.Error("failed to save signing key", error_.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventAppCreated, AppID: id, Details: name})
+
 	return id, nil
 }
 
+// getAppByName authenticates an app by its name and password. Unknown apps and
+// wrong passwords count against the peer lockout; a locked peer gets
+// entity.ErrLoginLocked without its password being checked.
 func (a *AuthUseCase) getAppByName(ctx context.Context, name string, password string) (int, error) {
 	const op = "internal - usecase - Auth.getAppByName"
 
@@ -49,10 +64,22 @@ func (a *AuthUseCase) getAppByName(ctx context.Context, name string, password st
 
 	log.Info("attempting to get app")
 
+	if err := a.checkPeerAllowed(ctx); err != nil {
+		if errors.Is(err, entity.ErrLoginLocked) {
+			log.Warn("app authentication of locked peer")
+		} else {
+			log.Error("failed to check lockout", error_.Err(err))
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
 	app, err := a.repo.GetAppByName(ctx, name)
 	if err != nil {
 		if errors.Is(err, entity.ErrAppNotFound) {
 			log.Warn("app not found", error_.Err(err))
+			a.registerPeerFailure(ctx, log)
+
 			return 0, entity.ErrAppNotFound
 		}
 
@@ -61,16 +88,15 @@ func (a *AuthUseCase) getAppByName(ctx context.Context, name string, password st
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if err := bcrypt.CompareHashAndPassword(app.PassHash, []byte(password)); err != nil {
-		a.log.Info("invalid credentials", error_.Err(err))
+	if !a.checkPassword(log, app.PassHash, password) {
+		log.Info("invalid credentials")
+		a.registerPeerFailure(ctx, log)
 
 		return 0, fmt.Errorf("%s: %w", op, error_.ErrInvalidCredentials)
 	}
 	log.Info("app successfully identify")
 
-
 	return app.ID, nil
-
 }
 
 func (a *AuthUseCase) updateApp(ctx context.Context, id_ int, secret string, ttlHour int) (int, error) {
@@ -88,19 +114,317 @@ func (a *AuthUseCase) updateApp(ctx context.Context, id_ int, secret string, ttl
 		log.Error("failed to save app", error_.Err(err))
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
+
+	app, err := a.repo.GetAppForUser(ctx, id_)
+	if err != nil {
+		log.Error("failed to get app", error_.Err(err))
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Only HS256 apps sign with the secret. The previous secret keeps
+	// verifying outstanding tokens during the overlap period.
+	if app.SigningAlg == entity.SigningAlgHS256 {
+		key, err := a.repo.GetActiveSigningKey(ctx, id_)
+		if err != nil && !errors.Is(err, entity.ErrSigningKeyNotFound) {
+			log.Error("failed to get signing key", error_.Err(err))
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err != nil || key.Secret != secret {
+			if _, err := a.rotateSigningKey(ctx, app, app.SigningAlg); err != nil {
+				log.Error("failed to rotate signing key", error_.Err(err))
+				return 0, fmt.Errorf("%s: %w", op, err)
+			}
+			log.Info("signing secret rotated")
+		}
+	}
+
+	a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventAppUpdated, AppID: id_, Details: "settings updated"})
+
 	return id, nil
 }
 
-func (a *AuthUseCase) GetCreateApp(ctx context.Context, name string, password string, secret string, ttlHour int) (int, error) {
-
+// ConfigureApp updates the secret and token TTL of an existing app identified
+// by its credentials. Apps are created through the admin API only.
+func (a *AuthUseCase) ConfigureApp(ctx context.Context, name string, password string, secret string, ttlHour int) (int, error) {
 	id, err := a.getAppByName(ctx, name, password)
-	if err != nil && errors.Is(err, entity.ErrAppNotFound) {
-		return a.createApp(ctx, name, password, secret, ttlHour)
-	}
 	if err != nil {
 		return 0, err
 	}
 
-	return a.updateApp(ctx, id, secret, ttlHour)
+	if _, err := a.updateApp(ctx, id, secret, ttlHour); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// CreateApp registers a new app. It fails with entity.ErrAppExists if the
+// name is taken.
+func (a *AuthUseCase) CreateApp(ctx context.Context, name string, password string, secret string, ttlHour int) (int, error) {
+	return a.createApp(ctx, name, password, secret, ttlHour)
+}
+
+// UpdateApp replaces the secret and token TTL of the app, and its password
+// unless password is empty.
+func (a *AuthUseCase) UpdateApp(ctx context.Context, id_ int, password string, secret string, ttlHour int) (entity.App, error) {
+	const op = "internal - usecase - Auth.UpdateApp"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("service_id", id_),
+	)
+
+	if _, err := a.GetApp(ctx, id_); err != nil {
+		return entity.App{}, err
+	}
+
+	if password != "" {
+		passHash, err := a.hasher.Hash(password)
+		if err != nil {
+			log.Error("failed to generate password hash", error_.Err(err))
+
+			return entity.App{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if _, err := a.repo.UpdateAppPassword(ctx, id_, passHash); err != nil {
+			log.Error("failed to save app password", error_.Err(err))
+
+			return entity.App{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventAppUpdated, AppID: id_, Details: "password changed"})
+	}
+
+	if _, err := a.updateApp(ctx, id_, secret, ttlHour); err != nil {
+		return entity.App{}, err
+	}
+
+	return a.GetApp(ctx, id_)
+}
+
+func (a *AuthUseCase) GetApp(ctx context.Context, id_ int) (entity.App, error) {
+	const op = "internal - usecase - Auth.GetApp"
+
+	app, err := a.repo.GetAppForUser(ctx, id_)
+	if err != nil {
+		if !errors.Is(err, entity.ErrAppNotFound) {
+			a.log.Error("failed to get app", slog.String("op", op), error_.Err(err))
+		}
+
+		return entity.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	app.RedirectURIs, err = a.repo.GetAppRedirectURIs(ctx, id_)
+	if err != nil {
+		a.log.Error("failed to get app redirect uris", slog.String("op", op), error_.Err(err))
+
+		return entity.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	app.Scopes, err = a.repo.GetAppScopes(ctx, id_)
+	if err != nil {
+		a.log.Error("failed to get app scopes", slog.String("op", op), error_.Err(err))
+
+		return entity.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	app.Claims, err = a.repo.GetAppClaims(ctx, id_)
+	if err != nil {
+		a.log.Error("failed to get app claims", slog.String("op", op), error_.Err(err))
+
+		return entity.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return app, nil
+}
+
+// ListApps returns up to limit apps with ids above afterID and the afterID of
+// the next page, or zero on the last page.
+func (a *AuthUseCase) ListApps(ctx context.Context, afterID int, limit int) ([]entity.App, int, error) {
+	const op = "internal - usecase - Auth.ListApps"
+
+	switch {
+	case limit <= 0:
+		limit = _defaultAppsLimit
+	case limit > _maxAppsLimit:
+		limit = _maxAppsLimit
+	}
+
+	apps, err := a.repo.ListApps(ctx, afterID, limit)
+	if err != nil {
+		a.log.Error("failed to list apps", slog.String("op", op), error_.Err(err))
+
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// A short page means there is nothing left to read.
+	next := 0
+	if len(apps) == limit {
+		next = apps[len(apps)-1].ID
+	}
+
+	return apps, next, nil
+}
+
+// SetAppDisabled disables or re-enables the app. Logins and token refreshes
+// of a disabled app are rejected with entity.ErrAppDisabled.
+func (a *AuthUseCase) SetAppDisabled(ctx context.Context, id_ int, disabled bool) error {
+	const op = "internal - usecase - Auth.SetAppDisabled"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("service_id", id_),
+		slog.Bool("disabled", disabled),
+	)
+
+	log.Info("changing app state")
+
+	count, err := a.repo.SetAppDisabled(ctx, id_, disabled)
+	if err != nil {
+		log.Error("failed to save app state", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		return fmt.Errorf("%s: %w", op, entity.ErrAppNotFound)
+	}
+
+	details := "enabled"
+	if disabled {
+		details = "disabled"
+	}
+	a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventAppUpdated, AppID: id_, Details: details})
+
+	return nil
+}
+
+// SetAppEmailPolicy sets whether logins to the app require a verified email.
+// Users with unverified emails are rejected with entity.ErrEmailNotVerified.
+func (a *AuthUseCase) SetAppEmailPolicy(ctx context.Context, id_ int, requireVerified bool) error {
+	const op = "internal - usecase - Auth.SetAppEmailPolicy"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("service_id", id_),
+		slog.Bool("require_verified_email", requireVerified),
+	)
+
+	log.Info("changing app email policy")
+
+	count, err := a.repo.SetAppEmailPolicy(ctx, id_, requireVerified)
+	if err != nil {
+		log.Error("failed to save app email policy", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		return fmt.Errorf("%s: %w", op, entity.ErrAppNotFound)
+	}
+
+	details := "verified email optional"
+	if requireVerified {
+		details = "verified email required"
+	}
+	a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventAppUpdated, AppID: id_, Details: details})
+
+	return nil
+}
+
+// SetAppRedirectURIs replaces the uris the app may receive authorization codes
+// at. Uris have to be absolute, without a fragment, and are matched exactly.
+func (a *AuthUseCase) SetAppRedirectURIs(ctx context.Context, id_ int, uris []string) error {
+	const op = "internal - usecase - Auth.SetAppRedirectURIs"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("service_id", id_),
+	)
+
+	log.Info("changing app redirect uris")
+
+	for _, uri := range uris {
+		if err := validateRedirectURI(uri); err != nil {
+			log.Info("invalid redirect uri", slog.String("uri", uri))
+
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if _, err := a.GetApp(ctx, id_); err != nil {
+		return err
+	}
+
+	if err := a.repo.SetAppRedirectURIs(ctx, id_, uris); err != nil {
+		log.Error("failed to save app redirect uris", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventAppUpdated, AppID: id_, Details: "redirect uris changed"})
+
+	return nil
+}
+
+// SetAppScopes replaces the scopes the app may request for its own tokens by
+// the client credentials grant.
+func (a *AuthUseCase) SetAppScopes(ctx context.Context, id_ int, scopes []string) error {
+	const op = "internal - usecase - Auth.SetAppScopes"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("service_id", id_),
+	)
+
+	log.Info("changing app scopes")
+
+	for _, scope := range scopes {
+		if !validScope(scope) {
+			log.Info("malformed scope", slog.String("scope", scope))
+
+			return fmt.Errorf("%s: %w", op, entity.ErrMalformedScope)
+		}
+	}
+
+	if _, err := a.GetApp(ctx, id_); err != nil {
+		return err
+	}
+
+	if err := a.repo.SetAppScopes(ctx, id_, scopes); err != nil {
+		log.Error("failed to save app scopes", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventAppUpdated, AppID: id_, Details: "scopes changed"})
+
+	return nil
+}
+
+// DeleteApp removes the app together with its signing keys, refresh tokens,
+// roles and revoked tokens. Access tokens it issued stop verifying
+// immediately. Auth events of the app are kept.
+func (a *AuthUseCase) DeleteApp(ctx context.Context, id_ int) error {
+	const op = "internal - usecase - Auth.DeleteApp"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("service_id", id_),
+	)
+
+	log.Info("deleting app")
+
+	count, err := a.repo.DeleteApp(ctx, id_)
+	if err != nil {
+		log.Error("failed to delete app", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		return fmt.Errorf("%s: %w", op, entity.ErrAppNotFound)
+	}
+
+	a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventAppDeleted, AppID: id_})
 
+	return nil
 }
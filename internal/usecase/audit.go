@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+)
+
+const (
+	_defaultAuthEventsLimit = 50
+	_maxAuthEventsLimit     = 500
+)
+
+// ListAuthEvents returns audit events matching filter, newest first, and the
+// BeforeID of the next page or zero on the last page. The caller must present
+// an access token of an admin user.
+func (a *AuthUseCase) ListAuthEvents(ctx context.Context, token string, filter entity.AuthEventFilter) ([]entity.AuthEvent, int, error) {
+	const op = "internal - usecase - Auth.ListAuthEvents"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	if err := a.authorizeAdmin(ctx, token); err != nil {
+		log.Warn("failed to authorize admin", error_.Err(err))
+
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	switch {
+	case filter.Limit <= 0:
+		filter.Limit = _defaultAuthEventsLimit
+	case filter.Limit > _maxAuthEventsLimit:
+		filter.Limit = _maxAuthEventsLimit
+	}
+
+	events, err := a.repo.ListAuthEvents(ctx, filter)
+	if err != nil {
+		log.Error("failed to list auth events", error_.Err(err))
+
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// A short page means there is nothing left to read.
+	next := 0
+	if len(events) == filter.Limit {
+		next = events[len(events)-1].ID
+	}
+
+	return events, next, nil
+}
+
+// authorizeAdmin checks that token is a valid access token of an admin user.
+func (a *AuthUseCase) authorizeAdmin(ctx context.Context, token string) error {
+	claims, err := a.ValidateToken(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return a.requireAdmin(ctx, claims)
+}
+
+// recordEvent stores an audit event enriched with the caller details. Failing
+// to store it never fails the audited action.
+func (a *AuthUseCase) recordEvent(ctx context.Context, event entity.AuthEvent) {
+	const op = "internal - usecase - Auth.recordEvent"
+
+	client := entity.ClientFromContext(ctx)
+	event.CreatedAt = time.Now()
+	event.PeerAddr = client.Addr
+	event.UserAgent = client.UserAgent
+
+	// The event is saved even when the caller has already gone away.
+	if _, err := a.repo.InsertAuthEvent(context.WithoutCancel(ctx), event); err != nil {
+		a.log.Error("failed to save auth event",
+			slog.String("op", op),
+			slog.String("type", event.Type),
+			error_.Err(err),
+		)
+	}
+}
@@ -2,7 +2,9 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/1kovalevskiy/sso/internal/entity"
@@ -12,9 +14,55 @@ import (
 
 type (
 	Auth interface {
-		GetCreateApp(ctx context.Context, name string, password string, secret string, ttlHour int) (int, error)
+		ConfigureApp(ctx context.Context, name string, password string, secret string, ttlHour int) (int, error)
+		CreateApp(ctx context.Context, name string, password string, secret string, ttlHour int) (int, error)
+		UpdateApp(ctx context.Context, id_ int, password string, secret string, ttlHour int) (entity.App, error)
+		GetApp(ctx context.Context, id_ int) (entity.App, error)
+		ListApps(ctx context.Context, afterID int, limit int) ([]entity.App, int, error)
+		SetAppDisabled(ctx context.Context, id_ int, disabled bool) error
+		SetAppEmailPolicy(ctx context.Context, id_ int, requireVerified bool) error
+		DeleteApp(ctx context.Context, id_ int) error
 		Login(ctx context.Context, email string, password string, appID int) (string, error)
+		LoginWithRefresh(ctx context.Context, email string, password string, appID int) (entity.TokenPair, error)
+		Refresh(ctx context.Context, refreshToken string) (entity.TokenPair, error)
 		RegisterNewUser(ctx context.Context, email string, pass string) (int, error)
+		GetUser(ctx context.Context, accessToken string, userID int) (entity.User, error)
+		IsAdmin(ctx context.Context, accessToken string, userID int) (bool, error)
+		SetUserAdmin(ctx context.Context, userID int, isAdmin bool) error
+		ValidateToken(ctx context.Context, token string) (entity.Claims, error)
+		SetSigningAlgorithm(ctx context.Context, name string, password string, alg string) (int, error)
+		RotateSigningKey(ctx context.Context, name string, password string) (string, error)
+		JWKS(ctx context.Context) ([]entity.JWK, error)
+		RevokeToken(ctx context.Context, token string) error
+		Logout(ctx context.Context, accessToken string, refreshToken string) error
+		SetRole(ctx context.Context, appName string, appPassword string, role string, permissions []string) (int, error)
+		AssignRole(ctx context.Context, appName string, appPassword string, userID int, role string) error
+		UnassignRole(ctx context.Context, appName string, appPassword string, userID int, role string) error
+		ListAuthEvents(ctx context.Context, token string, filter entity.AuthEventFilter) ([]entity.AuthEvent, int, error)
+		ChangePassword(ctx context.Context, email string, currentPassword string, newPassword string, mfaCode string) error
+		RequestPasswordReset(ctx context.Context, email string) error
+		ConfirmPasswordReset(ctx context.Context, token string, newPassword string) error
+		RequestEmailVerification(ctx context.Context, email string) error
+		VerifyEmail(ctx context.Context, token string) error
+		EnrollTOTP(ctx context.Context, accessToken string) (entity.TOTPEnrollment, error)
+		ConfirmTOTP(ctx context.Context, accessToken string, code string) ([]string, error)
+		DisableTOTP(ctx context.Context, accessToken string, code string) error
+		VerifyMFALogin(ctx context.Context, challenge string, code string) (entity.TokenPair, error)
+		SetAppRedirectURIs(ctx context.Context, id_ int, uris []string) error
+		CheckAuthorizationRequest(ctx context.Context, req entity.AuthorizationRequest) (entity.App, error)
+		Authorize(ctx context.Context, req entity.AuthorizationRequest, email string, password string) (string, error)
+		AuthorizeMFA(ctx context.Context, req entity.AuthorizationRequest, challenge string, code string) (string, error)
+		ExchangeAuthorizationCode(ctx context.Context, clientID int, clientSecret string, code string, redirectURI string, codeVerifier string) (entity.OIDCTokens, error)
+		ExchangeRefreshToken(ctx context.Context, clientID int, clientSecret string, refreshToken string) (entity.TokenPair, error)
+		UserInfo(ctx context.Context, accessToken string) (entity.User, error)
+		Issuer() string
+		SetAppScopes(ctx context.Context, id_ int, scopes []string) error
+		IssueClientToken(ctx context.Context, name string, password string, scope []string) (entity.ClientToken, error)
+		ExchangeClientCredentials(ctx context.Context, clientID int, clientSecret string, scope []string) (entity.ClientToken, error)
+		SetAppClaims(ctx context.Context, id_ int, claims map[string]string) error
+		SetUserAttribute(ctx context.Context, userID int, key string, value string) error
+		DeleteUserAttribute(ctx context.Context, userID int, key string) error
+		GetUserAttributes(ctx context.Context, userID int) (map[string]string, error)
 	}
 
 	AuthRepo interface {
@@ -24,38 +72,211 @@ type (
 		GetAppByName(ctx context.Context, name string) (entity.App, error)
 		InsertApp(ctx context.Context, name string, passHash []byte, secret string, ttlHour int) (int, error)
 		UpdateApp(ctx context.Context, id_ int, secret string, ttlHour int) (int, error)
+		UpdateAppPassword(ctx context.Context, id_ int, passHash []byte) (int, error)
+		DeleteApp(ctx context.Context, id_ int) (int, error)
+		SetAppDisabled(ctx context.Context, id_ int, disabled bool) (int, error)
+		SetAppEmailPolicy(ctx context.Context, id_ int, requireVerified bool) (int, error)
+		ListApps(ctx context.Context, afterID int, limit int) ([]entity.App, error)
+		GetUserByID(ctx context.Context, id int) (entity.User, error)
+		IsAdmin(ctx context.Context, userID int) (bool, error)
+		SetUserAdmin(ctx context.Context, userID int, isAdmin bool) (int, error)
+		UpdateUserPassword(ctx context.Context, userID int, passHash []byte) (int, error)
+		RehashUserPassword(ctx context.Context, userID int, oldHash []byte, newHash []byte) (int, error)
+		ListEmailCollisions(ctx context.Context) ([]entity.EmailCollision, error)
+		ListUnnormalizedEmails(ctx context.Context) ([]entity.User, error)
+		UpdateUserEmail(ctx context.Context, userID int, email string) (int, error)
+		AddEmailCollision(ctx context.Context, email string, userID int) error
+		InsertRefreshToken(ctx context.Context, token entity.RefreshToken) (int, error)
+		GetRefreshToken(ctx context.Context, tokenHash []byte) (entity.RefreshToken, error)
+		RotateRefreshToken(ctx context.Context, id_ int, next entity.RefreshToken) (int, error)
+		RevokeRefreshTokenFamily(ctx context.Context, familyID string) (int, error)
+		RevokeUserRefreshTokens(ctx context.Context, userID int) (int, error)
+		UpdateAppSigningAlg(ctx context.Context, id_ int, alg string) (int, error)
+		RotateSigningKey(ctx context.Context, next entity.SigningKey, retiresAt time.Time) (int, error)
+		GetActiveSigningKey(ctx context.Context, appID int) (entity.SigningKey, error)
+		GetSigningKey(ctx context.Context, kid string) (entity.SigningKey, error)
+		GetPublicSigningKeys(ctx context.Context, now time.Time) ([]entity.SigningKey, error)
+		DeleteRetiredSigningKeys(ctx context.Context, now time.Time) (int, error)
+		InsertRevokedToken(ctx context.Context, jti string, userID int, appID int, expiresAt time.Time) error
+		IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+		DeleteExpiredRevokedTokens(ctx context.Context, now time.Time) (int, error)
+		UpsertRole(ctx context.Context, appID int, name string, permissions []string) (int, error)
+		GetRole(ctx context.Context, appID int, name string) (entity.Role, error)
+		AssignRole(ctx context.Context, userID int, roleID int) error
+		UnassignRole(ctx context.Context, userID int, roleID int) (int, error)
+		GetUserAccess(ctx context.Context, userID int, appID int) (entity.Access, error)
+		InsertAuthEvent(ctx context.Context, event entity.AuthEvent) (int, error)
+		ListAuthEvents(ctx context.Context, filter entity.AuthEventFilter) ([]entity.AuthEvent, error)
+		GetLoginFailure(ctx context.Context, key string) (entity.LoginFailure, error)
+		RecordLoginFailure(ctx context.Context, key string, now time.Time, windowStart time.Time) (int, error)
+		LockLogin(ctx context.Context, key string, until time.Time) (int, error)
+		DeleteLoginFailure(ctx context.Context, key string) error
+		DeleteStaleLoginFailures(ctx context.Context, now time.Time, windowStart time.Time) (int, error)
+		InsertPasswordReset(ctx context.Context, reset entity.PasswordReset) error
+		ResetPassword(ctx context.Context, tokenHash []byte, now time.Time, passHash []byte) (int, error)
+		DeleteExpiredPasswordResets(ctx context.Context, now time.Time) (int, error)
+		InsertEmailVerification(ctx context.Context, verification entity.EmailVerification) error
+		VerifyEmail(ctx context.Context, tokenHash []byte, now time.Time) (int, error)
+		DeleteExpiredEmailVerifications(ctx context.Context, now time.Time) (int, error)
+		SaveTOTP(ctx context.Context, totp entity.TOTP) (int, error)
+		GetTOTP(ctx context.Context, userID int) (entity.TOTP, error)
+		ConfirmTOTP(ctx context.Context, userID int, step int64, codeHashes [][]byte) (int, error)
+		UseTOTPStep(ctx context.Context, userID int, step int64) (int, error)
+		UseRecoveryCode(ctx context.Context, userID int, codeHash []byte) (int, error)
+		DeleteTOTP(ctx context.Context, userID int) (int, error)
+		InsertMFAChallenge(ctx context.Context, challenge entity.MFAChallenge) error
+		GetMFAChallenge(ctx context.Context, tokenHash []byte, now time.Time) (entity.MFAChallenge, error)
+		DeleteMFAChallenge(ctx context.Context, tokenHash []byte) (int, error)
+		DeleteExpiredMFAChallenges(ctx context.Context, now time.Time) (int, error)
+		SetAppRedirectURIs(ctx context.Context, appID int, uris []string) error
+		GetAppRedirectURIs(ctx context.Context, appID int) ([]string, error)
+		InsertAuthorizationCode(ctx context.Context, code entity.AuthorizationCode) error
+		ConsumeAuthorizationCode(ctx context.Context, codeHash []byte, now time.Time) (entity.AuthorizationCode, error)
+		SetAuthorizationCodeTokens(ctx context.Context, code entity.AuthorizationCode) error
+		DeleteExpiredAuthorizationCodes(ctx context.Context, now time.Time) (int, error)
+		SetAppScopes(ctx context.Context, appID int, scopes []string) error
+		GetAppScopes(ctx context.Context, appID int) ([]string, error)
+		SetAppClaims(ctx context.Context, appID int, claims map[string]string) error
+		GetAppClaims(ctx context.Context, appID int) (map[string]string, error)
+		SetUserAttribute(ctx context.Context, userID int, key string, value string) error
+		DeleteUserAttribute(ctx context.Context, userID int, key string) (int, error)
+		GetUserAttributes(ctx context.Context, userID int) (map[string]string, error)
+	}
+
+	// Notifier delivers notifications carrying secret tokens, e.g. password
+	// reset links, to users.
+	Notifier interface {
+		Notify(ctx context.Context, n entity.Notification) error
+	}
+
+	// PasswordHasher hashes user and app passwords. Verify has to accept
+	// hashes of every supported algorithm, so stored hashes keep working after
+	// the configuration changes, and NeedsRehash reports the ones that don't
+	// match the configured algorithm and parameters.
+	PasswordHasher interface {
+		Hash(password string) ([]byte, error)
+		Verify(hash []byte, password string) (bool, error)
+		NeedsRehash(hash []byte) bool
 	}
 )
 
+const (
+	_defaultRefreshTokenTTL  = 30 * 24 * time.Hour
+	_defaultKeyOverlap       = 24 * time.Hour
+	_defaultPasswordResetTTL = time.Hour
+	_defaultVerificationTTL  = 24 * time.Hour
+	_defaultMFAChallengeTTL  = 5 * time.Minute
+	_defaultTOTPIssuer       = "sso"
+	_defaultAuthCodeTTL      = time.Minute
+	_defaultIssuer           = "http://localhost:8080"
+)
+
 type AuthUseCase struct {
-	log  *slog.Logger
-	repo AuthRepo
+	log        *slog.Logger
+	repo       AuthRepo
+	refreshTTL time.Duration
+	keyOverlap time.Duration
+	lockout    LockoutPolicy
+	passwords  PasswordPolicy
+	notifier   Notifier
+	hasher     PasswordHasher
+	resetTTL   time.Duration
+	verifyTTL  time.Duration
+	totpIssuer string
+	issuer     string
+	codeTTL    time.Duration
+
+	challengeTTL time.Duration
+
+	queryTimeout time.Duration
+	slowQuery    time.Duration
 }
 
 func New(
 	log *slog.Logger,
 	repo AuthRepo,
+	opts ...Option,
 ) *AuthUseCase {
-	return &AuthUseCase{
-		repo: repo,
-		log:  log,
+	a := &AuthUseCase{
+		repo:       repo,
+		log:        log,
+		refreshTTL: _defaultRefreshTokenTTL,
+		keyOverlap: _defaultKeyOverlap,
+		lockout:    _defaultLockoutPolicy,
+		passwords:  _defaultPasswordPolicy,
+		notifier:   nopNotifier{},
+		hasher:     _defaultPasswordHasher,
+		resetTTL:   _defaultPasswordResetTTL,
+		verifyTTL:  _defaultVerificationTTL,
+		totpIssuer: _defaultTOTPIssuer,
+		issuer:     _defaultIssuer,
+		codeTTL:    _defaultAuthCodeTTL,
+
+		challengeTTL: _defaultMFAChallengeTTL,
+	}
+
+	for _, opt := range opts {
+		opt(a)
 	}
+
+	if a.queryTimeout > 0 || a.slowQuery > 0 {
+		a.repo = &timedRepo{
+			repo:    a.repo,
+			log:     a.log,
+			timeout: a.queryTimeout,
+			slow:    a.slowQuery,
+		}
+	}
+
+	return a
 }
 
-func NewToken(user entity.User, app entity.App) (string, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
+// NewToken signs an access token for user. custom holds the rendered claim
+// templates of the app; it fails with entity.ErrReservedClaim rather than
+// let them overwrite a claim set by the provider.
+func NewToken(user entity.User, app entity.App, signing entity.SigningKey, access entity.Access, custom map[string]string) (string, error) {
+	method, key, err := signingKey(signing)
+	if err != nil {
+		return "", err
+	}
+
+	jti, err := newID()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.New(method)
+	token.Header["kid"] = signing.KeyID
 
 	duration := time.Duration(app.TTLHours) * time.Hour
 	claims := token.Claims.(jwt.MapClaims)
+	claims["jti"] = jti
 	claims["uid"] = user.ID
 	claims["email"] = user.Email
 	claims["exp"] = time.Now().Add(duration).Unix()
 	claims["app_id"] = app.ID
+	claims["roles"] = nonNil(access.Roles)
+	claims["scope"] = strings.Join(access.Permissions, " ")
+
+	for name, value := range custom {
+		if reservedClaim(name) {
+			return "", fmt.Errorf("%w: %s", entity.ErrReservedClaim, name)
+		}
+		claims[name] = value
+	}
 
-	tokenString, err := token.SignedString([]byte(app.Secret))
+	tokenString, err := token.SignedString(key)
 	if err != nil {
 		return "", err
 	}
 
 	return tokenString, nil
 }
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+
+	return values
+}
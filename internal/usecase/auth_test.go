@@ -0,0 +1,1332 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+	"github.com/1kovalevskiy/sso/internal/usecase"
+	repo "github.com/1kovalevskiy/sso/internal/usecase/repo_memory"
+	"github.com/1kovalevskiy/sso/pkg/logger/slogdiscard"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	testEmail    = "user@example.com"
+	testPassword = "s3cret-Passw0rd"
+	testAppName  = "test-app"
+	testAppPass  = "app-password"
+	testSecret   = "app-secret"
+)
+
+func newAuth(t *testing.T, opts ...usecase.Option) (context.Context, *usecase.AuthUseCase) {
+	t.Helper()
+
+	return context.Background(), usecase.New(slogdiscard.NewDiscardLogger(), repo.New(), opts...)
+}
+
+func createApp(t *testing.T, ctx context.Context, auth *usecase.AuthUseCase) int {
+	t.Helper()
+
+	appID, err := auth.CreateApp(ctx, testAppName, testAppPass, testSecret, 1)
+	require.NoError(t, err)
+
+	return appID
+}
+
+func TestLogin_HappyPath(t *testing.T) {
+	ctx, auth := newAuth(t)
+	appID := createApp(t, ctx, auth)
+
+	uid, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	token, err := auth.Login(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	claims, err := auth.ValidateToken(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, uid, claims.UserID)
+	assert.Equal(t, testEmail, claims.Email)
+	assert.Equal(t, appID, claims.AppID)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), claims.ExpiresAt, 2*time.Second)
+}
+
+func TestRegisterNewUser_Duplicate(t *testing.T) {
+	ctx, auth := newAuth(t)
+
+	_, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	_, err = auth.RegisterNewUser(ctx, testEmail, testPassword)
+	assert.ErrorIs(t, err, entity.ErrUserExists)
+}
+
+func TestLogin_Fails(t *testing.T) {
+	ctx, auth := newAuth(t)
+	appID := createApp(t, ctx, auth)
+
+	_, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	_, err = auth.Login(ctx, testEmail, "wrong-password", appID)
+	assert.ErrorIs(t, err, error_.ErrInvalidCredentials)
+
+	_, err = auth.Login(ctx, "unknown@example.com", testPassword, appID)
+	assert.ErrorIs(t, err, error_.ErrInvalidCredentials)
+
+	_, err = auth.Login(ctx, testEmail, testPassword, appID+1000)
+	assert.ErrorIs(t, err, entity.ErrAppNotFound)
+}
+
+func TestLogin_Lockout(t *testing.T) {
+	ctx, auth := newAuth(t, usecase.Lockout(usecase.LockoutPolicy{
+		MaxFailures: 2,
+		Window:      time.Minute,
+		Duration:    time.Minute,
+	}))
+	appID := createApp(t, ctx, auth)
+
+	_, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = auth.Login(ctx, testEmail, "wrong-password", appID)
+		assert.ErrorIs(t, err, error_.ErrInvalidCredentials)
+	}
+
+	_, err = auth.Login(ctx, testEmail, testPassword, appID)
+	assert.ErrorIs(t, err, entity.ErrLoginLocked)
+}
+
+func TestLogin_IPLockout(t *testing.T) {
+	ctx, auth := newAuth(t, usecase.Lockout(usecase.LockoutPolicy{
+		MaxIPFailures: 2,
+		Window:        time.Minute,
+		Duration:      time.Minute,
+	}))
+	appID := createApp(t, ctx, auth)
+
+	_, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	peer := entity.ContextWithClient(ctx, entity.Client{Addr: "203.0.113.7:5000"})
+	for i := 0; i < 2; i++ {
+		_, err = auth.Login(peer, fmt.Sprintf("user%d@example.com", i), "wrong-password", appID)
+		assert.ErrorIs(t, err, error_.ErrInvalidCredentials)
+	}
+
+	// The peer is locked, whichever email it tries.
+	_, err = auth.Login(peer, testEmail, testPassword, appID)
+	assert.ErrorIs(t, err, entity.ErrLoginLocked)
+
+	other := entity.ContextWithClient(ctx, entity.Client{Addr: "203.0.113.8:5000"})
+	_, err = auth.Login(other, testEmail, testPassword, appID)
+	assert.NoError(t, err)
+}
+
+func TestRefresh_ReuseRevokesFamily(t *testing.T) {
+	ctx, auth := newAuth(t)
+	appID := createApp(t, ctx, auth)
+
+	_, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	pair, err := auth.LoginWithRefresh(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	next, err := auth.Refresh(ctx, pair.RefreshToken)
+	require.NoError(t, err)
+
+	_, err = auth.Refresh(ctx, pair.RefreshToken)
+	assert.ErrorIs(t, err, entity.ErrRefreshTokenReused)
+
+	_, err = auth.Refresh(ctx, next.RefreshToken)
+	assert.ErrorIs(t, err, entity.ErrRefreshTokenRevoked)
+}
+
+func TestRevokeToken(t *testing.T) {
+	ctx, auth := newAuth(t)
+	appID := createApp(t, ctx, auth)
+
+	_, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	token, err := auth.Login(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	require.NoError(t, auth.RevokeToken(ctx, token))
+
+	_, err = auth.ValidateToken(ctx, token)
+	assert.ErrorIs(t, err, entity.ErrTokenRevoked)
+}
+
+func TestRoles_InToken(t *testing.T) {
+	ctx, auth := newAuth(t)
+	appID := createApp(t, ctx, auth)
+
+	uid, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	_, err = auth.SetRole(ctx, testAppName, testAppPass, "editor", []string{"posts:write", "posts:read"})
+	require.NoError(t, err)
+	require.NoError(t, auth.AssignRole(ctx, testAppName, testAppPass, uid, "editor"))
+
+	token, err := auth.Login(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	claims, err := auth.ValidateToken(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"editor"}, claims.Roles)
+	assert.Equal(t, []string{"posts:read", "posts:write"}, claims.Permissions)
+}
+
+func TestListAuthEvents(t *testing.T) {
+	const adminEmail = "admin@example.com"
+
+	ctx, auth := newAuth(t)
+	appID := createApp(t, ctx, auth)
+
+	uid, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+	_, err = auth.Login(ctx, testEmail, "wrong-password", appID)
+	require.Error(t, err)
+
+	userToken, err := auth.Login(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	adminID, err := auth.RegisterNewUser(ctx, adminEmail, testPassword)
+	require.NoError(t, err)
+	require.NoError(t, auth.SetUserAdmin(ctx, adminID, true))
+	adminToken, err := auth.Login(ctx, adminEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	_, _, err = auth.ListAuthEvents(ctx, userToken, entity.AuthEventFilter{})
+	assert.ErrorIs(t, err, error_.ErrPermissionDenied)
+
+	filter := entity.AuthEventFilter{UserID: uid, Limit: 2}
+	events, next, err := auth.ListAuthEvents(ctx, adminToken, filter)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, entity.EventLoginSuccess, events[0].Type)
+	assert.Equal(t, entity.EventLoginFailure, events[1].Type)
+	require.NotZero(t, next)
+
+	filter.BeforeID = next
+	events, next, err = auth.ListAuthEvents(ctx, adminToken, filter)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, entity.EventRegister, events[0].Type)
+	assert.Zero(t, next)
+}
+
+func TestUpdateApp_NonHS256RecordsEvent(t *testing.T) {
+	const adminEmail = "admin@example.com"
+
+	ctx, auth := newAuth(t)
+	appID := createApp(t, ctx, auth)
+
+	_, err := auth.SetSigningAlgorithm(ctx, testAppName, testAppPass, entity.SigningAlgRS256)
+	require.NoError(t, err)
+
+	keys, err := auth.JWKS(ctx)
+	require.NoError(t, err)
+
+	app, err := auth.UpdateApp(ctx, appID, "", "new-secret", 2)
+	require.NoError(t, err)
+	assert.Equal(t, entity.SigningAlgRS256, app.SigningAlg)
+
+	// The secret does not sign RS256 tokens, so no key is rotated.
+	rotated, err := auth.JWKS(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, keys, rotated)
+
+	adminID, err := auth.RegisterNewUser(ctx, adminEmail, testPassword)
+	require.NoError(t, err)
+	require.NoError(t, auth.SetUserAdmin(ctx, adminID, true))
+	adminToken, err := auth.Login(ctx, adminEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	events, _, err := auth.ListAuthEvents(ctx, adminToken, entity.AuthEventFilter{
+		AppID: appID,
+		Types: []string{entity.EventAppUpdated},
+		Limit: 1,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "settings updated", events[0].Details)
+}
+
+func TestSetUserAdmin(t *testing.T) {
+	ctx, auth := newAuth(t)
+	appID := createApp(t, ctx, auth)
+
+	uid, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+	token, err := auth.Login(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	// Registration never grants admin rights.
+	isAdmin, err := auth.IsAdmin(ctx, token, uid)
+	require.NoError(t, err)
+	assert.False(t, isAdmin)
+
+	require.NoError(t, auth.SetUserAdmin(ctx, uid, true))
+
+	isAdmin, err = auth.IsAdmin(ctx, token, uid)
+	require.NoError(t, err)
+	assert.True(t, isAdmin)
+
+	require.NoError(t, auth.SetUserAdmin(ctx, uid, false))
+
+	isAdmin, err = auth.IsAdmin(ctx, token, uid)
+	require.NoError(t, err)
+	assert.False(t, isAdmin)
+
+	assert.ErrorIs(t, auth.SetUserAdmin(ctx, uid+100, true), entity.ErrUserNotFound)
+}
+
+func TestGetUserAccess(t *testing.T) {
+	ctx, auth := newAuth(t)
+	appID := createApp(t, ctx, auth)
+
+	uid, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+	token, err := auth.Login(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	otherID, err := auth.RegisterNewUser(ctx, "other@example.com", testPassword)
+	require.NoError(t, err)
+	otherToken, err := auth.Login(ctx, "other@example.com", testPassword, appID)
+	require.NoError(t, err)
+
+	_, err = auth.GetUser(ctx, "", uid)
+	assert.Error(t, err)
+
+	_, err = auth.GetUser(ctx, otherToken, uid)
+	assert.ErrorIs(t, err, error_.ErrPermissionDenied)
+
+	_, err = auth.IsAdmin(ctx, otherToken, uid)
+	assert.ErrorIs(t, err, error_.ErrPermissionDenied)
+
+	require.NoError(t, auth.SetUserAdmin(ctx, uid, true))
+
+	user, err := auth.GetUser(ctx, token, otherID)
+	require.NoError(t, err)
+	assert.Equal(t, "other@example.com", user.Email)
+
+	_, err = auth.GetUser(ctx, token, otherID+100)
+	assert.ErrorIs(t, err, entity.ErrUserNotFound)
+}
+
+func TestDeleteApp(t *testing.T) {
+	ctx, auth := newAuth(t)
+	appID := createApp(t, ctx, auth)
+
+	_, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	token, err := auth.Login(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	require.NoError(t, auth.DeleteApp(ctx, appID))
+
+	_, err = auth.GetApp(ctx, appID)
+	assert.ErrorIs(t, err, entity.ErrAppNotFound)
+
+	_, err = auth.ValidateToken(ctx, token)
+	assert.Error(t, err)
+
+	assert.ErrorIs(t, auth.DeleteApp(ctx, appID), entity.ErrAppNotFound)
+
+	_, err = auth.ConfigureApp(ctx, testAppName, testAppPass, testSecret, 1)
+	assert.ErrorIs(t, err, entity.ErrAppNotFound)
+}
+
+func TestSetAppDisabled(t *testing.T) {
+	ctx, auth := newAuth(t)
+	appID := createApp(t, ctx, auth)
+
+	_, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	require.NoError(t, auth.SetAppDisabled(ctx, appID, true))
+
+	_, err = auth.Login(ctx, testEmail, testPassword, appID)
+	assert.ErrorIs(t, err, entity.ErrAppDisabled)
+
+	require.NoError(t, auth.SetAppDisabled(ctx, appID, false))
+
+	_, err = auth.Login(ctx, testEmail, testPassword, appID)
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, auth.SetAppDisabled(ctx, appID+1000, true), entity.ErrAppNotFound)
+}
+
+func TestListApps_Pagination(t *testing.T) {
+	ctx, auth := newAuth(t)
+
+	var ids []int
+	for _, name := range []string{"a", "b", "c"} {
+		id, err := auth.CreateApp(ctx, name, testAppPass, testSecret, 1)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	apps, next, err := auth.ListApps(ctx, 0, 2)
+	require.NoError(t, err)
+	require.Len(t, apps, 2)
+	assert.Equal(t, ids[:2], []int{apps[0].ID, apps[1].ID})
+	assert.Equal(t, ids[1], next)
+
+	apps, next, err = auth.ListApps(ctx, next, 2)
+	require.NoError(t, err)
+	require.Len(t, apps, 1)
+	assert.Equal(t, ids[2], apps[0].ID)
+	assert.Zero(t, next)
+}
+
+func TestChangePassword(t *testing.T) {
+	ctx, auth := newAuth(t)
+	appID := createApp(t, ctx, auth)
+
+	_, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	pair, err := auth.LoginWithRefresh(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	err = auth.ChangePassword(ctx, testEmail, "wrong-password", "n3w-Passw0rd", "")
+	assert.ErrorIs(t, err, error_.ErrInvalidCredentials)
+
+	require.NoError(t, auth.ChangePassword(ctx, testEmail, testPassword, "n3w-Passw0rd", ""))
+
+	_, err = auth.Login(ctx, testEmail, testPassword, appID)
+	assert.ErrorIs(t, err, error_.ErrInvalidCredentials)
+
+	_, err = auth.Login(ctx, testEmail, "n3w-Passw0rd", appID)
+	assert.NoError(t, err)
+
+	_, err = auth.Refresh(ctx, pair.RefreshToken)
+	assert.ErrorIs(t, err, entity.ErrRefreshTokenRevoked)
+}
+
+// captureNotifier keeps the notifications it was asked to deliver.
+type captureNotifier struct {
+	sent []entity.Notification
+}
+
+func (n *captureNotifier) Notify(_ context.Context, notification entity.Notification) error {
+	n.sent = append(n.sent, notification)
+
+	return nil
+}
+
+func (n *captureNotifier) ofType(typ string) []entity.Notification {
+	var sent []entity.Notification
+	for _, notification := range n.sent {
+		if notification.Type == typ {
+			sent = append(sent, notification)
+		}
+	}
+
+	return sent
+}
+
+func TestPasswordReset(t *testing.T) {
+	notifier := &captureNotifier{}
+	ctx, auth := newAuth(t, usecase.WithNotifier(notifier))
+	appID := createApp(t, ctx, auth)
+
+	_, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	require.NoError(t, auth.RequestPasswordReset(ctx, "unknown@example.com"))
+	assert.Empty(t, notifier.ofType(entity.NotificationPasswordReset))
+
+	require.NoError(t, auth.RequestPasswordReset(ctx, testEmail))
+	require.NoError(t, auth.RequestPasswordReset(ctx, testEmail))
+	sent := notifier.ofType(entity.NotificationPasswordReset)
+	require.Len(t, sent, 2)
+	assert.Equal(t, testEmail, sent[0].Email)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), sent[0].ExpiresAt, 2*time.Second)
+
+	err = auth.ConfirmPasswordReset(ctx, "not-a-token", "n3w-Passw0rd")
+	assert.ErrorIs(t, err, entity.ErrPasswordResetNotFound)
+
+	require.NoError(t, auth.ConfirmPasswordReset(ctx, sent[1].Token, "n3w-Passw0rd"))
+
+	_, err = auth.Login(ctx, testEmail, "n3w-Passw0rd", appID)
+	assert.NoError(t, err)
+
+	// Tokens are single-use and a reset drops the other outstanding tokens.
+	err = auth.ConfirmPasswordReset(ctx, sent[1].Token, "an0ther-Passw0rd")
+	assert.ErrorIs(t, err, entity.ErrPasswordResetNotFound)
+
+	err = auth.ConfirmPasswordReset(ctx, sent[0].Token, "an0ther-Passw0rd")
+	assert.ErrorIs(t, err, entity.ErrPasswordResetNotFound)
+}
+
+// failingNotifier fails every delivery.
+type failingNotifier struct{}
+
+func (failingNotifier) Notify(context.Context, entity.Notification) error {
+	return errors.New("smtp unavailable")
+}
+
+func TestPasswordReset_DeliveryFailureNotReported(t *testing.T) {
+	ctx, auth := newAuth(t, usecase.WithNotifier(failingNotifier{}))
+
+	_, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	assert.NoError(t, auth.RequestPasswordReset(ctx, testEmail))
+	assert.NoError(t, auth.RequestPasswordReset(ctx, "unknown@example.com"))
+}
+
+func TestPasswordReset_Expired(t *testing.T) {
+	notifier := &captureNotifier{}
+	ctx, auth := newAuth(t, usecase.WithNotifier(notifier), usecase.PasswordResetTTL(time.Nanosecond))
+
+	_, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	require.NoError(t, auth.RequestPasswordReset(ctx, testEmail))
+	sent := notifier.ofType(entity.NotificationPasswordReset)
+	require.Len(t, sent, 1)
+
+	time.Sleep(time.Millisecond)
+
+	err = auth.ConfirmPasswordReset(ctx, sent[0].Token, "n3w-Passw0rd")
+	assert.ErrorIs(t, err, entity.ErrPasswordResetNotFound)
+}
+
+func TestVerifyEmail(t *testing.T) {
+	notifier := &captureNotifier{}
+	ctx, auth := newAuth(t, usecase.WithNotifier(notifier))
+	appID := createApp(t, ctx, auth)
+
+	uid, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	sent := notifier.ofType(entity.NotificationEmailVerification)
+	require.Len(t, sent, 1)
+	assert.Equal(t, testEmail, sent[0].Email)
+
+	// Unverified users can log in until the app requires a verified email.
+	_, err = auth.Login(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	require.NoError(t, auth.SetAppEmailPolicy(ctx, appID, true))
+
+	_, err = auth.Login(ctx, testEmail, testPassword, appID)
+	assert.ErrorIs(t, err, entity.ErrEmailNotVerified)
+
+	err = auth.VerifyEmail(ctx, "not-a-token")
+	assert.ErrorIs(t, err, entity.ErrEmailVerificationNotFound)
+
+	require.NoError(t, auth.VerifyEmail(ctx, sent[0].Token))
+
+	token, err := auth.Login(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	user, err := auth.GetUser(ctx, token, uid)
+	require.NoError(t, err)
+	assert.True(t, user.EmailVerified)
+
+	err = auth.VerifyEmail(ctx, sent[0].Token)
+	assert.ErrorIs(t, err, entity.ErrEmailVerificationNotFound)
+
+	// Verified and unknown emails don't get new tokens.
+	require.NoError(t, auth.RequestEmailVerification(ctx, testEmail))
+	require.NoError(t, auth.RequestEmailVerification(ctx, "unknown@example.com"))
+	assert.Len(t, notifier.ofType(entity.NotificationEmailVerification), 1)
+}
+
+func TestSetAppEmailPolicy_UnknownApp(t *testing.T) {
+	ctx, auth := newAuth(t)
+
+	err := auth.SetAppEmailPolicy(ctx, 1<<30, true)
+	assert.ErrorIs(t, err, entity.ErrAppNotFound)
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	denylistPath := filepath.Join(t.TempDir(), "denylist.txt")
+	require.NoError(t, os.WriteFile(denylistPath, []byte("# common\n\nPassword123\n"), 0o600))
+
+	denylist, err := usecase.LoadPasswordDenylist(denylistPath)
+	require.NoError(t, err)
+
+	ctx, auth := newAuth(t, usecase.Passwords(usecase.PasswordPolicy{
+		MinLength:     10,
+		MaxLength:     100,
+		RequireLower:  true,
+		RequireUpper:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+		Denylist:      denylist,
+	}))
+
+	tests := []struct {
+		name     string
+		password string
+		rules    []string
+	}{
+		{
+			name:     "all classes missing",
+			password: "          ",
+			rules: []string{
+				entity.PasswordRuleLower, entity.PasswordRuleUpper,
+				entity.PasswordRuleDigit, entity.PasswordRuleSymbol,
+			},
+		},
+		{
+			name:     "short without symbol",
+			password: "Ab1",
+			rules:    []string{entity.PasswordRuleMinLength, entity.PasswordRuleSymbol},
+		},
+		{
+			name:     "over bcrypt limit",
+			password: "Ab1!" + strings.Repeat("x", 69),
+			rules:    []string{entity.PasswordRuleMaxLength},
+		},
+		{
+			name:     "denylisted in other case",
+			password: "PASSWORD123",
+			rules:    []string{entity.PasswordRuleLower, entity.PasswordRuleSymbol, entity.PasswordRuleDenylist},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := auth.RegisterNewUser(ctx, testEmail, tt.password)
+			require.ErrorIs(t, err, entity.ErrWeakPassword)
+
+			var policyErr *entity.PasswordPolicyError
+			require.ErrorAs(t, err, &policyErr)
+
+			rules := make([]string, 0, len(policyErr.Violations))
+			for _, v := range policyErr.Violations {
+				rules = append(rules, v.Rule)
+			}
+			assert.ElementsMatch(t, tt.rules, rules)
+		})
+	}
+
+	_, err = auth.RegisterNewUser(ctx, testEmail, "Str0ng-Passw0rd")
+	require.NoError(t, err)
+
+	err = auth.ChangePassword(ctx, testEmail, "Str0ng-Passw0rd", "weak", "")
+	assert.ErrorIs(t, err, entity.ErrWeakPassword)
+}
+
+func TestEmailNormalization(t *testing.T) {
+	ctx, auth := newAuth(t)
+	appID := createApp(t, ctx, auth)
+
+	uid, err := auth.RegisterNewUser(ctx, "Bob@Example.COM", testPassword)
+	require.NoError(t, err)
+
+	_, err = auth.RegisterNewUser(ctx, "BOB@example.com", testPassword)
+	assert.ErrorIs(t, err, entity.ErrUserExists)
+
+	token, err := auth.Login(ctx, "bOb@eXample.com", testPassword, appID)
+	require.NoError(t, err)
+
+	user, err := auth.GetUser(ctx, token, uid)
+	require.NoError(t, err)
+	assert.Equal(t, "bob@example.com", user.Email)
+
+	uid, err = auth.RegisterNewUser(ctx, "user@Bücher.example", testPassword)
+	require.NoError(t, err)
+
+	token, err = auth.Login(ctx, "user@xn--bcher-kva.example", testPassword, appID)
+	require.NoError(t, err)
+
+	user, err = auth.GetUser(ctx, token, uid)
+	require.NoError(t, err)
+	assert.Equal(t, "user@xn--bcher-kva.example", user.Email)
+
+	_, err = auth.RegisterNewUser(ctx, "no-at-sign", testPassword)
+	assert.ErrorIs(t, err, entity.ErrInvalidEmail)
+}
+
+func TestNormalizeEmails(t *testing.T) {
+	ctx := context.Background()
+	users := repo.New()
+	auth := usecase.New(slogdiscard.NewDiscardLogger(), users)
+	appID := createApp(t, ctx, auth)
+
+	holderID, err := auth.RegisterNewUser(ctx, "user@bücher.example", testPassword)
+	require.NoError(t, err)
+
+	// Rows stored before normalization, as the SQL migration leaves them.
+	passHash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	require.NoError(t, err)
+	jorgID, err := users.InsertUser(ctx, "Jörg@Bücher.Example", passHash)
+	require.NoError(t, err)
+	dupID, err := users.InsertUser(ctx, "User@BÜCHER.example", passHash)
+	require.NoError(t, err)
+
+	normalized, err := auth.NormalizeEmails(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, normalized)
+
+	jorg, err := users.GetUserByID(ctx, jorgID)
+	require.NoError(t, err)
+	assert.Equal(t, "jörg@xn--bcher-kva.example", jorg.Email)
+
+	_, err = auth.Login(ctx, "JÖRG@bücher.example", testPassword, appID)
+	assert.NoError(t, err)
+
+	dup, err := users.GetUserByID(ctx, dupID)
+	require.NoError(t, err)
+	assert.Equal(t, "User@BÜCHER.example", dup.Email)
+
+	collisions, err := users.ListEmailCollisions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.EmailCollision{
+		{Email: "user@xn--bcher-kva.example", UserIDs: []int{holderID, dupID}},
+	}, collisions)
+
+	unresolved, err := auth.ReportEmailCollisions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, unresolved)
+
+	normalized, err = auth.NormalizeEmails(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, normalized)
+}
+
+func TestLogin_RehashesPassword(t *testing.T) {
+	ctx := context.Background()
+	users := repo.New()
+	legacy := usecase.New(slogdiscard.NewDiscardLogger(), users,
+		usecase.Hasher(usecase.BcryptHasher{Cost: bcrypt.MinCost}),
+	)
+	appID := createApp(t, ctx, legacy)
+
+	_, err := legacy.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	stored, err := users.GetUser(ctx, testEmail)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(stored.PassHash), "$2a$"))
+
+	argon := usecase.Argon2idHasher{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	auth := usecase.New(slogdiscard.NewDiscardLogger(), users, usecase.Hasher(argon))
+
+	_, err = auth.Login(ctx, testEmail, "wrong-password", appID)
+	require.ErrorIs(t, err, error_.ErrInvalidCredentials)
+
+	unchanged, err := users.GetUser(ctx, testEmail)
+	require.NoError(t, err)
+	assert.Equal(t, stored.PassHash, unchanged.PassHash)
+
+	_, err = auth.Login(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	rehashed, err := users.GetUser(ctx, testEmail)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(rehashed.PassHash), "$argon2id$v=19$m=1024,t=1,p=1$"))
+	assert.False(t, argon.NeedsRehash(rehashed.PassHash))
+
+	_, err = auth.Login(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	stronger := argon
+	stronger.Iterations = 2
+	assert.True(t, stronger.NeedsRehash(rehashed.PassHash))
+
+	auth = usecase.New(slogdiscard.NewDiscardLogger(), users, usecase.Hasher(stronger))
+	_, err = auth.Login(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	rehashed, err = users.GetUser(ctx, testEmail)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(rehashed.PassHash), "$argon2id$v=19$m=1024,t=2,p=1$"))
+}
+
+func TestPasswordHasher(t *testing.T) {
+	hashers := map[string]usecase.PasswordHasher{
+		"argon2id": usecase.Argon2idHasher{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+		"bcrypt":   usecase.BcryptHasher{Cost: bcrypt.MinCost},
+	}
+
+	for name, hasher := range hashers {
+		t.Run(name, func(t *testing.T) {
+			hash, err := hasher.Hash(testPassword)
+			require.NoError(t, err)
+			assert.False(t, hasher.NeedsRehash(hash))
+
+			ok, err := hasher.Verify(hash, testPassword)
+			require.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = hasher.Verify(hash, "wrong-password")
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			for other, otherHasher := range hashers {
+				if other == name {
+					continue
+				}
+
+				ok, err = otherHasher.Verify(hash, testPassword)
+				require.NoError(t, err)
+				assert.True(t, ok)
+				assert.True(t, otherHasher.NeedsRehash(hash))
+			}
+
+			_, err = hasher.Verify([]byte("$argon2id$v=19$m=1024,t=1,p=1$bad"), testPassword)
+			assert.Error(t, err)
+
+			_, err = hasher.Verify([]byte("plain"), testPassword)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// totpCode computes the RFC 6238 code of a base32 secret at t.
+func totpCode(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	require.NoError(t, err)
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(at.Unix()/30))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%06d", value%1_000_000)
+}
+
+func TestTOTP(t *testing.T) {
+	ctx, auth := newAuth(t, usecase.TOTPIssuer("Example"))
+	appID := createApp(t, ctx, auth)
+
+	uid, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	token, err := auth.Login(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	enrollment, err := auth.EnrollTOTP(ctx, token)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(enrollment.URI, "otpauth://totp/Example:"+testEmail+"?"))
+	assert.Contains(t, enrollment.URI, "secret="+enrollment.Secret)
+
+	// An unconfirmed enrollment doesn't guard logins.
+	_, err = auth.Login(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	_, err = auth.ConfirmTOTP(ctx, token, "000000")
+	require.ErrorIs(t, err, entity.ErrInvalidMFACode)
+
+	now := time.Now()
+	recovery, err := auth.ConfirmTOTP(ctx, token, totpCode(t, enrollment.Secret, now))
+	require.NoError(t, err)
+	require.Len(t, recovery, 10)
+
+	_, err = auth.EnrollTOTP(ctx, token)
+	require.ErrorIs(t, err, entity.ErrTOTPEnabled)
+
+	_, err = auth.Login(ctx, testEmail, testPassword, appID)
+	var mfaErr *entity.MFARequiredError
+	require.ErrorAs(t, err, &mfaErr)
+	assert.ErrorIs(t, err, entity.ErrMFARequired)
+
+	_, err = auth.VerifyMFALogin(ctx, mfaErr.Challenge, "000000")
+	require.ErrorIs(t, err, entity.ErrInvalidMFACode)
+
+	// The code of the confirmation step was used up.
+	_, err = auth.VerifyMFALogin(ctx, mfaErr.Challenge, totpCode(t, enrollment.Secret, now))
+	require.ErrorIs(t, err, entity.ErrInvalidMFACode)
+
+	next := totpCode(t, enrollment.Secret, now.Add(30*time.Second))
+	pair, err := auth.VerifyMFALogin(ctx, mfaErr.Challenge, next)
+	require.NoError(t, err)
+	assert.Empty(t, pair.RefreshToken)
+
+	claims, err := auth.ValidateToken(ctx, pair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, uid, claims.UserID)
+
+	_, err = auth.VerifyMFALogin(ctx, mfaErr.Challenge, next)
+	require.ErrorIs(t, err, entity.ErrMFAChallengeNotFound)
+
+	_, err = auth.LoginWithRefresh(ctx, testEmail, testPassword, appID)
+	require.ErrorAs(t, err, &mfaErr)
+
+	_, err = auth.VerifyMFALogin(ctx, mfaErr.Challenge, next)
+	require.ErrorIs(t, err, entity.ErrInvalidMFACode)
+
+	pair, err = auth.VerifyMFALogin(ctx, mfaErr.Challenge, strings.ToUpper(recovery[0]))
+	require.NoError(t, err)
+	assert.NotEmpty(t, pair.RefreshToken)
+
+	_, err = auth.LoginWithRefresh(ctx, testEmail, testPassword, appID)
+	require.ErrorAs(t, err, &mfaErr)
+
+	_, err = auth.VerifyMFALogin(ctx, mfaErr.Challenge, recovery[0])
+	require.ErrorIs(t, err, entity.ErrInvalidMFACode)
+
+	require.NoError(t, auth.DisableTOTP(ctx, token, recovery[1]))
+
+	_, err = auth.VerifyMFALogin(ctx, mfaErr.Challenge, recovery[2])
+	require.ErrorIs(t, err, entity.ErrMFAChallengeNotFound)
+
+	_, err = auth.Login(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+}
+
+func TestTOTP_ChallengeExpires(t *testing.T) {
+	ctx, auth := newAuth(t, usecase.MFAChallengeTTL(-time.Second))
+	appID := createApp(t, ctx, auth)
+
+	_, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	token, err := auth.Login(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	enrollment, err := auth.EnrollTOTP(ctx, token)
+	require.NoError(t, err)
+
+	now := time.Now()
+	_, err = auth.ConfirmTOTP(ctx, token, totpCode(t, enrollment.Secret, now))
+	require.NoError(t, err)
+
+	_, err = auth.Login(ctx, testEmail, testPassword, appID)
+	var mfaErr *entity.MFARequiredError
+	require.ErrorAs(t, err, &mfaErr)
+
+	_, err = auth.VerifyMFALogin(ctx, mfaErr.Challenge, totpCode(t, enrollment.Secret, now.Add(30*time.Second)))
+	assert.ErrorIs(t, err, entity.ErrMFAChallengeNotFound)
+}
+
+func TestTOTP_ChangePassword(t *testing.T) {
+	ctx, auth := newAuth(t)
+	appID := createApp(t, ctx, auth)
+
+	_, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	token, err := auth.Login(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	enrollment, err := auth.EnrollTOTP(ctx, token)
+	require.NoError(t, err)
+
+	now := time.Now()
+	recovery, err := auth.ConfirmTOTP(ctx, token, totpCode(t, enrollment.Secret, now))
+	require.NoError(t, err)
+
+	// The password alone is not enough once TOTP is enabled.
+	err = auth.ChangePassword(ctx, testEmail, testPassword, "n3w-Passw0rd", "")
+	require.ErrorIs(t, err, entity.ErrInvalidMFACode)
+
+	_, err = auth.Login(ctx, testEmail, testPassword, appID)
+	var mfaErr *entity.MFARequiredError
+	require.ErrorAs(t, err, &mfaErr)
+
+	next := totpCode(t, enrollment.Secret, now.Add(30*time.Second))
+	require.NoError(t, auth.ChangePassword(ctx, testEmail, testPassword, "n3w-Passw0rd", next))
+	require.NoError(t, auth.ChangePassword(ctx, testEmail, "n3w-Passw0rd", "an0ther-Passw0rd", recovery[0]))
+
+	_, err = auth.Login(ctx, testEmail, "an0ther-Passw0rd", appID)
+	assert.ErrorAs(t, err, &mfaErr)
+}
+
+func TestAuthorizationCode(t *testing.T) {
+	ctx, auth := newAuth(t, usecase.Issuer("https://sso.example.com"))
+	appID := createApp(t, ctx, auth)
+
+	const redirectURI = "https://app.example.com/callback"
+	require.ErrorIs(t, auth.SetAppRedirectURIs(ctx, appID, []string{"/callback"}), entity.ErrInvalidRedirectURI)
+	require.ErrorIs(t, auth.SetAppRedirectURIs(ctx, appID, []string{redirectURI + "#top"}), entity.ErrInvalidRedirectURI)
+	require.NoError(t, auth.SetAppRedirectURIs(ctx, appID, []string{redirectURI}))
+
+	app, err := auth.GetApp(ctx, appID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{redirectURI}, app.RedirectURIs)
+
+	uid, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	const verifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	req := entity.AuthorizationRequest{
+		ClientID:      appID,
+		RedirectURI:   redirectURI,
+		Scope:         []string{"openid", "email", "profile"},
+		Nonce:         "n-0S6_WzA2Mj",
+		CodeChallenge: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+	}
+
+	_, err = auth.CheckAuthorizationRequest(ctx, entity.AuthorizationRequest{ClientID: appID, RedirectURI: "https://evil.example.com/"})
+	require.ErrorIs(t, err, entity.ErrRedirectURINotAllowed)
+
+	_, err = auth.CheckAuthorizationRequest(ctx, entity.AuthorizationRequest{ClientID: appID + 100, RedirectURI: redirectURI})
+	require.ErrorIs(t, err, entity.ErrInvalidClient)
+
+	_, err = auth.Authorize(ctx, req, testEmail, "wrong")
+	require.ErrorIs(t, err, error_.ErrInvalidCredentials)
+
+	code, err := auth.Authorize(ctx, req, testEmail, testPassword)
+	require.NoError(t, err)
+
+	_, err = auth.ExchangeAuthorizationCode(ctx, appID, "wrong", code, redirectURI, verifier)
+	require.ErrorIs(t, err, entity.ErrInvalidClient)
+
+	tokens, err := auth.ExchangeAuthorizationCode(ctx, appID, testAppPass, code, redirectURI, verifier)
+	require.NoError(t, err)
+	assert.Equal(t, "openid email", tokens.Scope)
+	assert.Empty(t, tokens.RefreshToken)
+
+	claims, err := auth.ValidateToken(ctx, tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, uid, claims.UserID)
+
+	// ID tokens are not access tokens.
+	_, err = auth.ValidateToken(ctx, tokens.IDToken)
+	require.ErrorIs(t, err, entity.ErrTokenMalformed)
+
+	idToken, err := jwt.Parse(tokens.IDToken, func(*jwt.Token) (interface{}, error) {
+		return []byte(testSecret), nil
+	}, jwt.WithIssuer("https://sso.example.com"), jwt.WithAudience(strconv.Itoa(appID)))
+	require.NoError(t, err)
+
+	idClaims := idToken.Claims.(jwt.MapClaims)
+	assert.Equal(t, strconv.Itoa(uid), idClaims["sub"])
+	assert.Equal(t, req.Nonce, idClaims["nonce"])
+	assert.Equal(t, testEmail, idClaims["email"])
+	assert.Equal(t, false, idClaims["email_verified"])
+
+	// Codes are single-use.
+	_, err = auth.ExchangeAuthorizationCode(ctx, appID, testAppPass, code, redirectURI, verifier)
+	require.ErrorIs(t, err, entity.ErrAuthorizationCodeNotFound)
+
+	code, err = auth.Authorize(ctx, req, testEmail, testPassword)
+	require.NoError(t, err)
+
+	_, err = auth.ExchangeAuthorizationCode(ctx, appID, testAppPass, code, redirectURI, "another-verifier")
+	require.ErrorIs(t, err, entity.ErrInvalidGrant)
+
+	req.Scope = []string{"offline_access"}
+	code, err = auth.Authorize(ctx, req, testEmail, testPassword)
+	require.NoError(t, err)
+
+	_, err = auth.ExchangeAuthorizationCode(ctx, appID, testAppPass, code, "https://app.example.com/other", verifier)
+	require.ErrorIs(t, err, entity.ErrInvalidGrant)
+
+	code, err = auth.Authorize(ctx, req, testEmail, testPassword)
+	require.NoError(t, err)
+
+	tokens, err = auth.ExchangeAuthorizationCode(ctx, appID, testAppPass, code, redirectURI, verifier)
+	require.NoError(t, err)
+	assert.Empty(t, tokens.IDToken)
+	require.NotEmpty(t, tokens.RefreshToken)
+
+	otherID, err := auth.CreateApp(ctx, "other-app", testAppPass, testSecret, 1)
+	require.NoError(t, err)
+
+	_, err = auth.ExchangeRefreshToken(ctx, otherID, testAppPass, tokens.RefreshToken)
+	require.ErrorIs(t, err, entity.ErrInvalidGrant)
+
+	pair, err := auth.ExchangeRefreshToken(ctx, appID, testAppPass, tokens.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, tokens.RefreshToken, pair.RefreshToken)
+}
+
+func TestAuthorizationCode_ReuseRevokesTokens(t *testing.T) {
+	ctx, auth := newAuth(t)
+	appID := createApp(t, ctx, auth)
+
+	const redirectURI = "https://app.example.com/callback"
+	require.NoError(t, auth.SetAppRedirectURIs(ctx, appID, []string{redirectURI}))
+
+	_, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	const verifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	code, err := auth.Authorize(ctx, entity.AuthorizationRequest{
+		ClientID:      appID,
+		RedirectURI:   redirectURI,
+		Scope:         []string{"openid", "offline_access"},
+		CodeChallenge: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+	}, testEmail, testPassword)
+	require.NoError(t, err)
+
+	tokens, err := auth.ExchangeAuthorizationCode(ctx, appID, testAppPass, code, redirectURI, verifier)
+	require.NoError(t, err)
+
+	pair, err := auth.ExchangeRefreshToken(ctx, appID, testAppPass, tokens.RefreshToken)
+	require.NoError(t, err)
+
+	_, err = auth.ExchangeAuthorizationCode(ctx, appID, testAppPass, code, redirectURI, verifier)
+	require.ErrorIs(t, err, entity.ErrAuthorizationCodeNotFound)
+
+	_, err = auth.ValidateToken(ctx, tokens.AccessToken)
+	assert.ErrorIs(t, err, entity.ErrTokenRevoked)
+
+	_, err = auth.Refresh(ctx, pair.RefreshToken)
+	assert.ErrorIs(t, err, entity.ErrRefreshTokenRevoked)
+}
+
+func TestAuthorizationCode_Expired(t *testing.T) {
+	ctx, auth := newAuth(t, usecase.AuthorizationCodeTTL(-time.Second))
+	appID := createApp(t, ctx, auth)
+
+	const redirectURI = "com.example.app:/callback"
+	require.NoError(t, auth.SetAppRedirectURIs(ctx, appID, []string{redirectURI}))
+
+	_, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	code, err := auth.Authorize(ctx, entity.AuthorizationRequest{
+		ClientID:      appID,
+		RedirectURI:   redirectURI,
+		Scope:         []string{"openid"},
+		CodeChallenge: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+	}, testEmail, testPassword)
+	require.NoError(t, err)
+
+	_, err = auth.ExchangeAuthorizationCode(ctx, appID, testAppPass, code, redirectURI, "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	assert.ErrorIs(t, err, entity.ErrAuthorizationCodeNotFound)
+}
+
+func TestIssueClientToken(t *testing.T) {
+	ctx, auth := newAuth(t)
+	appID := createApp(t, ctx, auth)
+
+	require.ErrorIs(t, auth.SetAppScopes(ctx, appID, []string{"orders read"}), entity.ErrMalformedScope)
+	require.NoError(t, auth.SetAppScopes(ctx, appID, []string{"orders:write", "orders:read"}))
+
+	app, err := auth.GetApp(ctx, appID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders:read", "orders:write"}, app.Scopes)
+
+	_, err = auth.IssueClientToken(ctx, testAppName, "wrong", nil)
+	require.ErrorIs(t, err, entity.ErrInvalidClient)
+
+	_, err = auth.IssueClientToken(ctx, "unknown-app", testAppPass, nil)
+	require.ErrorIs(t, err, entity.ErrInvalidClient)
+
+	_, err = auth.IssueClientToken(ctx, testAppName, testAppPass, []string{"orders:delete"})
+	require.ErrorIs(t, err, entity.ErrInvalidScope)
+
+	token, err := auth.IssueClientToken(ctx, testAppName, testAppPass, []string{"orders:read"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders:read"}, token.Scope)
+
+	claims, err := auth.ValidateToken(ctx, token.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "app:"+strconv.Itoa(appID), claims.Subject)
+	assert.Equal(t, appID, claims.AppID)
+	assert.Zero(t, claims.UserID)
+	assert.Equal(t, []string{"orders:read"}, claims.Permissions)
+
+	// App tokens do not act for any user.
+	_, err = auth.UserInfo(ctx, token.AccessToken)
+	require.ErrorIs(t, err, entity.ErrUserNotFound)
+
+	all, err := auth.IssueClientToken(ctx, testAppName, testAppPass, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders:read", "orders:write"}, all.Scope)
+
+	require.NoError(t, auth.RevokeToken(ctx, all.AccessToken))
+	_, err = auth.ValidateToken(ctx, all.AccessToken)
+	require.ErrorIs(t, err, entity.ErrTokenRevoked)
+
+	require.NoError(t, auth.SetAppDisabled(ctx, appID, true))
+	_, err = auth.IssueClientToken(ctx, testAppName, testAppPass, nil)
+	require.ErrorIs(t, err, entity.ErrAppDisabled)
+}
+
+func TestExchangeClientCredentials(t *testing.T) {
+	ctx, auth := newAuth(t)
+	appID := createApp(t, ctx, auth)
+	require.NoError(t, auth.SetAppScopes(ctx, appID, []string{"orders:read", "orders:write"}))
+
+	_, err := auth.ExchangeClientCredentials(ctx, appID, "wrong", nil)
+	require.ErrorIs(t, err, entity.ErrInvalidClient)
+
+	_, err = auth.ExchangeClientCredentials(ctx, appID+100, testAppPass, nil)
+	require.ErrorIs(t, err, entity.ErrInvalidClient)
+
+	_, err = auth.ExchangeClientCredentials(ctx, appID, testAppPass, []string{"orders:delete"})
+	require.ErrorIs(t, err, entity.ErrInvalidScope)
+
+	token, err := auth.ExchangeClientCredentials(ctx, appID, testAppPass, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders:read", "orders:write"}, token.Scope)
+
+	claims, err := auth.ValidateToken(ctx, token.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "app:"+strconv.Itoa(appID), claims.Subject)
+}
+
+func TestClientAuthentication_PeerLockout(t *testing.T) {
+	ctx, auth := newAuth(t, usecase.Lockout(usecase.LockoutPolicy{
+		MaxIPFailures: 3,
+		Window:        time.Minute,
+		Duration:      time.Minute,
+	}))
+	appID := createApp(t, ctx, auth)
+
+	peer := entity.ContextWithClient(ctx, entity.Client{Addr: "203.0.113.7:5000"})
+
+	_, err := auth.IssueClientToken(peer, testAppName, "wrong", nil)
+	require.ErrorIs(t, err, entity.ErrInvalidClient)
+	_, err = auth.ExchangeAuthorizationCode(peer, appID, "wrong", "code", "https://app.example.com/cb", "verifier")
+	require.ErrorIs(t, err, entity.ErrInvalidClient)
+	_, err = auth.ExchangeRefreshToken(peer, appID+100, testAppPass, "refresh")
+	require.ErrorIs(t, err, entity.ErrInvalidClient)
+
+	// The peer is locked, even the right secret is refused now.
+	_, err = auth.IssueClientToken(peer, testAppName, testAppPass, nil)
+	assert.ErrorIs(t, err, entity.ErrLoginLocked)
+	_, err = auth.ExchangeRefreshToken(peer, appID, testAppPass, "refresh")
+	assert.ErrorIs(t, err, entity.ErrLoginLocked)
+
+	other := entity.ContextWithClient(ctx, entity.Client{Addr: "203.0.113.8:5000"})
+	_, err = auth.IssueClientToken(other, testAppName, testAppPass, nil)
+	assert.NoError(t, err)
+}
+
+func TestCustomClaims(t *testing.T) {
+	ctx, auth := newAuth(t)
+	appID := createApp(t, ctx, auth)
+
+	uid, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, auth.SetAppClaims(ctx, appID, map[string]string{"exp": "0"}), entity.ErrReservedClaim)
+	require.ErrorIs(t, auth.SetAppClaims(ctx, appID, map[string]string{"uid": "{{tenant}}"}), entity.ErrReservedClaim)
+	require.ErrorIs(t, auth.SetAppClaims(ctx, appID, map[string]string{"tenant": "{{tenant"}), entity.ErrInvalidClaimTemplate)
+	require.ErrorIs(t, auth.SetAppClaims(ctx, appID, map[string]string{"my tenant": "x"}), entity.ErrInvalidClaimName)
+
+	require.NoError(t, auth.SetAppClaims(ctx, appID, map[string]string{
+		"tenant": "{{tenant}}",
+		"plan":   "pro",
+		"locale": "{{ locale }}",
+		"org":    "{{tenant}}/{{team}}",
+	}))
+
+	app, err := auth.GetApp(ctx, appID)
+	require.NoError(t, err)
+	assert.Equal(t, "{{tenant}}", app.Claims["tenant"])
+
+	require.ErrorIs(t, auth.SetUserAttribute(ctx, uid, "bad key", "x"), entity.ErrInvalidAttribute)
+	require.ErrorIs(t, auth.SetUserAttribute(ctx, uid+100, "tenant", "acme"), entity.ErrUserNotFound)
+	require.NoError(t, auth.SetUserAttribute(ctx, uid, "tenant", "acme"))
+	require.NoError(t, auth.SetUserAttribute(ctx, uid, "locale", "en"))
+	require.NoError(t, auth.SetUserAttribute(ctx, uid, "locale", "de"))
+
+	attributes, err := auth.GetUserAttributes(ctx, uid)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"tenant": "acme", "locale": "de"}, attributes)
+
+	token, err := auth.Login(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	claims, err := auth.ValidateToken(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, uid, claims.UserID)
+	// org references the unset team attribute and is left out.
+	assert.Equal(t, map[string]string{"tenant": "acme", "plan": "pro", "locale": "de"}, claims.Custom)
+
+	require.NoError(t, auth.DeleteUserAttribute(ctx, uid, "locale"))
+	require.NoError(t, auth.DeleteUserAttribute(ctx, uid, "locale"))
+
+	token, err = auth.Login(ctx, testEmail, testPassword, appID)
+	require.NoError(t, err)
+
+	claims, err = auth.ValidateToken(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"tenant": "acme", "plan": "pro"}, claims.Custom)
+
+	_, err = usecase.NewToken(entity.User{ID: uid}, app, entity.SigningKey{Alg: entity.SigningAlgHS256, Secret: testSecret},
+		entity.Access{}, map[string]string{"exp": "0"})
+	require.ErrorIs(t, err, entity.ErrReservedClaim)
+}
+
+func TestAppAuthentication_PeerLockout(t *testing.T) {
+	ctx, auth := newAuth(t, usecase.Lockout(usecase.LockoutPolicy{
+		MaxIPFailures: 3,
+		Window:        time.Minute,
+		Duration:      time.Minute,
+	}))
+	createApp(t, ctx, auth)
+
+	uid, err := auth.RegisterNewUser(ctx, testEmail, testPassword)
+	require.NoError(t, err)
+
+	peer := entity.ContextWithClient(ctx, entity.Client{Addr: "203.0.113.7:5000"})
+
+	_, err = auth.ConfigureApp(peer, testAppName, "wrong", testSecret, 1)
+	require.ErrorIs(t, err, error_.ErrInvalidCredentials)
+	_, err = auth.SetRole(peer, "unknown-app", testAppPass, "editor", nil)
+	require.ErrorIs(t, err, entity.ErrAppNotFound)
+	_, err = auth.RotateSigningKey(peer, testAppName, "wrong")
+	require.ErrorIs(t, err, error_.ErrInvalidCredentials)
+
+	// The peer is locked, even the right password is refused now.
+	_, err = auth.SetSigningAlgorithm(peer, testAppName, testAppPass, entity.SigningAlgRS256)
+	assert.ErrorIs(t, err, entity.ErrLoginLocked)
+	_, err = auth.SetRole(peer, testAppName, testAppPass, "editor", nil)
+	assert.ErrorIs(t, err, entity.ErrLoginLocked)
+	err = auth.AssignRole(peer, testAppName, testAppPass, uid, "editor")
+	assert.ErrorIs(t, err, entity.ErrLoginLocked)
+	err = auth.UnassignRole(peer, testAppName, testAppPass, uid, "editor")
+	assert.ErrorIs(t, err, entity.ErrLoginLocked)
+
+	other := entity.ContextWithClient(ctx, entity.Client{Addr: "203.0.113.8:5000"})
+	_, err = auth.RotateSigningKey(other, testAppName, testAppPass)
+	assert.NoError(t, err)
+}
+
+// blockingRepo makes GetUser hang until its context is done.
+type blockingRepo struct {
+	*repo.AuthRepo
+}
+
+func (r blockingRepo) GetUser(ctx context.Context, email string) (entity.User, error) {
+	<-ctx.Done()
+	return entity.User{}, ctx.Err()
+}
+
+func TestQueryTimeout(t *testing.T) {
+	ctx := context.Background()
+	auth := usecase.New(slogdiscard.NewDiscardLogger(), blockingRepo{repo.New()},
+		usecase.QueryTimeout(50*time.Millisecond),
+	)
+	appID := createApp(t, ctx, auth)
+
+	start := time.Now()
+	_, err := auth.Login(ctx, testEmail, testPassword, appID)
+	require.ErrorIs(t, err, entity.ErrQueryTimeout)
+	assert.Less(t, time.Since(start), time.Second)
+}
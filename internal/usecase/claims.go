@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+)
+
+const (
+	_maxClaimNameLen  = 64
+	_maxClaimValueLen = 1024
+	_templateOpen     = "{{"
+	_templateClose    = "}}"
+)
+
+// _reservedClaims are set by the provider itself in access, client and ID
+// tokens and cannot be overwritten by app claim templates.
+var _reservedClaims = map[string]struct{}{
+	"jti": {}, "uid": {}, "email": {}, "exp": {}, "app_id": {}, "roles": {}, "scope": {},
+	"sub": {}, "iss": {}, "aud": {}, "iat": {}, "nbf": {}, "auth_time": {}, "nonce": {},
+	"email_verified": {},
+}
+
+func reservedClaim(name string) bool {
+	_, ok := _reservedClaims[name]
+
+	return ok
+}
+
+// SetAppClaims replaces the custom claims added to access tokens of the app.
+// Templates are literal text that may reference user attributes as {{key}};
+// see renderClaim. Reserved claim names fail with entity.ErrReservedClaim.
+func (a *AuthUseCase) SetAppClaims(ctx context.Context, id_ int, claims map[string]string) error {
+	const op = "internal - usecase - Auth.SetAppClaims"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("service_id", id_),
+	)
+
+	log.Info("changing app claims")
+
+	for name, template := range claims {
+		if err := validateClaimTemplate(name, template); err != nil {
+			log.Info("invalid claim template", slog.String("claim", name), error_.Err(err))
+
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if _, err := a.GetApp(ctx, id_); err != nil {
+		return err
+	}
+
+	if err := a.repo.SetAppClaims(ctx, id_, claims); err != nil {
+		log.Error("failed to save app claims", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventAppUpdated, AppID: id_, Details: "claims changed"})
+
+	return nil
+}
+
+// SetUserAttribute sets the attribute of the user that app claim templates
+// can reference.
+func (a *AuthUseCase) SetUserAttribute(ctx context.Context, userID int, key string, value string) error {
+	const op = "internal - usecase - Auth.SetUserAttribute"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("user_id", userID),
+		slog.String("key", key),
+	)
+
+	log.Info("setting user attribute")
+
+	if !validClaimName(key) || len(value) > _maxClaimValueLen {
+		return fmt.Errorf("%s: %w", op, entity.ErrInvalidAttribute)
+	}
+
+	if _, err := a.attributesUser(ctx, log, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.repo.SetUserAttribute(ctx, userID, key, value); err != nil {
+		log.Error("failed to save user attribute", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteUserAttribute removes the attribute of the user. Removing a missing
+// attribute is not an error.
+func (a *AuthUseCase) DeleteUserAttribute(ctx context.Context, userID int, key string) error {
+	const op = "internal - usecase - Auth.DeleteUserAttribute"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("user_id", userID),
+		slog.String("key", key),
+	)
+
+	log.Info("deleting user attribute")
+
+	if _, err := a.attributesUser(ctx, log, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := a.repo.DeleteUserAttribute(ctx, userID, key); err != nil {
+		log.Error("failed to delete user attribute", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (a *AuthUseCase) GetUserAttributes(ctx context.Context, userID int) (map[string]string, error) {
+	const op = "internal - usecase - Auth.GetUserAttributes"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("user_id", userID),
+	)
+
+	if _, err := a.attributesUser(ctx, log, userID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	attributes, err := a.repo.GetUserAttributes(ctx, userID)
+	if err != nil {
+		log.Error("failed to get user attributes", error_.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return attributes, nil
+}
+
+func (a *AuthUseCase) attributesUser(ctx context.Context, log *slog.Logger, userID int) (entity.User, error) {
+	user, err := a.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, entity.ErrUserNotFound) {
+			log.Error("failed to get user", error_.Err(err))
+		}
+
+		return entity.User{}, err
+	}
+
+	return user, nil
+}
+
+// customClaims renders the claim templates of app for user.
+func (a *AuthUseCase) customClaims(ctx context.Context, user entity.User, app entity.App) (map[string]string, error) {
+	templates, err := a.repo.GetAppClaims(ctx, app.ID)
+	if err != nil || len(templates) == 0 {
+		return nil, err
+	}
+
+	attributes, err := a.repo.GetUserAttributes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := make(map[string]string, len(templates))
+	for name, template := range templates {
+		if value, ok := renderClaim(template, attributes); ok {
+			claims[name] = value
+		}
+	}
+
+	return claims, nil
+}
+
+// renderClaim replaces every {{key}} in template with the user attribute key.
+// Surrounding spaces inside the braces are ignored. The claim is left out if
+// any referenced attribute is not set.
+func renderClaim(template string, attributes map[string]string) (string, bool) {
+	var b strings.Builder
+	for {
+		before, rest, found := strings.Cut(template, _templateOpen)
+		b.WriteString(before)
+		if !found {
+			return b.String(), true
+		}
+
+		key, after, _ := strings.Cut(rest, _templateClose)
+		value, ok := attributes[strings.TrimSpace(key)]
+		if !ok {
+			return "", false
+		}
+		b.WriteString(value)
+		template = after
+	}
+}
+
+func validateClaimTemplate(name string, template string) error {
+	if !validClaimName(name) {
+		return entity.ErrInvalidClaimName
+	}
+
+	if reservedClaim(name) {
+		return entity.ErrReservedClaim
+	}
+
+	if len(template) > _maxClaimValueLen {
+		return entity.ErrInvalidClaimTemplate
+	}
+
+	for {
+		_, rest, found := strings.Cut(template, _templateOpen)
+		if !found {
+			return nil
+		}
+
+		key, after, closed := strings.Cut(rest, _templateClose)
+		if !closed || !validClaimName(strings.TrimSpace(key)) {
+			return entity.ErrInvalidClaimTemplate
+		}
+		template = after
+	}
+}
+
+// validClaimName accepts up to 64 ascii letters, digits and _ - . : as claim
+// names and attribute keys.
+func validClaimName(name string) bool {
+	if name == "" || len(name) > _maxClaimNameLen {
+		return false
+	}
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '_', c == '-', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
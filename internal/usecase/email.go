@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+
+	"golang.org/x/net/idna"
+)
+
+// normalizeEmail returns the canonical form users are stored and looked up
+// by: the whole address lowercased and the domain converted to its IDNA ASCII
+// form, so Bob@Example.com and bob@example.com are the same account.
+func normalizeEmail(email string) (string, error) {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 || at == len(email)-1 {
+		return "", entity.ErrInvalidEmail
+	}
+
+	domain, err := idna.Lookup.ToASCII(email[at+1:])
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", entity.ErrInvalidEmail, err)
+	}
+
+	return strings.ToLower(email[:at]) + "@" + strings.ToLower(domain), nil
+}
+
+// NormalizeEmails brings emails stored before normalization to their
+// canonical form. The SQL migration only lowercases ASCII, so the rest (non-ASCII
+// local parts, IDN domains) is done here with normalizeEmail. An account whose
+// canonical email is already taken is left as is and flagged in
+// email_collisions along with the holder. It returns how many emails changed.
+func (a *AuthUseCase) NormalizeEmails(ctx context.Context) (int, error) {
+	const op = "internal - usecase - Auth.NormalizeEmails"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	users, err := a.repo.ListUnnormalizedEmails(ctx)
+	if err != nil {
+		log.Error("failed to list unnormalized emails", error_.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	normalized := 0
+	for _, user := range users {
+		email, err := normalizeEmail(user.Email)
+		if err != nil {
+			log.Warn("stored email is invalid, fix it manually", slog.Int("user_id", user.ID), error_.Err(err))
+
+			continue
+		}
+
+		if email == user.Email {
+			continue
+		}
+
+		_, err = a.repo.UpdateUserEmail(ctx, user.ID, email)
+		if errors.Is(err, entity.ErrUserExists) {
+			if err := a.repo.AddEmailCollision(ctx, email, user.ID); err != nil {
+				log.Error("failed to flag email collision", error_.Err(err))
+
+				return normalized, fmt.Errorf("%s: %w", op, err)
+			}
+
+			continue
+		}
+		if err != nil {
+			log.Error("failed to update email", error_.Err(err))
+
+			return normalized, fmt.Errorf("%s: %w", op, err)
+		}
+
+		normalized++
+	}
+
+	if normalized > 0 {
+		log.Info("emails normalized", slog.Int("count", normalized))
+	}
+
+	return normalized, nil
+}
+
+// ReportEmailCollisions logs the accounts found to share an email when emails
+// were normalized. It returns how many collisions are unresolved.
+func (a *AuthUseCase) ReportEmailCollisions(ctx context.Context) (int, error) {
+	const op = "internal - usecase - Auth.ReportEmailCollisions"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	collisions, err := a.repo.ListEmailCollisions(ctx)
+	if err != nil {
+		log.Error("failed to list email collisions", error_.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, collision := range collisions {
+		log.Warn("accounts with emails normalizing to the same address, merge or rename them",
+			slog.String("email", collision.Email),
+			slog.Any("user_ids", collision.UserIDs),
+		)
+	}
+
+	return len(collisions), nil
+}
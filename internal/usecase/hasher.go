@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var errUnsupportedPasswordHash = errors.New("unsupported password hash")
+
+// Argon2idHasher hashes passwords with argon2id into PHC strings of the form
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>.
+type Argon2idHasher struct {
+	// Memory is counted in KiB.
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// BcryptHasher hashes passwords with bcrypt at Cost.
+type BcryptHasher struct {
+	Cost int
+}
+
+// _defaultPasswordHasher follows the OWASP minimum for argon2id.
+var _defaultPasswordHasher = Argon2idHasher{
+	Memory:      19 * 1024,
+	Iterations:  2,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func (h Argon2idHasher) Hash(password string) ([]byte, error) {
+	salt := make([]byte, h.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.Iterations, h.Memory, h.Parallelism, h.KeyLength)
+
+	return []byte(argon2idParams{
+		memory:      h.Memory,
+		iterations:  h.Iterations,
+		parallelism: h.Parallelism,
+		salt:        salt,
+		key:         key,
+	}.String()), nil
+}
+
+func (h Argon2idHasher) Verify(hash []byte, password string) (bool, error) {
+	return verifyPassword(hash, password)
+}
+
+func (h Argon2idHasher) NeedsRehash(hash []byte) bool {
+	params, err := parseArgon2id(string(hash))
+	if err != nil {
+		return true
+	}
+
+	return params.memory != h.Memory ||
+		params.iterations != h.Iterations ||
+		params.parallelism != h.Parallelism ||
+		uint32(len(params.salt)) != h.SaltLength ||
+		uint32(len(params.key)) != h.KeyLength
+}
+
+func (h BcryptHasher) Hash(password string) ([]byte, error) {
+	return bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+}
+
+func (h BcryptHasher) Verify(hash []byte, password string) (bool, error) {
+	return verifyPassword(hash, password)
+}
+
+func (h BcryptHasher) NeedsRehash(hash []byte) bool {
+	cost, err := bcrypt.Cost(hash)
+	if err != nil {
+		return true
+	}
+
+	return cost != h.Cost
+}
+
+// verifyPassword checks password against a hash of any supported algorithm,
+// so hashes stay verifiable after the configured hasher changes.
+func verifyPassword(hash []byte, password string) (bool, error) {
+	switch {
+	case strings.HasPrefix(string(hash), "$argon2id$"):
+		params, err := parseArgon2id(string(hash))
+		if err != nil {
+			return false, err
+		}
+
+		key := argon2.IDKey([]byte(password), params.salt, params.iterations, params.memory, params.parallelism, uint32(len(params.key)))
+
+		return subtle.ConstantTimeCompare(key, params.key) == 1, nil
+	case strings.HasPrefix(string(hash), "$2"):
+		err := bcrypt.CompareHashAndPassword(hash, []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		return true, nil
+	default:
+		return false, errUnsupportedPasswordHash
+	}
+}
+
+type argon2idParams struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+func (p argon2idParams) String() string {
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.memory, p.iterations, p.parallelism,
+		base64.RawStdEncoding.EncodeToString(p.salt),
+		base64.RawStdEncoding.EncodeToString(p.key),
+	)
+}
+
+func parseArgon2id(hash string) (argon2idParams, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return argon2idParams{}, errUnsupportedPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return argon2idParams{}, fmt.Errorf("%w: %w", errUnsupportedPasswordHash, err)
+	}
+	if version != argon2.Version {
+		return argon2idParams{}, fmt.Errorf("%w: argon2 version %d", errUnsupportedPasswordHash, version)
+	}
+
+	var p argon2idParams
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.iterations, &p.parallelism); err != nil {
+		return argon2idParams{}, fmt.Errorf("%w: %w", errUnsupportedPasswordHash, err)
+	}
+
+	var err error
+	if p.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return argon2idParams{}, fmt.Errorf("%w: %w", errUnsupportedPasswordHash, err)
+	}
+	if p.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return argon2idParams{}, fmt.Errorf("%w: %w", errUnsupportedPasswordHash, err)
+	}
+	if len(p.key) == 0 {
+		return argon2idParams{}, errUnsupportedPasswordHash
+	}
+
+	return p, nil
+}
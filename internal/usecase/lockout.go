@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+)
+
+// LockoutPolicy limits password guessing. Failed logins are counted per email
+// and per peer IP; failures older than Window are forgotten.
+type LockoutPolicy struct {
+	// MaxFailures locks an email for Duration, zero disables the lockout.
+	MaxFailures int
+	// MaxIPFailures locks a peer IP for Duration, zero disables the lockout.
+	MaxIPFailures int
+	Window        time.Duration
+	Duration      time.Duration
+	// DelayAfter failures of an email each next attempt has to wait BaseDelay,
+	// doubled for every further failure and capped at MaxDelay.
+	DelayAfter int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+var _defaultLockoutPolicy = LockoutPolicy{
+	MaxFailures:   5,
+	MaxIPFailures: 100,
+	Window:        15 * time.Minute,
+	Duration:      15 * time.Minute,
+	DelayAfter:    3,
+	BaseDelay:     time.Second,
+	MaxDelay:      30 * time.Second,
+}
+
+// delay returns how long to wait after the last of failures before the next attempt.
+func (p LockoutPolicy) delay(failures int) time.Duration {
+	if p.DelayAfter <= 0 || failures < p.DelayAfter {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := p.DelayAfter; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, p.MaxDelay)
+}
+
+// checkLoginAllowed rejects logins for locked or throttled emails and peers.
+func (a *AuthUseCase) checkLoginAllowed(ctx context.Context, email string) error {
+	now := time.Now()
+
+	failure, err := a.repo.GetLoginFailure(ctx, emailLockoutKey(email))
+	if err != nil {
+		return err
+	}
+
+	if failure.LockedUntil.After(now) {
+		return entity.ErrLoginLocked
+	}
+
+	if now.Before(failure.LastFailedAt.Add(a.lockout.delay(failure.Failures))) {
+		return entity.ErrLoginThrottled
+	}
+
+	return a.checkPeerAllowed(ctx)
+}
+
+// checkPeerAllowed rejects requests of a peer IP locked for failed logins or
+// failed client authentications.
+func (a *AuthUseCase) checkPeerAllowed(ctx context.Context) error {
+	key, ok := ipLockoutKey(ctx)
+	if !ok {
+		return nil
+	}
+
+	failure, err := a.repo.GetLoginFailure(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if failure.LockedUntil.After(time.Now()) {
+		return entity.ErrLoginLocked
+	}
+
+	return nil
+}
+
+// registerLoginFailure counts a failed login and locks the email or the peer
+// once they exceed the policy limits.
+func (a *AuthUseCase) registerLoginFailure(ctx context.Context, log *slog.Logger, email string) {
+	a.countLoginFailure(ctx, log, emailLockoutKey(email), a.lockout.MaxFailures)
+	a.registerPeerFailure(ctx, log)
+}
+
+// registerPeerFailure counts a failed client authentication against the peer
+// IP, so guessing app secrets locks the peer like guessing passwords does.
+func (a *AuthUseCase) registerPeerFailure(ctx context.Context, log *slog.Logger) {
+	if key, ok := ipLockoutKey(ctx); ok {
+		a.countLoginFailure(ctx, log, key, a.lockout.MaxIPFailures)
+	}
+}
+
+func (a *AuthUseCase) countLoginFailure(ctx context.Context, log *slog.Logger, key string, limit int) {
+	now := time.Now()
+
+	failures, err := a.repo.RecordLoginFailure(ctx, key, now, now.Add(-a.lockout.Window))
+	if err != nil {
+		log.Error("failed to record login failure", error_.Err(err))
+
+		return
+	}
+
+	if limit <= 0 || failures < limit {
+		return
+	}
+
+	if _, err := a.repo.LockLogin(ctx, key, now.Add(a.lockout.Duration)); err != nil {
+		log.Error("failed to lock login", error_.Err(err))
+
+		return
+	}
+
+	log.Warn("login locked", slog.String("key", key), slog.Int("failures", failures))
+}
+
+// resetLoginFailures forgets the failures of email after a successful login.
+func (a *AuthUseCase) resetLoginFailures(ctx context.Context, log *slog.Logger, email string) {
+	if err := a.repo.DeleteLoginFailure(ctx, emailLockoutKey(email)); err != nil {
+		log.Error("failed to reset login failures", error_.Err(err))
+	}
+}
+
+func emailLockoutKey(email string) string {
+	return "email:" + email
+}
+
+func ipLockoutKey(ctx context.Context) (string, bool) {
+	addr := entity.ClientFromContext(ctx).Addr
+	if addr == "" {
+		return "", false
+	}
+
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+
+	return "ip:" + addr, true
+}
@@ -0,0 +1,413 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+)
+
+// EnrollTOTP starts the TOTP enrollment of the owner of accessToken. A new
+// secret replaces any enrollment that was not confirmed yet; it guards logins
+// only after ConfirmTOTP.
+func (a *AuthUseCase) EnrollTOTP(ctx context.Context, accessToken string) (entity.TOTPEnrollment, error) {
+	const op = "internal - usecase - Auth.EnrollTOTP"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	user, err := a.tokenUser(ctx, accessToken)
+	if err != nil {
+		log.Warn("failed to authenticate user", error_.Err(err))
+
+		return entity.TOTPEnrollment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int("user_id", user.ID))
+	log.Info("enrolling totp")
+
+	secret, err := newTOTPSecret()
+	if err != nil {
+		log.Error("failed to generate totp secret", error_.Err(err))
+
+		return entity.TOTPEnrollment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	saved, err := a.repo.SaveTOTP(ctx, entity.TOTP{UserID: user.ID, Secret: secret, CreatedAt: time.Now()})
+	if err != nil {
+		log.Error("failed to save totp", error_.Err(err))
+
+		return entity.TOTPEnrollment{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if saved == 0 {
+		log.Info("totp already enabled")
+
+		return entity.TOTPEnrollment{}, fmt.Errorf("%s: %w", op, entity.ErrTOTPEnabled)
+	}
+
+	return entity.TOTPEnrollment{
+		Secret: _base32.EncodeToString(secret),
+		URI:    totpURI(a.totpIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables the enrollment started by EnrollTOTP once code proves
+// the authenticator app is set up. It returns recovery codes, which are shown
+// to the user only this once.
+func (a *AuthUseCase) ConfirmTOTP(ctx context.Context, accessToken string, code string) ([]string, error) {
+	const op = "internal - usecase - Auth.ConfirmTOTP"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	user, err := a.tokenUser(ctx, accessToken)
+	if err != nil {
+		log.Warn("failed to authenticate user", error_.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int("user_id", user.ID))
+	log.Info("confirming totp")
+
+	totp, err := a.repo.GetTOTP(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, entity.ErrTOTPNotFound) {
+			log.Info("totp enrollment not started")
+
+			return nil, fmt.Errorf("%s: %w", op, entity.ErrTOTPNotFound)
+		}
+
+		log.Error("failed to get totp", error_.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if totp.Confirmed {
+		log.Info("totp already enabled")
+
+		return nil, fmt.Errorf("%s: %w", op, entity.ErrTOTPEnabled)
+	}
+
+	step, ok := matchTOTP(totp.Secret, code, time.Now())
+	if !ok {
+		log.Info("invalid totp code")
+
+		return nil, fmt.Errorf("%s: %w", op, entity.ErrInvalidMFACode)
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		log.Error("failed to generate recovery codes", error_.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	confirmed, err := a.repo.ConfirmTOTP(ctx, user.ID, step, hashes)
+	if err != nil {
+		log.Error("failed to confirm totp", error_.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if confirmed == 0 {
+		log.Info("totp enrollment changed concurrently")
+
+		return nil, fmt.Errorf("%s: %w", op, entity.ErrTOTPNotFound)
+	}
+
+	a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventMFAEnabled, UserID: user.ID, Email: user.Email})
+
+	log.Info("totp enabled")
+
+	return codes, nil
+}
+
+// DisableTOTP turns TOTP off for the owner of accessToken. code is a current
+// TOTP code or an unused recovery code.
+func (a *AuthUseCase) DisableTOTP(ctx context.Context, accessToken string, code string) error {
+	const op = "internal - usecase - Auth.DisableTOTP"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	user, err := a.tokenUser(ctx, accessToken)
+	if err != nil {
+		log.Warn("failed to authenticate user", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int("user_id", user.ID))
+	log.Info("disabling totp")
+
+	if err := a.checkLoginAllowed(ctx, user.Email); err != nil {
+		log.Warn("totp disabling rejected", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.useMFACode(ctx, log, user, code); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := a.repo.DeleteTOTP(ctx, user.ID); err != nil {
+		log.Error("failed to delete totp", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventMFADisabled, UserID: user.ID, Email: user.Email})
+
+	log.Info("totp disabled")
+
+	return nil
+}
+
+// VerifyMFALogin completes a login that failed with *entity.MFARequiredError.
+// code is a current TOTP code or an unused recovery code. The refresh token of
+// the pair is only set if the login asked for one.
+func (a *AuthUseCase) VerifyMFALogin(ctx context.Context, challenge string, code string) (entity.TokenPair, error) {
+	const op = "internal - usecase - Auth.VerifyMFALogin"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("verifying mfa login")
+
+	pending, user, app, err := a.verifyMFAChallenge(ctx, log, challenge, code)
+	if err != nil {
+		return entity.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if pending.WithRefresh {
+		familyID, err := newOpaqueToken()
+		if err != nil {
+			log.Error("failed to generate token family", error_.Err(err))
+
+			return entity.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		pair, err := a.issueTokenPair(ctx, user, app, familyID, nil)
+		if err != nil {
+			log.Error("failed to issue tokens", error_.Err(err))
+
+			return entity.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return pair, nil
+	}
+
+	expiresAt := time.Now().Add(time.Duration(app.TTLHours) * time.Hour)
+
+	token, err := a.newToken(ctx, user, app)
+	if err != nil {
+		log.Error("failed to generate token", error_.Err(err))
+
+		return entity.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entity.TokenPair{AccessToken: token, ExpiresAt: expiresAt}, nil
+}
+
+// verifyMFAChallenge checks code against the second factor of the login
+// waiting on challenge, consumes the challenge and completes the login.
+func (a *AuthUseCase) verifyMFAChallenge(ctx context.Context, log *slog.Logger, challenge string, code string) (entity.MFAChallenge, entity.User, entity.App, error) {
+	tokenHash := hashToken(challenge)
+
+	pending, err := a.repo.GetMFAChallenge(ctx, tokenHash, time.Now())
+	if err != nil {
+		if errors.Is(err, entity.ErrMFAChallengeNotFound) {
+			log.Warn("invalid mfa challenge")
+
+			return entity.MFAChallenge{}, entity.User{}, entity.App{}, entity.ErrMFAChallengeNotFound
+		}
+
+		log.Error("failed to get mfa challenge", error_.Err(err))
+
+		return entity.MFAChallenge{}, entity.User{}, entity.App{}, err
+	}
+
+	log = log.With(slog.Int("user_id", pending.UserID), slog.Int("app_id", pending.AppID))
+
+	user, err := a.repo.GetUserByID(ctx, pending.UserID)
+	if err != nil {
+		log.Error("failed to get user", error_.Err(err))
+
+		return entity.MFAChallenge{}, entity.User{}, entity.App{}, err
+	}
+
+	if err := a.checkLoginAllowed(ctx, user.Email); err != nil {
+		if errors.Is(err, entity.ErrLoginLocked) || errors.Is(err, entity.ErrLoginThrottled) {
+			log.Warn("mfa login rejected", error_.Err(err))
+		} else {
+			log.Error("failed to check login failures", error_.Err(err))
+		}
+
+		return entity.MFAChallenge{}, entity.User{}, entity.App{}, err
+	}
+
+	if err := a.useMFACode(ctx, log, user, code); err != nil {
+		if errors.Is(err, entity.ErrInvalidMFACode) {
+			a.recordEvent(ctx, entity.AuthEvent{
+				Type:    entity.EventLoginFailure,
+				AppID:   pending.AppID,
+				UserID:  user.ID,
+				Email:   user.Email,
+				Details: "invalid mfa code",
+			})
+		}
+
+		return entity.MFAChallenge{}, entity.User{}, entity.App{}, err
+	}
+
+	// Deleting the challenge only after the code was accepted keeps it usable
+	// for retries, while the count makes it single-use under concurrency.
+	used, err := a.repo.DeleteMFAChallenge(ctx, tokenHash)
+	if err != nil {
+		log.Error("failed to delete mfa challenge", error_.Err(err))
+
+		return entity.MFAChallenge{}, entity.User{}, entity.App{}, err
+	}
+	if used == 0 {
+		log.Warn("mfa challenge already used")
+
+		return entity.MFAChallenge{}, entity.User{}, entity.App{}, entity.ErrMFAChallengeNotFound
+	}
+
+	app, err := a.repo.GetAppForUser(ctx, pending.AppID)
+	if err != nil {
+		log.Error("failed to get app", error_.Err(err))
+
+		return entity.MFAChallenge{}, entity.User{}, entity.App{}, err
+	}
+
+	if app.Disabled {
+		log.Warn("mfa login to disabled app")
+
+		return entity.MFAChallenge{}, entity.User{}, entity.App{}, entity.ErrAppDisabled
+	}
+
+	a.loginSucceeded(ctx, log, user, app)
+
+	return pending, user, app, nil
+}
+
+// requireMFA issues a challenge and returns it as *entity.MFARequiredError
+// when user has TOTP enabled. It returns nil for users without it.
+func (a *AuthUseCase) requireMFA(ctx context.Context, log *slog.Logger, user entity.User, app entity.App, withRefresh bool) error {
+	totp, err := a.repo.GetTOTP(ctx, user.ID)
+	if errors.Is(err, entity.ErrTOTPNotFound) {
+		return nil
+	}
+	if err != nil {
+		log.Error("failed to get totp", error_.Err(err))
+
+		return err
+	}
+
+	if !totp.Confirmed {
+		return nil
+	}
+
+	token, err := newOpaqueToken()
+	if err != nil {
+		log.Error("failed to generate mfa challenge", error_.Err(err))
+
+		return err
+	}
+
+	now := time.Now()
+	challenge := entity.MFAChallenge{
+		TokenHash:   hashToken(token),
+		UserID:      user.ID,
+		AppID:       app.ID,
+		WithRefresh: withRefresh,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(a.challengeTTL),
+	}
+
+	if err := a.repo.InsertMFAChallenge(ctx, challenge); err != nil {
+		log.Error("failed to save mfa challenge", error_.Err(err))
+
+		return err
+	}
+
+	log.Info("mfa required")
+
+	return &entity.MFARequiredError{Challenge: token, ExpiresAt: challenge.ExpiresAt}
+}
+
+// useMFACode accepts a TOTP code newer than the last accepted one or consumes
+// a recovery code of user. A wrong code counts as a failed login.
+func (a *AuthUseCase) useMFACode(ctx context.Context, log *slog.Logger, user entity.User, code string) error {
+	totp, err := a.repo.GetTOTP(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, entity.ErrTOTPNotFound) {
+			return entity.ErrTOTPNotFound
+		}
+
+		log.Error("failed to get totp", error_.Err(err))
+
+		return err
+	}
+
+	if !totp.Confirmed {
+		return entity.ErrTOTPNotFound
+	}
+
+	if step, ok := matchTOTP(totp.Secret, code, time.Now()); ok {
+		used, err := a.repo.UseTOTPStep(ctx, user.ID, step)
+		if err != nil {
+			log.Error("failed to record totp step", error_.Err(err))
+
+			return err
+		}
+		if used == 1 {
+			return nil
+		}
+
+		log.Warn("totp code replayed")
+	} else {
+		used, err := a.repo.UseRecoveryCode(ctx, user.ID, hashRecoveryCode(code))
+		if err != nil {
+			log.Error("failed to use recovery code", error_.Err(err))
+
+			return err
+		}
+		if used == 1 {
+			a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventRecoveryCodeUsed, UserID: user.ID, Email: user.Email})
+
+			log.Info("recovery code used")
+
+			return nil
+		}
+	}
+
+	log.Info("invalid mfa code")
+
+	a.registerLoginFailure(ctx, log, user.Email)
+
+	return entity.ErrInvalidMFACode
+}
+
+// tokenUser resolves the user an access token was issued to.
+func (a *AuthUseCase) tokenUser(ctx context.Context, accessToken string) (entity.User, error) {
+	claims, err := a.ValidateToken(ctx, accessToken)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	if claims.Subject != "" {
+		return entity.User{}, entity.ErrUserNotFound
+	}
+
+	return a.repo.GetUserByID(ctx, claims.UserID)
+}
@@ -0,0 +1,82 @@
+// Package notifier implements usecase.Notifier for local development. Both
+// notifiers expose the secret tokens they deliver and must not be used in
+// production.
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+// Log writes notifications to the logger.
+type Log struct {
+	log *slog.Logger
+}
+
+func NewLog(log *slog.Logger) *Log {
+	return &Log{log: log}
+}
+
+func (n *Log) Notify(_ context.Context, notification entity.Notification) error {
+	n.log.Info("notification",
+		slog.String("type", notification.Type),
+		slog.String("email", notification.Email),
+		slog.String("token", notification.Token),
+		slog.Time("expires_at", notification.ExpiresAt),
+	)
+
+	return nil
+}
+
+// File appends notifications to a file, one JSON object per line.
+type File struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFile(path string) *File {
+	return &File{path: path}
+}
+
+type fileRecord struct {
+	Type      string    `json:"type"`
+	Email     string    `json:"email"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (n *File) Notify(_ context.Context, notification entity.Notification) error {
+	const op = "internal - usecase - notifier - File.Notify"
+
+	line, err := json.Marshal(fileRecord{
+		Type:      notification.Type,
+		Email:     notification.Email,
+		Token:     notification.Token,
+		ExpiresAt: notification.ExpiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
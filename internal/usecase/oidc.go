@@ -0,0 +1,505 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// CheckAuthorizationRequest resolves the app of an authorization request and
+// makes sure it may receive codes at the redirect uri. Errors of this check
+// must be shown to the user instead of being sent to the redirect uri.
+func (a *AuthUseCase) CheckAuthorizationRequest(ctx context.Context, req entity.AuthorizationRequest) (entity.App, error) {
+	const op = "internal - usecase - Auth.CheckAuthorizationRequest"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", req.ClientID),
+	)
+
+	app, err := a.repo.GetAppForUser(ctx, req.ClientID)
+	if err != nil {
+		if errors.Is(err, entity.ErrAppNotFound) {
+			log.Info("unknown client")
+
+			return entity.App{}, fmt.Errorf("%s: %w", op, entity.ErrInvalidClient)
+		}
+
+		log.Error("failed to get app", error_.Err(err))
+
+		return entity.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if app.Disabled {
+		log.Warn("authorization request of disabled app")
+
+		return entity.App{}, fmt.Errorf("%s: %w", op, entity.ErrAppDisabled)
+	}
+
+	uris, err := a.repo.GetAppRedirectURIs(ctx, app.ID)
+	if err != nil {
+		log.Error("failed to get app redirect uris", error_.Err(err))
+
+		return entity.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !slices.Contains(uris, req.RedirectURI) {
+		log.Warn("redirect uri not registered", slog.String("redirect_uri", req.RedirectURI))
+
+		return entity.App{}, fmt.Errorf("%s: %w", op, entity.ErrRedirectURINotAllowed)
+	}
+
+	return app, nil
+}
+
+// Authorize logs the user in with a password on behalf of the app of req and
+// returns an authorization code for it. Users with TOTP enabled get
+// *entity.MFARequiredError instead, to be completed with AuthorizeMFA.
+func (a *AuthUseCase) Authorize(ctx context.Context, req entity.AuthorizationRequest, email string, password string) (string, error) {
+	const op = "internal - usecase - Auth.Authorize"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("username", email),
+		slog.Int("app_id", req.ClientID),
+	)
+
+	if _, err := a.CheckAuthorizationRequest(ctx, req); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, app, err := a.authenticate(ctx, log, email, password, req.ClientID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.requireMFA(ctx, log, user, app, false); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.loginSucceeded(ctx, log, user, app)
+
+	code, err := a.issueAuthorizationCode(ctx, user, app, req)
+	if err != nil {
+		log.Error("failed to issue authorization code", error_.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return code, nil
+}
+
+// AuthorizeMFA completes an Authorize that failed with
+// *entity.MFARequiredError. code is a current TOTP code or an unused recovery
+// code.
+func (a *AuthUseCase) AuthorizeMFA(ctx context.Context, req entity.AuthorizationRequest, challenge string, code string) (string, error) {
+	const op = "internal - usecase - Auth.AuthorizeMFA"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", req.ClientID),
+	)
+
+	if _, err := a.CheckAuthorizationRequest(ctx, req); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	pending, user, app, err := a.verifyMFAChallenge(ctx, log, challenge, code)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if pending.AppID != req.ClientID {
+		log.Warn("mfa challenge issued for another app", slog.Int("challenge_app_id", pending.AppID))
+
+		return "", fmt.Errorf("%s: %w", op, entity.ErrMFAChallengeNotFound)
+	}
+
+	authCode, err := a.issueAuthorizationCode(ctx, user, app, req)
+	if err != nil {
+		log.Error("failed to issue authorization code", error_.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return authCode, nil
+}
+
+// ExchangeAuthorizationCode redeems code for tokens. The client authenticates
+// with its app id and password, and has to present the redirect uri and the
+// PKCE verifier of the authorization request. A code is redeemed only once;
+// presenting it again revokes the tokens it was redeemed for.
+func (a *AuthUseCase) ExchangeAuthorizationCode(ctx context.Context, clientID int, clientSecret string, code string, redirectURI string, codeVerifier string) (entity.OIDCTokens, error) {
+	const op = "internal - usecase - Auth.ExchangeAuthorizationCode"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", clientID),
+	)
+
+	log.Info("exchanging authorization code")
+
+	app, err := a.authenticateClient(ctx, log, clientID, clientSecret)
+	if err != nil {
+		return entity.OIDCTokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	grant, err := a.repo.ConsumeAuthorizationCode(ctx, hashToken(code), time.Now())
+	if err != nil {
+		if errors.Is(err, entity.ErrAuthorizationCodeNotFound) {
+			log.Warn("invalid authorization code")
+
+			return entity.OIDCTokens{}, fmt.Errorf("%s: %w", op, entity.ErrAuthorizationCodeNotFound)
+		}
+
+		log.Error("failed to consume authorization code", error_.Err(err))
+
+		return entity.OIDCTokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int("user_id", grant.UserID))
+
+	if grant.Used {
+		return entity.OIDCTokens{}, fmt.Errorf("%s: %w", op, a.revokeCodeTokens(ctx, log, grant))
+	}
+
+	switch {
+	case grant.AppID != app.ID:
+		log.Warn("authorization code issued for another app", slog.Int("code_app_id", grant.AppID))
+
+		return entity.OIDCTokens{}, fmt.Errorf("%s: %w", op, entity.ErrInvalidGrant)
+	case grant.RedirectURI != redirectURI:
+		log.Warn("redirect uri mismatch", slog.String("redirect_uri", redirectURI))
+
+		return entity.OIDCTokens{}, fmt.Errorf("%s: %w", op, entity.ErrInvalidGrant)
+	case !verifyCodeChallenge(grant.CodeChallenge, codeVerifier):
+		log.Warn("invalid code verifier")
+
+		return entity.OIDCTokens{}, fmt.Errorf("%s: %w", op, entity.ErrInvalidGrant)
+	}
+
+	user, err := a.repo.GetUserByID(ctx, grant.UserID)
+	if err != nil {
+		log.Error("failed to get user", error_.Err(err))
+
+		return entity.OIDCTokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	scope := strings.Fields(grant.Scope)
+	tokens := entity.OIDCTokens{Scope: grant.Scope}
+
+	var familyID string
+	if slices.Contains(scope, entity.ScopeOfflineAccess) {
+		familyID, err = newOpaqueToken()
+		if err != nil {
+			log.Error("failed to generate token family", error_.Err(err))
+
+			return entity.OIDCTokens{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		tokens.TokenPair, err = a.issueTokenPair(ctx, user, app, familyID, nil)
+		if err != nil {
+			log.Error("failed to issue tokens", error_.Err(err))
+
+			return entity.OIDCTokens{}, fmt.Errorf("%s: %w", op, err)
+		}
+	} else {
+		tokens.ExpiresAt = time.Now().Add(time.Duration(app.TTLHours) * time.Hour)
+
+		tokens.AccessToken, err = a.newToken(ctx, user, app)
+		if err != nil {
+			log.Error("failed to generate token", error_.Err(err))
+
+			return entity.OIDCTokens{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := a.recordCodeTokens(ctx, grant, tokens, familyID); err != nil {
+		log.Error("failed to record tokens of authorization code", error_.Err(err))
+
+		return entity.OIDCTokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if slices.Contains(scope, entity.ScopeOpenID) {
+		key, err := a.activeSigningKey(ctx, app)
+		if err != nil {
+			log.Error("failed to get signing key", error_.Err(err))
+
+			return entity.OIDCTokens{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		tokens.IDToken, err = NewIDToken(user, app, key, a.issuer, grant)
+		if err != nil {
+			log.Error("failed to generate id token", error_.Err(err))
+
+			return entity.OIDCTokens{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	log.Info("authorization code exchanged")
+
+	return tokens, nil
+}
+
+// ExchangeRefreshToken is Refresh for an authenticated OAuth client. The
+// refresh token has to be issued for the app of the client.
+func (a *AuthUseCase) ExchangeRefreshToken(ctx context.Context, clientID int, clientSecret string, refreshToken string) (entity.TokenPair, error) {
+	const op = "internal - usecase - Auth.ExchangeRefreshToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", clientID),
+	)
+
+	app, err := a.authenticateClient(ctx, log, clientID, clientSecret)
+	if err != nil {
+		return entity.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	current, err := a.repo.GetRefreshToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, entity.ErrRefreshTokenNotFound) {
+			log.Warn("refresh token not found")
+
+			return entity.TokenPair{}, fmt.Errorf("%s: %w", op, entity.ErrRefreshTokenNotFound)
+		}
+
+		log.Error("failed to get refresh token", error_.Err(err))
+
+		return entity.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if current.AppID != app.ID {
+		log.Warn("refresh token issued for another app", slog.Int("token_app_id", current.AppID))
+
+		return entity.TokenPair{}, fmt.Errorf("%s: %w", op, entity.ErrInvalidGrant)
+	}
+
+	pair, err := a.Refresh(ctx, refreshToken)
+	if err != nil {
+		return entity.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return pair, nil
+}
+
+// UserInfo returns the owner of accessToken.
+func (a *AuthUseCase) UserInfo(ctx context.Context, accessToken string) (entity.User, error) {
+	const op = "internal - usecase - Auth.UserInfo"
+
+	user, err := a.tokenUser(ctx, accessToken)
+	if err != nil {
+		a.log.Warn("failed to authenticate user", slog.String("op", op), error_.Err(err))
+
+		return entity.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// Issuer is the iss claim of issued ID tokens.
+func (a *AuthUseCase) Issuer() string {
+	return a.issuer
+}
+
+// NewIDToken signs an OpenID Connect ID token for the login that produced the
+// authorization code. The email claims are only included if the email scope
+// was granted.
+func NewIDToken(user entity.User, app entity.App, signing entity.SigningKey, issuer string, code entity.AuthorizationCode) (string, error) {
+	method, key, err := signingKey(signing)
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.New(method)
+	token.Header["kid"] = signing.KeyID
+
+	now := time.Now()
+	claims := token.Claims.(jwt.MapClaims)
+	claims["iss"] = issuer
+	claims["sub"] = strconv.Itoa(user.ID)
+	claims["aud"] = strconv.Itoa(app.ID)
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(time.Duration(app.TTLHours) * time.Hour).Unix()
+	claims["auth_time"] = code.CreatedAt.Unix()
+	if code.Nonce != "" {
+		claims["nonce"] = code.Nonce
+	}
+	if slices.Contains(strings.Fields(code.Scope), entity.ScopeEmail) {
+		claims["email"] = user.Email
+		claims["email_verified"] = user.EmailVerified
+	}
+
+	return token.SignedString(key)
+}
+
+// issueAuthorizationCode stores a code for the login of user into app on
+// behalf of req. Requested scopes the provider does not know are dropped.
+func (a *AuthUseCase) issueAuthorizationCode(ctx context.Context, user entity.User, app entity.App, req entity.AuthorizationRequest) (string, error) {
+	code, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	var scope []string
+	for _, s := range []string{entity.ScopeOpenID, entity.ScopeEmail, entity.ScopeOfflineAccess} {
+		if slices.Contains(req.Scope, s) {
+			scope = append(scope, s)
+		}
+	}
+
+	now := time.Now()
+	err = a.repo.InsertAuthorizationCode(ctx, entity.AuthorizationCode{
+		CodeHash:      hashToken(code),
+		AppID:         app.ID,
+		UserID:        user.ID,
+		RedirectURI:   req.RedirectURI,
+		CodeChallenge: req.CodeChallenge,
+		Scope:         strings.Join(scope, " "),
+		Nonce:         req.Nonce,
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(a.codeTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+// recordCodeTokens stores the access token and refresh token family issued
+// for grant. The code is kept as long as they live, so revokeCodeTokens can
+// still find them.
+func (a *AuthUseCase) recordCodeTokens(ctx context.Context, grant entity.AuthorizationCode, tokens entity.OIDCTokens, familyID string) error {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokens.AccessToken, claims); err != nil {
+		return err
+	}
+	tokenID, _ := claims["jti"].(string)
+
+	keepUntil := tokens.ExpiresAt
+	if refreshExpiresAt := time.Now().Add(a.refreshTTL); familyID != "" && refreshExpiresAt.After(keepUntil) {
+		keepUntil = refreshExpiresAt
+	}
+
+	return a.repo.SetAuthorizationCodeTokens(ctx, entity.AuthorizationCode{
+		CodeHash:       grant.CodeHash,
+		ExpiresAt:      keepUntil,
+		TokenID:        tokenID,
+		TokenExpiresAt: tokens.ExpiresAt,
+		FamilyID:       familyID,
+	})
+}
+
+// revokeCodeTokens handles an authorization code presented after it was
+// redeemed: the tokens issued for it are revoked, since either the client or
+// an attacker holds an intercepted copy (RFC 6749, section 4.1.2).
+func (a *AuthUseCase) revokeCodeTokens(ctx context.Context, log *slog.Logger, grant entity.AuthorizationCode) error {
+	log.Warn("authorization code reuse detected, revoking issued tokens")
+
+	if grant.TokenID != "" {
+		err := a.repo.InsertRevokedToken(ctx, grant.TokenID, grant.UserID, grant.AppID, grant.TokenExpiresAt)
+		if err != nil {
+			log.Error("failed to revoke token", error_.Err(err))
+
+			return err
+		}
+	}
+
+	if grant.FamilyID != "" {
+		if _, err := a.repo.RevokeRefreshTokenFamily(ctx, grant.FamilyID); err != nil {
+			log.Error("failed to revoke token family", error_.Err(err))
+
+			return err
+		}
+	}
+
+	return entity.ErrAuthorizationCodeNotFound
+}
+
+// authenticateClient checks the password of an app acting as an OAuth
+// client. Unknown apps and wrong passwords fail alike with
+// entity.ErrInvalidClient and count against the peer lockout; a locked peer
+// gets entity.ErrLoginLocked without its secret being checked.
+func (a *AuthUseCase) authenticateClient(ctx context.Context, log *slog.Logger, clientID int, clientSecret string) (entity.App, error) {
+	if err := a.checkPeerAllowed(ctx); err != nil {
+		if errors.Is(err, entity.ErrLoginLocked) {
+			log.Warn("client authentication of locked peer")
+		} else {
+			log.Error("failed to check lockout", error_.Err(err))
+		}
+
+		return entity.App{}, err
+	}
+
+	app, err := a.repo.GetAppForUser(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, entity.ErrAppNotFound) {
+			log.Warn("unknown client")
+			a.registerPeerFailure(ctx, log)
+
+			return entity.App{}, entity.ErrInvalidClient
+		}
+
+		log.Error("failed to get app", error_.Err(err))
+
+		return entity.App{}, err
+	}
+
+	if !a.checkPassword(log, app.PassHash, clientSecret) {
+		log.Warn("invalid client secret")
+		a.registerPeerFailure(ctx, log)
+
+		return entity.App{}, entity.ErrInvalidClient
+	}
+
+	if app.Disabled {
+		log.Warn("disabled client")
+
+		return entity.App{}, entity.ErrAppDisabled
+	}
+
+	return app, nil
+}
+
+// verifyCodeChallenge checks a PKCE verifier against an S256 challenge
+// (RFC 7636).
+func verifyCodeChallenge(challenge string, verifier string) bool {
+	if verifier == "" {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+// validateRedirectURI accepts absolute uris without a fragment. http and https
+// uris need a host; other schemes are left to native apps.
+func validateRedirectURI(uri string) error {
+	u, err := url.Parse(uri)
+	if err != nil || !u.IsAbs() || u.Fragment != "" || strings.Contains(uri, "#") {
+		return entity.ErrInvalidRedirectURI
+	}
+
+	if (u.Scheme == "http" || u.Scheme == "https") && u.Host == "" {
+		return entity.ErrInvalidRedirectURI
+	}
+
+	return nil
+}
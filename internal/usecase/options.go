@@ -0,0 +1,110 @@
+package usecase
+
+import "time"
+
+// Option configures AuthUseCase.
+type Option func(*AuthUseCase)
+
+// RefreshTokenTTL sets the lifetime of issued refresh tokens.
+func RefreshTokenTTL(ttl time.Duration) Option {
+	return func(a *AuthUseCase) {
+		a.refreshTTL = ttl
+	}
+}
+
+// SigningKeyOverlap sets how long a rotated out signing key keeps verifying tokens.
+func SigningKeyOverlap(overlap time.Duration) Option {
+	return func(a *AuthUseCase) {
+		a.keyOverlap = overlap
+	}
+}
+
+// Lockout sets the brute-force protection policy of Login.
+func Lockout(policy LockoutPolicy) Option {
+	return func(a *AuthUseCase) {
+		a.lockout = policy
+	}
+}
+
+// QueryTimeout bounds every repo call by timeout. Calls running out of it fail
+// with entity.ErrQueryTimeout.
+func QueryTimeout(timeout time.Duration) Option {
+	return func(a *AuthUseCase) {
+		a.queryTimeout = timeout
+	}
+}
+
+// SlowQueryThreshold logs repo calls taking at least threshold.
+func SlowQueryThreshold(threshold time.Duration) Option {
+	return func(a *AuthUseCase) {
+		a.slowQuery = threshold
+	}
+}
+
+// WithNotifier sets how notifications like password reset tokens are delivered
+// to users. Without it they are dropped.
+func WithNotifier(n Notifier) Option {
+	return func(a *AuthUseCase) {
+		a.notifier = n
+	}
+}
+
+// PasswordResetTTL sets how long a password reset token stays valid.
+func PasswordResetTTL(ttl time.Duration) Option {
+	return func(a *AuthUseCase) {
+		a.resetTTL = ttl
+	}
+}
+
+// EmailVerificationTTL sets how long an email verification token stays valid.
+func EmailVerificationTTL(ttl time.Duration) Option {
+	return func(a *AuthUseCase) {
+		a.verifyTTL = ttl
+	}
+}
+
+// Passwords sets the policy new passwords have to satisfy.
+func Passwords(policy PasswordPolicy) Option {
+	return func(a *AuthUseCase) {
+		a.passwords = policy
+	}
+}
+
+// Hasher sets how passwords are hashed. Users logging in with a password
+// hashed differently get it rehashed with h. Defaults to argon2id.
+func Hasher(h PasswordHasher) Option {
+	return func(a *AuthUseCase) {
+		a.hasher = h
+	}
+}
+
+// TOTPIssuer sets the issuer authenticator apps show next to the account.
+func TOTPIssuer(issuer string) Option {
+	return func(a *AuthUseCase) {
+		a.totpIssuer = issuer
+	}
+}
+
+// MFAChallengeTTL sets how long the challenge of a login waiting for a second
+// factor stays valid.
+func MFAChallengeTTL(ttl time.Duration) Option {
+	return func(a *AuthUseCase) {
+		a.challengeTTL = ttl
+	}
+}
+
+// Issuer sets the public base URL of the OpenID Connect provider. It is the
+// iss claim of ID tokens and has to match the discovery document.
+func Issuer(issuer string) Option {
+	return func(a *AuthUseCase) {
+		a.issuer = issuer
+	}
+}
+
+// AuthorizationCodeTTL sets how long an OAuth authorization code can be
+// exchanged for tokens.
+func AuthorizationCodeTTL(ttl time.Duration) Option {
+	return func(a *AuthUseCase) {
+		a.codeTTL = ttl
+	}
+}
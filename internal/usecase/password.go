@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+)
+
+// ChangePassword replaces the password of the user with email after checking
+// currentPassword and, for users with TOTP enabled, mfaCode, a current TOTP
+// code or an unused recovery code. Failed checks count towards the login
+// lockout. All refresh tokens of the user are revoked.
+func (a *AuthUseCase) ChangePassword(ctx context.Context, email string, currentPassword string, newPassword string, mfaCode string) error {
+	const op = "internal - usecase - Auth.ChangePassword"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("username", email),
+	)
+
+	log.Info("changing password")
+
+	email, err := normalizeEmail(email)
+	if err != nil {
+		log.Info("invalid email", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.passwords.check(newPassword); err != nil {
+		log.Info("password rejected by policy", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.checkLoginAllowed(ctx, email); err != nil {
+		if errors.Is(err, entity.ErrLoginLocked) || errors.Is(err, entity.ErrLoginThrottled) {
+			log.Warn("password change rejected", error_.Err(err))
+		} else {
+			log.Error("failed to check login failures", error_.Err(err))
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := a.repo.GetUser(ctx, email)
+	if err != nil {
+		if errors.Is(err, entity.ErrUserNotFound) {
+			log.Warn("user not found", error_.Err(err))
+
+			a.registerLoginFailure(ctx, log, email)
+
+			return fmt.Errorf("%s: %w", op, error_.ErrInvalidCredentials)
+		}
+
+		log.Error("failed to get user", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !a.checkPassword(log, user.PassHash, currentPassword) {
+		log.Info("invalid credentials")
+
+		a.registerLoginFailure(ctx, log, email)
+
+		return fmt.Errorf("%s: %w", op, error_.ErrInvalidCredentials)
+	}
+
+	// The password alone would let whoever learned it lock the owner out.
+	if err := a.useMFACode(ctx, log, user, mfaCode); err != nil && !errors.Is(err, entity.ErrTOTPNotFound) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	passHash, err := a.hasher.Hash(newPassword)
+	if err != nil {
+		log.Error("failed to generate password hash", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := a.repo.UpdateUserPassword(ctx, user.ID, passHash); err != nil {
+		log.Error("failed to update password", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.resetLoginFailures(ctx, log, email)
+	a.revokeUserSessions(ctx, log, user.ID)
+	a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventPasswordChanged, UserID: user.ID, Email: user.Email})
+
+	log.Info("password changed")
+
+	return nil
+}
+
+// RequestPasswordReset issues a single-use reset token for the user with email
+// and hands it to the notifier. Unknown emails are not reported, so the call
+// can't be used to probe for registered users, and neither are delivery
+// failures.
+func (a *AuthUseCase) RequestPasswordReset(ctx context.Context, email string) error {
+	const op = "internal - usecase - Auth.RequestPasswordReset"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("username", email),
+	)
+
+	log.Info("requesting password reset")
+
+	email, err := normalizeEmail(email)
+	if err != nil {
+		log.Info("invalid email", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := a.repo.GetUser(ctx, email)
+	if err != nil {
+		if errors.Is(err, entity.ErrUserNotFound) {
+			log.Info("password reset for unknown user")
+
+			a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventPasswordResetRequested, Email: email, Details: "user not found"})
+
+			return nil
+		}
+
+		log.Error("failed to get user", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := newOpaqueToken()
+	if err != nil {
+		log.Error("failed to generate reset token", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	reset := entity.PasswordReset{
+		TokenHash: hashToken(token),
+		UserID:    user.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(a.resetTTL),
+	}
+
+	if err := a.repo.InsertPasswordReset(ctx, reset); err != nil {
+		log.Error("failed to save password reset", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = a.notifier.Notify(ctx, entity.Notification{
+		Type:      entity.NotificationPasswordReset,
+		Email:     user.Email,
+		Token:     token,
+		ExpiresAt: reset.ExpiresAt,
+	})
+	if err != nil {
+		// An error here would tell a registered email from an unknown one.
+		log.Error("failed to deliver password reset", error_.Err(err))
+
+		a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventPasswordResetRequested, UserID: user.ID, Email: user.Email, Details: "delivery failed"})
+
+		return nil
+	}
+
+	a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventPasswordResetRequested, UserID: user.ID, Email: user.Email})
+
+	return nil
+}
+
+// ConfirmPasswordReset sets newPassword for the owner of a reset token issued
+// by RequestPasswordReset. The token and all other outstanding tokens of the
+// user stop working, as do the user's refresh tokens.
+func (a *AuthUseCase) ConfirmPasswordReset(ctx context.Context, token string, newPassword string) error {
+	const op = "internal - usecase - Auth.ConfirmPasswordReset"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("confirming password reset")
+
+	if err := a.passwords.check(newPassword); err != nil {
+		log.Info("password rejected by policy", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	passHash, err := a.hasher.Hash(newPassword)
+	if err != nil {
+		log.Error("failed to generate password hash", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	userID, err := a.repo.ResetPassword(ctx, hashToken(token), time.Now(), passHash)
+	if err != nil {
+		if errors.Is(err, entity.ErrPasswordResetNotFound) {
+			log.Warn("invalid password reset token")
+
+			return fmt.Errorf("%s: %w", op, entity.ErrPasswordResetNotFound)
+		}
+
+		log.Error("failed to reset password", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int("user_id", userID))
+
+	a.revokeUserSessions(ctx, log, userID)
+
+	event := entity.AuthEvent{Type: entity.EventPasswordReset, UserID: userID}
+	if user, err := a.repo.GetUserByID(ctx, userID); err == nil {
+		event.Email = user.Email
+		a.resetLoginFailures(ctx, log, user.Email)
+	}
+	a.recordEvent(ctx, event)
+
+	log.Info("password reset")
+
+	return nil
+}
+
+// revokeUserSessions revokes all refresh tokens of userID after its password
+// changed. Access tokens run out on their own.
+func (a *AuthUseCase) revokeUserSessions(ctx context.Context, log *slog.Logger, userID int) {
+	revoked, err := a.repo.RevokeUserRefreshTokens(ctx, userID)
+	if err != nil {
+		log.Error("failed to revoke refresh tokens", error_.Err(err))
+
+		return
+	}
+
+	if revoked > 0 {
+		log.Info("refresh tokens revoked", slog.Int("count", revoked))
+	}
+}
+
+type nopNotifier struct{}
+
+func (nopNotifier) Notify(context.Context, entity.Notification) error {
+	return nil
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+// _bcryptMaxBytes is the longest password bcrypt hashes, longer ones are
+// rejected by it.
+const _bcryptMaxBytes = 72
+
+// PasswordPolicy is checked for every password a user sets on registration,
+// change or reset.
+type PasswordPolicy struct {
+	// MinLength is counted in characters.
+	MinLength int
+	// MaxLength is counted in bytes and capped at the 72 bytes bcrypt can
+	// hash, zero means the cap.
+	MaxLength     int
+	RequireLower  bool
+	RequireUpper  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// Denylist holds lowercased passwords refused regardless of other rules.
+	Denylist map[string]struct{}
+}
+
+var _defaultPasswordPolicy = PasswordPolicy{
+	MinLength: 8,
+	MaxLength: _bcryptMaxBytes,
+}
+
+// check returns an *entity.PasswordPolicyError listing every rule password
+// fails, or nil.
+func (p PasswordPolicy) check(password string) error {
+	var violations []entity.PasswordViolation
+	fail := func(rule string, format string, args ...any) {
+		violations = append(violations, entity.PasswordViolation{Rule: rule, Description: fmt.Sprintf(format, args...)})
+	}
+
+	if utf8.RuneCountInString(password) < p.MinLength {
+		fail(entity.PasswordRuleMinLength, "must be at least %d characters long", p.MinLength)
+	}
+
+	maxLength := p.MaxLength
+	if maxLength <= 0 || maxLength > _bcryptMaxBytes {
+		maxLength = _bcryptMaxBytes
+	}
+	if len(password) > maxLength {
+		fail(entity.PasswordRuleMaxLength, "must be at most %d bytes long", maxLength)
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsLetter(r) && !unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	if p.RequireLower && !lower {
+		fail(entity.PasswordRuleLower, "must contain a lowercase letter")
+	}
+	if p.RequireUpper && !upper {
+		fail(entity.PasswordRuleUpper, "must contain an uppercase letter")
+	}
+	if p.RequireDigit && !digit {
+		fail(entity.PasswordRuleDigit, "must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		fail(entity.PasswordRuleSymbol, "must contain a symbol")
+	}
+
+	if _, ok := p.Denylist[strings.ToLower(password)]; ok {
+		fail(entity.PasswordRuleDenylist, "must not be a commonly used password")
+	}
+
+	if len(violations) > 0 {
+		return &entity.PasswordPolicyError{Violations: violations}
+	}
+
+	return nil
+}
+
+// LoadPasswordDenylist reads denied passwords from the file at path, one per
+// line. Empty lines and lines starting with # are skipped.
+func LoadPasswordDenylist(path string) (map[string]struct{}, error) {
+	const op = "internal - usecase - LoadPasswordDenylist"
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer f.Close()
+
+	denylist := make(map[string]struct{})
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		denylist[strings.ToLower(line)] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return denylist, nil
+}
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+)
+
+const _opaqueTokenBytes = 32
+
+func (a *AuthUseCase) Refresh(ctx context.Context, refreshToken string) (entity.TokenPair, error) {
+	const op = "internal - usecase - Auth.Refresh"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("attempting to refresh token")
+
+	current, err := a.repo.GetRefreshToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, entity.ErrRefreshTokenNotFound) {
+			log.Warn("refresh token not found", error_.Err(err))
+
+			return entity.TokenPair{}, fmt.Errorf("%s: %w", op, entity.ErrRefreshTokenNotFound)
+		}
+
+		log.Error("failed to get refresh token", error_.Err(err))
+
+		return entity.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(
+		slog.Int("user_id", current.UserID),
+		slog.String("family_id", current.FamilyID),
+	)
+
+	if current.Revoked {
+		log.Warn("refresh token revoked")
+
+		return entity.TokenPair{}, fmt.Errorf("%s: %w", op, entity.ErrRefreshTokenRevoked)
+	}
+
+	if current.Rotated {
+		return entity.TokenPair{}, fmt.Errorf("%s: %w", op, a.revokeFamily(ctx, log, current.FamilyID))
+	}
+
+	if time.Now().After(current.ExpiresAt) {
+		log.Info("refresh token expired")
+
+		return entity.TokenPair{}, fmt.Errorf("%s: %w", op, entity.ErrRefreshTokenExpired)
+	}
+
+	user, err := a.repo.GetUserByID(ctx, current.UserID)
+	if err != nil {
+		log.Error("failed to get user", error_.Err(err))
+
+		return entity.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	app, err := a.repo.GetAppForUser(ctx, current.AppID)
+	if err != nil {
+		log.Error("failed to get app", error_.Err(err))
+
+		return entity.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if app.Disabled {
+		log.Warn("refresh for disabled app", slog.Int("app_id", app.ID))
+
+		return entity.TokenPair{}, fmt.Errorf("%s: %w", op, entity.ErrAppDisabled)
+	}
+
+	pair, err := a.issueTokenPair(ctx, user, app, current.FamilyID, &current)
+	if err != nil {
+		if errors.Is(err, entity.ErrRefreshTokenReused) {
+			return entity.TokenPair{}, fmt.Errorf("%s: %w", op, a.revokeFamily(ctx, log, current.FamilyID))
+		}
+
+		log.Error("failed to issue tokens", error_.Err(err))
+
+		return entity.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("token successfully refreshed")
+
+	return pair, nil
+}
+
+// revokeFamily handles a presented refresh token that was already rotated:
+// the whole family is revoked, since either the legitimate client or an
+// attacker is holding a stolen copy.
+func (a *AuthUseCase) revokeFamily(ctx context.Context, log *slog.Logger, familyID string) error {
+	log.Warn("refresh token reuse detected, revoking token family")
+
+	if _, err := a.repo.RevokeRefreshTokenFamily(ctx, familyID); err != nil {
+		log.Error("failed to revoke token family", error_.Err(err))
+
+		return err
+	}
+
+	return entity.ErrRefreshTokenReused
+}
+
+// issueTokenPair mints an access token and a refresh token in familyID.
+// If previous is set, it is rotated out in favour of the new refresh token.
+func (a *AuthUseCase) issueTokenPair(ctx context.Context, user entity.User, app entity.App, familyID string, previous *entity.RefreshToken) (entity.TokenPair, error) {
+	expiresAt := time.Now().Add(time.Duration(app.TTLHours) * time.Hour)
+
+	accessToken, err := a.newToken(ctx, user, app)
+	if err != nil {
+		return entity.TokenPair{}, err
+	}
+
+	refreshToken, err := newOpaqueToken()
+	if err != nil {
+		return entity.TokenPair{}, err
+	}
+
+	next := entity.RefreshToken{
+		TokenHash: hashToken(refreshToken),
+		FamilyID:  familyID,
+		UserID:    user.ID,
+		AppID:     app.ID,
+		ExpiresAt: time.Now().Add(a.refreshTTL),
+	}
+
+	if previous != nil {
+		_, err = a.repo.RotateRefreshToken(ctx, previous.ID, next)
+	} else {
+		_, err = a.repo.InsertRefreshToken(ctx, next)
+	}
+	if err != nil {
+		return entity.TokenPair{}, err
+	}
+
+	return entity.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+func newOpaqueToken() (string, error) {
+	b := make([]byte, _opaqueTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+
+	return sum[:]
+}
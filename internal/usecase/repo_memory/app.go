@@ -0,0 +1,228 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+func (r *AuthRepo) GetAppForUser(_ context.Context, id int) (entity.App, error) {
+	const op = "internal - usecase - repo_memory - AuthRepo.GetAppForUser"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.apps[id]; !ok {
+		return entity.App{}, fmt.Errorf("%s: %w", op, entity.ErrAppNotFound)
+	}
+
+	return r.app(id), nil
+}
+
+func (r *AuthRepo) GetAppByName(_ context.Context, name string) (entity.App, error) {
+	const op = "internal - usecase - repo_memory - AuthRepo.GetAppByName"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.appsByName[name]
+	if !ok {
+		return entity.App{}, fmt.Errorf("%s: %w", op, entity.ErrAppNotFound)
+	}
+
+	return r.app(id), nil
+}
+
+func (r *AuthRepo) InsertApp(_ context.Context, name string, passHash []byte, secret string, ttlHour int) (int, error) {
+	const op = "internal - usecase - repo_memory - AuthRepo.InsertApp"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.appsByName[name]; ok {
+		return 0, fmt.Errorf("%s: %w", op, entity.ErrAppExists)
+	}
+
+	id := r.nextID()
+	r.apps[id] = entity.App{
+		ID:         id,
+		Name:       name,
+		PassHash:   cloneBytes(passHash),
+		Secret:     secret,
+		TTLHours:   ttlHour,
+		SigningAlg: entity.SigningAlgHS256,
+	}
+	r.appsByName[name] = id
+
+	return id, nil
+}
+
+func (r *AuthRepo) UpdateApp(_ context.Context, id_ int, secret string, ttlHour int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	app, ok := r.apps[id_]
+	if !ok {
+		return 0, nil
+	}
+
+	app.Secret = secret
+	app.TTLHours = ttlHour
+	r.apps[id_] = app
+
+	return 1, nil
+}
+
+func (r *AuthRepo) UpdateAppSigningAlg(_ context.Context, id_ int, alg string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	app, ok := r.apps[id_]
+	if !ok {
+		return 0, nil
+	}
+
+	app.SigningAlg = alg
+	r.apps[id_] = app
+
+	return 1, nil
+}
+
+func (r *AuthRepo) UpdateAppPassword(_ context.Context, id_ int, passHash []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	app, ok := r.apps[id_]
+	if !ok {
+		return 0, nil
+	}
+
+	app.PassHash = cloneBytes(passHash)
+	r.apps[id_] = app
+
+	return 1, nil
+}
+
+// DeleteApp removes the app with its signing keys, refresh tokens, roles and
+// revoked tokens, like repo_sqlite does. Auth events are kept.
+func (r *AuthRepo) DeleteApp(_ context.Context, id_ int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	app, ok := r.apps[id_]
+	if !ok {
+		return 0, nil
+	}
+
+	delete(r.apps, id_)
+	delete(r.appsByName, app.Name)
+
+	for id, key := range r.signingKeys {
+		if key.AppID == id_ {
+			delete(r.signingKeys, id)
+			delete(r.signingKeysByKid, key.KeyID)
+		}
+	}
+
+	for id, token := range r.refreshTokens {
+		if token.AppID == id_ {
+			delete(r.refreshTokens, id)
+			delete(r.refreshTokensByHash, string(token.TokenHash))
+		}
+	}
+
+	for jti, token := range r.revokedTokens {
+		if token.appID == id_ {
+			delete(r.revokedTokens, jti)
+		}
+	}
+
+	for hash, challenge := range r.mfaChallenges {
+		if challenge.AppID == id_ {
+			delete(r.mfaChallenges, hash)
+		}
+	}
+
+	delete(r.redirectURIs, id_)
+	for hash, code := range r.authorizationCodes {
+		if code.AppID == id_ {
+			delete(r.authorizationCodes, hash)
+		}
+	}
+	delete(r.appScopes, id_)
+	delete(r.appClaims, id_)
+
+	for id, role := range r.roles {
+		if role.AppID != id_ {
+			continue
+		}
+
+		delete(r.roles, id)
+		delete(r.rolesByName, roleName{appID: role.AppID, name: role.Name})
+		delete(r.rolePermissions, id)
+		for _, roles := range r.userRoles {
+			delete(roles, id)
+		}
+	}
+
+	return 1, nil
+}
+
+func (r *AuthRepo) SetAppDisabled(_ context.Context, id_ int, disabled bool) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	app, ok := r.apps[id_]
+	if !ok {
+		return 0, nil
+	}
+
+	app.Disabled = disabled
+	r.apps[id_] = app
+
+	return 1, nil
+}
+
+func (r *AuthRepo) SetAppEmailPolicy(_ context.Context, id_ int, requireVerified bool) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	app, ok := r.apps[id_]
+	if !ok {
+		return 0, nil
+	}
+
+	app.RequireVerifiedEmail = requireVerified
+	r.apps[id_] = app
+
+	return 1, nil
+}
+
+// ListApps returns up to limit apps with ids above afterID in id order.
+func (r *AuthRepo) ListApps(_ context.Context, afterID int, limit int) ([]entity.App, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	apps := make([]entity.App, 0, min(limit, len(r.apps)))
+	for id := range r.apps {
+		if id > afterID {
+			apps = append(apps, r.app(id))
+		}
+	}
+	slices.SortFunc(apps, func(a, b entity.App) int { return a.ID - b.ID })
+
+	if len(apps) > limit {
+		apps = apps[:limit]
+	}
+
+	return apps, nil
+}
+
+func (r *AuthRepo) app(id int) entity.App {
+	app := r.apps[id]
+	app.PassHash = cloneBytes(app.PassHash)
+
+	return app
+}
@@ -0,0 +1,42 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+// SetAppScopes replaces the scopes of the app with scopes.
+func (r *AuthRepo) SetAppScopes(_ context.Context, appID int, scopes []string) error {
+	const op = "internal - usecase - repo_memory - AuthRepo.SetAppScopes"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.apps[appID]; !ok {
+		return fmt.Errorf("%s: %w", op, entity.ErrAppNotFound)
+	}
+
+	set := make(map[string]struct{}, len(scopes))
+	for _, scope := range scopes {
+		set[scope] = struct{}{}
+	}
+	r.appScopes[appID] = set
+
+	return nil
+}
+
+func (r *AuthRepo) GetAppScopes(_ context.Context, appID int) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var scopes []string
+	for scope := range r.appScopes[appID] {
+		scopes = append(scopes, scope)
+	}
+	slices.Sort(scopes)
+
+	return scopes, nil
+}
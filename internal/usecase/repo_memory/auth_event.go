@@ -0,0 +1,44 @@
+package repo
+
+import (
+	"context"
+	"slices"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+func (r *AuthRepo) InsertAuthEvent(_ context.Context, event entity.AuthEvent) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event.ID = r.nextID()
+	r.authEvents = append(r.authEvents, event)
+
+	return event.ID, nil
+}
+
+// ListAuthEvents returns events matching filter, newest first.
+func (r *AuthRepo) ListAuthEvents(_ context.Context, filter entity.AuthEventFilter) ([]entity.AuthEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var events []entity.AuthEvent
+	for i := len(r.authEvents) - 1; i >= 0 && len(events) < filter.Limit; i-- {
+		event := r.authEvents[i]
+
+		switch {
+		case filter.UserID != 0 && event.UserID != filter.UserID,
+			filter.AppID != 0 && event.AppID != filter.AppID,
+			len(filter.Types) > 0 && !slices.Contains(filter.Types, event.Type),
+			filter.Email != "" && event.Email != filter.Email,
+			!filter.Since.IsZero() && event.CreatedAt.Before(filter.Since),
+			!filter.Until.IsZero() && !event.CreatedAt.Before(filter.Until),
+			filter.BeforeID != 0 && event.ID >= filter.BeforeID:
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
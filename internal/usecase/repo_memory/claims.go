@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+// SetAppClaims replaces the claim templates of the app with claims.
+func (r *AuthRepo) SetAppClaims(_ context.Context, appID int, claims map[string]string) error {
+	const op = "internal - usecase - repo_memory - AuthRepo.SetAppClaims"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.apps[appID]; !ok {
+		return fmt.Errorf("%s: %w", op, entity.ErrAppNotFound)
+	}
+
+	r.appClaims[appID] = maps.Clone(claims)
+
+	return nil
+}
+
+func (r *AuthRepo) GetAppClaims(_ context.Context, appID int) (map[string]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	claims := make(map[string]string, len(r.appClaims[appID]))
+	maps.Copy(claims, r.appClaims[appID])
+
+	return claims, nil
+}
+
+func (r *AuthRepo) SetUserAttribute(_ context.Context, userID int, key string, value string) error {
+	const op = "internal - usecase - repo_memory - AuthRepo.SetUserAttribute"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[userID]; !ok {
+		return fmt.Errorf("%s: %w", op, entity.ErrUserNotFound)
+	}
+
+	if r.userAttributes[userID] == nil {
+		r.userAttributes[userID] = make(map[string]string)
+	}
+	r.userAttributes[userID][key] = value
+
+	return nil
+}
+
+func (r *AuthRepo) DeleteUserAttribute(_ context.Context, userID int, key string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.userAttributes[userID][key]; !ok {
+		return 0, nil
+	}
+
+	delete(r.userAttributes[userID], key)
+
+	return 1, nil
+}
+
+func (r *AuthRepo) GetUserAttributes(_ context.Context, userID int) (map[string]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	attributes := make(map[string]string, len(r.userAttributes[userID]))
+	maps.Copy(attributes, r.userAttributes[userID])
+
+	return attributes, nil
+}
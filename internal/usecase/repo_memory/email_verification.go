@@ -0,0 +1,72 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+var errDuplicateVerificationHash = errors.New("duplicate email verification token hash")
+
+func (r *AuthRepo) InsertEmailVerification(_ context.Context, verification entity.EmailVerification) error {
+	const op = "internal - usecase - repo_memory - AuthRepo.InsertEmailVerification"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.emailVerifications[string(verification.TokenHash)]; ok {
+		return fmt.Errorf("%s: %w", op, errDuplicateVerificationHash)
+	}
+
+	verification.TokenHash = cloneBytes(verification.TokenHash)
+	r.emailVerifications[string(verification.TokenHash)] = verification
+
+	return nil
+}
+
+// VerifyEmail consumes the verification token with tokenHash and marks the
+// email of its user as verified atomically. Other outstanding tokens of the
+// user are dropped. It fails with entity.ErrEmailVerificationNotFound if the
+// token is unknown, already used or expired at now.
+func (r *AuthRepo) VerifyEmail(_ context.Context, tokenHash []byte, now time.Time) (int, error) {
+	const op = "internal - usecase - repo_memory - AuthRepo.VerifyEmail"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	verification, ok := r.emailVerifications[string(tokenHash)]
+	if !ok || !verification.ExpiresAt.After(now) {
+		return 0, fmt.Errorf("%s: %w", op, entity.ErrEmailVerificationNotFound)
+	}
+
+	for hash, other := range r.emailVerifications {
+		if other.UserID == verification.UserID {
+			delete(r.emailVerifications, hash)
+		}
+	}
+
+	if user, ok := r.users[verification.UserID]; ok {
+		user.EmailVerified = true
+		r.users[verification.UserID] = user
+	}
+
+	return verification.UserID, nil
+}
+
+func (r *AuthRepo) DeleteExpiredEmailVerifications(_ context.Context, now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for hash, verification := range r.emailVerifications {
+		if !verification.ExpiresAt.After(now) {
+			delete(r.emailVerifications, hash)
+			count++
+		}
+	}
+
+	return count, nil
+}
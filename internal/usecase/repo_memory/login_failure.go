@@ -0,0 +1,86 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+// GetLoginFailure returns the failures recorded for key. A key without
+// failures yields a zero LoginFailure.
+func (r *AuthRepo) GetLoginFailure(_ context.Context, key string) (entity.LoginFailure, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	failure, ok := r.loginFailures[key]
+	if !ok {
+		return entity.LoginFailure{Key: key}, nil
+	}
+
+	return failure, nil
+}
+
+// RecordLoginFailure increments the failures of key, starting over when the
+// previous failure happened before windowStart, and returns the new count.
+func (r *AuthRepo) RecordLoginFailure(_ context.Context, key string, now time.Time, windowStart time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	failure, ok := r.loginFailures[key]
+	if !ok {
+		failure = entity.LoginFailure{Key: key}
+	}
+
+	if failure.LastFailedAt.Before(windowStart) {
+		failure.Failures = 0
+	}
+	failure.Failures++
+	failure.LastFailedAt = now
+	r.loginFailures[key] = failure
+
+	return failure.Failures, nil
+}
+
+func (r *AuthRepo) LockLogin(_ context.Context, key string, until time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	failure, ok := r.loginFailures[key]
+	if !ok {
+		return 0, nil
+	}
+
+	failure.LockedUntil = until
+	r.loginFailures[key] = failure
+
+	return 1, nil
+}
+
+func (r *AuthRepo) DeleteLoginFailure(_ context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.loginFailures, key)
+
+	return nil
+}
+
+// DeleteStaleLoginFailures removes unlocked records whose last failure happened
+// before windowStart.
+func (r *AuthRepo) DeleteStaleLoginFailures(_ context.Context, now time.Time, windowStart time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for key, failure := range r.loginFailures {
+		if !failure.LastFailedAt.Before(windowStart) || failure.LockedUntil.After(now) {
+			continue
+		}
+
+		delete(r.loginFailures, key)
+		count++
+	}
+
+	return count, nil
+}
@@ -0,0 +1,191 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+var errDuplicateChallengeHash = errors.New("duplicate mfa challenge token hash")
+
+// SaveTOTP stores an unconfirmed enrollment of the user, replacing a previous
+// unconfirmed one. It returns 0 and stores nothing if the user already has
+// TOTP enabled.
+func (r *AuthRepo) SaveTOTP(_ context.Context, totp entity.TOTP) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if current, ok := r.totps[totp.UserID]; ok && current.Confirmed {
+		return 0, nil
+	}
+
+	r.totps[totp.UserID] = entity.TOTP{
+		UserID:    totp.UserID,
+		Secret:    cloneBytes(totp.Secret),
+		CreatedAt: totp.CreatedAt,
+	}
+
+	return 1, nil
+}
+
+func (r *AuthRepo) GetTOTP(_ context.Context, userID int) (entity.TOTP, error) {
+	const op = "internal - usecase - repo_memory - AuthRepo.GetTOTP"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	totp, ok := r.totps[userID]
+	if !ok {
+		return entity.TOTP{}, fmt.Errorf("%s: %w", op, entity.ErrTOTPNotFound)
+	}
+
+	totp.Secret = cloneBytes(totp.Secret)
+
+	return totp, nil
+}
+
+// ConfirmTOTP enables the pending enrollment of the user, accepting step as
+// its first code, and replaces the user's recovery codes with codeHashes. It
+// returns 0 and changes nothing if there is no pending enrollment.
+func (r *AuthRepo) ConfirmTOTP(_ context.Context, userID int, step int64, codeHashes [][]byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	totp, ok := r.totps[userID]
+	if !ok || totp.Confirmed {
+		return 0, nil
+	}
+
+	totp.Confirmed = true
+	totp.LastStep = step
+	r.totps[userID] = totp
+
+	codes := make(map[string]struct{}, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes[string(hash)] = struct{}{}
+	}
+	r.recoveryCodes[userID] = codes
+
+	return 1, nil
+}
+
+// UseTOTPStep records step as the last accepted code of the user. It returns
+// 0 if TOTP is not enabled or a code of step or a later one was already
+// accepted.
+func (r *AuthRepo) UseTOTPStep(_ context.Context, userID int, step int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	totp, ok := r.totps[userID]
+	if !ok || !totp.Confirmed || totp.LastStep >= step {
+		return 0, nil
+	}
+
+	totp.LastStep = step
+	r.totps[userID] = totp
+
+	return 1, nil
+}
+
+// UseRecoveryCode consumes the recovery code of the user with codeHash. It
+// returns 0 if there is no such code.
+func (r *AuthRepo) UseRecoveryCode(_ context.Context, userID int, codeHash []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	codes := r.recoveryCodes[userID]
+	if _, ok := codes[string(codeHash)]; !ok {
+		return 0, nil
+	}
+
+	delete(codes, string(codeHash))
+
+	return 1, nil
+}
+
+// DeleteTOTP removes the enrollment of the user together with its recovery
+// codes and outstanding challenges.
+func (r *AuthRepo) DeleteTOTP(_ context.Context, userID int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.totps[userID]; !ok {
+		return 0, nil
+	}
+
+	delete(r.totps, userID)
+	delete(r.recoveryCodes, userID)
+
+	for hash, challenge := range r.mfaChallenges {
+		if challenge.UserID == userID {
+			delete(r.mfaChallenges, hash)
+		}
+	}
+
+	return 1, nil
+}
+
+func (r *AuthRepo) InsertMFAChallenge(_ context.Context, challenge entity.MFAChallenge) error {
+	const op = "internal - usecase - repo_memory - AuthRepo.InsertMFAChallenge"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.mfaChallenges[string(challenge.TokenHash)]; ok {
+		return fmt.Errorf("%s: %w", op, errDuplicateChallengeHash)
+	}
+
+	challenge.TokenHash = cloneBytes(challenge.TokenHash)
+	r.mfaChallenges[string(challenge.TokenHash)] = challenge
+
+	return nil
+}
+
+// GetMFAChallenge fails with entity.ErrMFAChallengeNotFound if the challenge
+// with tokenHash is unknown, already used or expired at now.
+func (r *AuthRepo) GetMFAChallenge(_ context.Context, tokenHash []byte, now time.Time) (entity.MFAChallenge, error) {
+	const op = "internal - usecase - repo_memory - AuthRepo.GetMFAChallenge"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	challenge, ok := r.mfaChallenges[string(tokenHash)]
+	if !ok || !challenge.ExpiresAt.After(now) {
+		return entity.MFAChallenge{}, fmt.Errorf("%s: %w", op, entity.ErrMFAChallengeNotFound)
+	}
+
+	challenge.TokenHash = cloneBytes(challenge.TokenHash)
+
+	return challenge, nil
+}
+
+func (r *AuthRepo) DeleteMFAChallenge(_ context.Context, tokenHash []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.mfaChallenges[string(tokenHash)]; !ok {
+		return 0, nil
+	}
+
+	delete(r.mfaChallenges, string(tokenHash))
+
+	return 1, nil
+}
+
+func (r *AuthRepo) DeleteExpiredMFAChallenges(_ context.Context, now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for hash, challenge := range r.mfaChallenges {
+		if !challenge.ExpiresAt.After(now) {
+			delete(r.mfaChallenges, hash)
+			count++
+		}
+	}
+
+	return count, nil
+}
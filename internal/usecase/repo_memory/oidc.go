@@ -0,0 +1,123 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+var errDuplicateCodeHash = errors.New("duplicate authorization code hash")
+
+// SetAppRedirectURIs replaces the redirect uris of the app with uris.
+func (r *AuthRepo) SetAppRedirectURIs(_ context.Context, appID int, uris []string) error {
+	const op = "internal - usecase - repo_memory - AuthRepo.SetAppRedirectURIs"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.apps[appID]; !ok {
+		return fmt.Errorf("%s: %w", op, entity.ErrAppNotFound)
+	}
+
+	set := make(map[string]struct{}, len(uris))
+	for _, uri := range uris {
+		set[uri] = struct{}{}
+	}
+	r.redirectURIs[appID] = set
+
+	return nil
+}
+
+func (r *AuthRepo) GetAppRedirectURIs(_ context.Context, appID int) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var uris []string
+	for uri := range r.redirectURIs[appID] {
+		uris = append(uris, uri)
+	}
+	slices.Sort(uris)
+
+	return uris, nil
+}
+
+func (r *AuthRepo) InsertAuthorizationCode(_ context.Context, code entity.AuthorizationCode) error {
+	const op = "internal - usecase - repo_memory - AuthRepo.InsertAuthorizationCode"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.authorizationCodes[string(code.CodeHash)]; ok {
+		return fmt.Errorf("%s: %w", op, errDuplicateCodeHash)
+	}
+
+	code.CodeHash = cloneBytes(code.CodeHash)
+	r.authorizationCodes[string(code.CodeHash)] = code
+
+	return nil
+}
+
+// ConsumeAuthorizationCode marks the code with codeHash used and returns it.
+// A code that was used before is returned unchanged with Used set. It fails
+// with entity.ErrAuthorizationCodeNotFound if the code is unknown or expired
+// at now.
+func (r *AuthRepo) ConsumeAuthorizationCode(_ context.Context, codeHash []byte, now time.Time) (entity.AuthorizationCode, error) {
+	const op = "internal - usecase - repo_memory - AuthRepo.ConsumeAuthorizationCode"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	code, ok := r.authorizationCodes[string(codeHash)]
+	if !ok || !code.ExpiresAt.After(now) {
+		return entity.AuthorizationCode{}, fmt.Errorf("%s: %w", op, entity.ErrAuthorizationCodeNotFound)
+	}
+
+	if code.Used {
+		return code, nil
+	}
+
+	used := code
+	used.Used = true
+	r.authorizationCodes[string(codeHash)] = used
+
+	return code, nil
+}
+
+// SetAuthorizationCodeTokens records the tokens issued for the used code
+// code.CodeHash and keeps the code until code.ExpiresAt.
+func (r *AuthRepo) SetAuthorizationCodeTokens(_ context.Context, code entity.AuthorizationCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.authorizationCodes[string(code.CodeHash)]
+	if !ok {
+		return nil
+	}
+
+	stored.TokenID = code.TokenID
+	stored.TokenExpiresAt = code.TokenExpiresAt
+	stored.FamilyID = code.FamilyID
+	stored.ExpiresAt = code.ExpiresAt
+	r.authorizationCodes[string(code.CodeHash)] = stored
+
+	return nil
+}
+
+func (r *AuthRepo) DeleteExpiredAuthorizationCodes(_ context.Context, now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for hash, code := range r.authorizationCodes {
+		if !code.ExpiresAt.After(now) {
+			delete(r.authorizationCodes, hash)
+			count++
+		}
+	}
+
+	return count, nil
+}
@@ -0,0 +1,72 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+var errDuplicateResetHash = errors.New("duplicate password reset token hash")
+
+func (r *AuthRepo) InsertPasswordReset(_ context.Context, reset entity.PasswordReset) error {
+	const op = "internal - usecase - repo_memory - AuthRepo.InsertPasswordReset"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.passwordResets[string(reset.TokenHash)]; ok {
+		return fmt.Errorf("%s: %w", op, errDuplicateResetHash)
+	}
+
+	reset.TokenHash = cloneBytes(reset.TokenHash)
+	r.passwordResets[string(reset.TokenHash)] = reset
+
+	return nil
+}
+
+// ResetPassword consumes the reset token with tokenHash and stores passHash for
+// its user atomically. All other outstanding resets of the user are dropped as
+// well. It fails with entity.ErrPasswordResetNotFound if the token is unknown,
+// already used or expired at now.
+func (r *AuthRepo) ResetPassword(_ context.Context, tokenHash []byte, now time.Time, passHash []byte) (int, error) {
+	const op = "internal - usecase - repo_memory - AuthRepo.ResetPassword"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reset, ok := r.passwordResets[string(tokenHash)]
+	if !ok || !reset.ExpiresAt.After(now) {
+		return 0, fmt.Errorf("%s: %w", op, entity.ErrPasswordResetNotFound)
+	}
+
+	for hash, other := range r.passwordResets {
+		if other.UserID == reset.UserID {
+			delete(r.passwordResets, hash)
+		}
+	}
+
+	if user, ok := r.users[reset.UserID]; ok {
+		user.PassHash = cloneBytes(passHash)
+		r.users[reset.UserID] = user
+	}
+
+	return reset.UserID, nil
+}
+
+func (r *AuthRepo) DeleteExpiredPasswordResets(_ context.Context, now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for hash, reset := range r.passwordResets {
+		if !reset.ExpiresAt.After(now) {
+			delete(r.passwordResets, hash)
+			count++
+		}
+	}
+
+	return count, nil
+}
@@ -0,0 +1,122 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+var errDuplicateTokenHash = errors.New("duplicate refresh token hash")
+
+func (r *AuthRepo) InsertRefreshToken(_ context.Context, token entity.RefreshToken) (int, error) {
+	const op = "internal - usecase - repo_memory - AuthRepo.InsertRefreshToken"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id, err := r.insertRefreshToken(token)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *AuthRepo) GetRefreshToken(_ context.Context, tokenHash []byte) (entity.RefreshToken, error) {
+	const op = "internal - usecase - repo_memory - AuthRepo.GetRefreshToken"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.refreshTokensByHash[string(tokenHash)]
+	if !ok {
+		return entity.RefreshToken{}, fmt.Errorf("%s: %w", op, entity.ErrRefreshTokenNotFound)
+	}
+
+	token := r.refreshTokens[id]
+	token.TokenHash = cloneBytes(token.TokenHash)
+
+	return token, nil
+}
+
+// RotateRefreshToken marks the token with id_ as used and stores its successor
+// atomically. It fails with entity.ErrRefreshTokenReused if the token has
+// already been rotated or revoked.
+func (r *AuthRepo) RotateRefreshToken(_ context.Context, id_ int, next entity.RefreshToken) (int, error) {
+	const op = "internal - usecase - repo_memory - AuthRepo.RotateRefreshToken"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.refreshTokens[id_]
+	if !ok || token.Rotated || token.Revoked {
+		return 0, fmt.Errorf("%s: %w", op, entity.ErrRefreshTokenReused)
+	}
+
+	if _, ok := r.refreshTokensByHash[string(next.TokenHash)]; ok {
+		return 0, fmt.Errorf("%s: %w", op, errDuplicateTokenHash)
+	}
+
+	token.Rotated = true
+	r.refreshTokens[id_] = token
+
+	id, err := r.insertRefreshToken(next)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *AuthRepo) RevokeRefreshTokenFamily(_ context.Context, familyID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for id, token := range r.refreshTokens {
+		if token.FamilyID != familyID || token.Revoked {
+			continue
+		}
+
+		token.Revoked = true
+		r.refreshTokens[id] = token
+		count++
+	}
+
+	return count, nil
+}
+
+func (r *AuthRepo) RevokeUserRefreshTokens(_ context.Context, userID int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for id, token := range r.refreshTokens {
+		if token.UserID != userID || token.Revoked {
+			continue
+		}
+
+		token.Revoked = true
+		r.refreshTokens[id] = token
+		count++
+	}
+
+	return count, nil
+}
+
+func (r *AuthRepo) insertRefreshToken(token entity.RefreshToken) (int, error) {
+	if _, ok := r.refreshTokensByHash[string(token.TokenHash)]; ok {
+		return 0, errDuplicateTokenHash
+	}
+
+	token.ID = r.nextID()
+	token.TokenHash = cloneBytes(token.TokenHash)
+	token.Rotated = false
+	token.Revoked = false
+	r.refreshTokens[token.ID] = token
+	r.refreshTokensByHash[string(token.TokenHash)] = token.ID
+
+	return token.ID, nil
+}
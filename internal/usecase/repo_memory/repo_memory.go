@@ -0,0 +1,111 @@
+// Package repo implements usecase.AuthRepo in memory. It keeps the error
+// semantics of repo_sqlite and is meant for tests and ephemeral dev runs.
+package repo
+
+import (
+	"sync"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+type revokedToken struct {
+	appID     int
+	expiresAt time.Time
+}
+
+type AuthRepo struct {
+	mu sync.RWMutex
+
+	lastID int
+
+	users           map[int]entity.User
+	usersByEmail    map[string]int
+	emailCollisions map[string]map[int]struct{}
+
+	apps       map[int]entity.App
+	appsByName map[string]int
+
+	refreshTokens       map[int]entity.RefreshToken
+	refreshTokensByHash map[string]int
+
+	signingKeys      map[int]entity.SigningKey
+	signingKeysByKid map[string]int
+
+	revokedTokens map[string]revokedToken
+
+	roles           map[int]entity.Role
+	rolesByName     map[roleName]int
+	rolePermissions map[int]map[string]struct{}
+	userRoles       map[int]map[int]struct{}
+
+	authEvents []entity.AuthEvent
+
+	loginFailures map[string]entity.LoginFailure
+
+	passwordResets     map[string]entity.PasswordReset
+	emailVerifications map[string]entity.EmailVerification
+
+	totps         map[int]entity.TOTP
+	recoveryCodes map[int]map[string]struct{}
+	mfaChallenges map[string]entity.MFAChallenge
+
+	redirectURIs       map[int]map[string]struct{}
+	authorizationCodes map[string]entity.AuthorizationCode
+
+	appScopes map[int]map[string]struct{}
+
+	appClaims      map[int]map[string]string
+	userAttributes map[int]map[string]string
+}
+
+type roleName struct {
+	appID int
+	name  string
+}
+
+func New() *AuthRepo {
+	return &AuthRepo{
+		users:               make(map[int]entity.User),
+		usersByEmail:        make(map[string]int),
+		emailCollisions:     make(map[string]map[int]struct{}),
+		apps:                make(map[int]entity.App),
+		appsByName:          make(map[string]int),
+		refreshTokens:       make(map[int]entity.RefreshToken),
+		refreshTokensByHash: make(map[string]int),
+		signingKeys:         make(map[int]entity.SigningKey),
+		signingKeysByKid:    make(map[string]int),
+		revokedTokens:       make(map[string]revokedToken),
+		roles:               make(map[int]entity.Role),
+		rolesByName:         make(map[roleName]int),
+		rolePermissions:     make(map[int]map[string]struct{}),
+		userRoles:           make(map[int]map[int]struct{}),
+		loginFailures:       make(map[string]entity.LoginFailure),
+		passwordResets:      make(map[string]entity.PasswordReset),
+		emailVerifications:  make(map[string]entity.EmailVerification),
+		totps:               make(map[int]entity.TOTP),
+		recoveryCodes:       make(map[int]map[string]struct{}),
+		mfaChallenges:       make(map[string]entity.MFAChallenge),
+		redirectURIs:        make(map[int]map[string]struct{}),
+		authorizationCodes:  make(map[string]entity.AuthorizationCode),
+		appScopes:           make(map[int]map[string]struct{}),
+		appClaims:           make(map[int]map[string]string),
+		userAttributes:      make(map[int]map[string]string),
+	}
+}
+
+// nextID returns a new row id. Ids are unique across all tables, which is
+// enough for callers that only rely on them being unique per table.
+func (r *AuthRepo) nextID() int {
+	r.lastID++
+
+	return r.lastID
+}
+
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+
+	return append([]byte(nil), b...)
+}
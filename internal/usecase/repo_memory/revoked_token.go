@@ -0,0 +1,43 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+func (r *AuthRepo) InsertRevokedToken(_ context.Context, jti string, _ int, appID int, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.revokedTokens[jti]; !ok {
+		r.revokedTokens[jti] = revokedToken{appID: appID, expiresAt: expiresAt}
+	}
+
+	return nil
+}
+
+func (r *AuthRepo) IsTokenRevoked(_ context.Context, jti string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.revokedTokens[jti]
+
+	return ok, nil
+}
+
+func (r *AuthRepo) DeleteExpiredRevokedTokens(_ context.Context, now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for jti, token := range r.revokedTokens {
+		if token.expiresAt.After(now) {
+			continue
+		}
+
+		delete(r.revokedTokens, jti)
+		count++
+	}
+
+	return count, nil
+}
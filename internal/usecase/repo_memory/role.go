@@ -0,0 +1,103 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+// UpsertRole creates the role within the app or replaces the permissions of
+// an existing one.
+func (r *AuthRepo) UpsertRole(_ context.Context, appID int, name string, permissions []string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := roleName{appID: appID, name: name}
+	id, ok := r.rolesByName[key]
+	if !ok {
+		id = r.nextID()
+		r.roles[id] = entity.Role{ID: id, AppID: appID, Name: name}
+		r.rolesByName[key] = id
+	}
+
+	set := make(map[string]struct{}, len(permissions))
+	for _, permission := range permissions {
+		set[permission] = struct{}{}
+	}
+	r.rolePermissions[id] = set
+
+	return id, nil
+}
+
+func (r *AuthRepo) GetRole(_ context.Context, appID int, name string) (entity.Role, error) {
+	const op = "internal - usecase - repo_memory - AuthRepo.GetRole"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.rolesByName[roleName{appID: appID, name: name}]
+	if !ok {
+		return entity.Role{}, fmt.Errorf("%s: %w", op, entity.ErrRoleNotFound)
+	}
+
+	return r.roles[id], nil
+}
+
+func (r *AuthRepo) AssignRole(_ context.Context, userID int, roleID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	roles, ok := r.userRoles[userID]
+	if !ok {
+		roles = make(map[int]struct{})
+		r.userRoles[userID] = roles
+	}
+	roles[roleID] = struct{}{}
+
+	return nil
+}
+
+func (r *AuthRepo) UnassignRole(_ context.Context, userID int, roleID int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.userRoles[userID][roleID]; !ok {
+		return 0, nil
+	}
+	delete(r.userRoles[userID], roleID)
+
+	return 1, nil
+}
+
+// GetUserAccess returns the roles of the user within the app and the union of
+// their permissions, both sorted.
+func (r *AuthRepo) GetUserAccess(_ context.Context, userID int, appID int) (entity.Access, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var access entity.Access
+	permissions := make(map[string]struct{})
+
+	for roleID := range r.userRoles[userID] {
+		role, ok := r.roles[roleID]
+		if !ok || role.AppID != appID {
+			continue
+		}
+
+		access.Roles = append(access.Roles, role.Name)
+		for permission := range r.rolePermissions[roleID] {
+			permissions[permission] = struct{}{}
+		}
+	}
+
+	for permission := range permissions {
+		access.Permissions = append(access.Permissions, permission)
+	}
+
+	sort.Strings(access.Roles)
+	sort.Strings(access.Permissions)
+
+	return access, nil
+}
@@ -0,0 +1,115 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+var errDuplicateKeyID = errors.New("duplicate signing key id")
+
+// RotateSigningKey schedules the current key of the app for retirement at
+// retiresAt and stores next as the new current key atomically.
+func (r *AuthRepo) RotateSigningKey(_ context.Context, next entity.SigningKey, retiresAt time.Time) (int, error) {
+	const op = "internal - usecase - repo_memory - AuthRepo.RotateSigningKey"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.signingKeysByKid[next.KeyID]; ok {
+		return 0, fmt.Errorf("%s: %w", op, errDuplicateKeyID)
+	}
+
+	for id, key := range r.signingKeys {
+		if key.AppID == next.AppID && key.RetiresAt.IsZero() {
+			key.RetiresAt = retiresAt
+			r.signingKeys[id] = key
+		}
+	}
+
+	next.ID = r.nextID()
+	next.RetiresAt = time.Time{}
+	next.PrivateKey = cloneBytes(next.PrivateKey)
+	next.PublicKey = cloneBytes(next.PublicKey)
+	r.signingKeys[next.ID] = next
+	r.signingKeysByKid[next.KeyID] = next.ID
+
+	return next.ID, nil
+}
+
+func (r *AuthRepo) GetActiveSigningKey(_ context.Context, appID int) (entity.SigningKey, error) {
+	const op = "internal - usecase - repo_memory - AuthRepo.GetActiveSigningKey"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for id, key := range r.signingKeys {
+		if key.AppID == appID && key.RetiresAt.IsZero() {
+			return r.signingKey(id), nil
+		}
+	}
+
+	return entity.SigningKey{}, fmt.Errorf("%s: %w", op, entity.ErrSigningKeyNotFound)
+}
+
+func (r *AuthRepo) GetSigningKey(_ context.Context, kid string) (entity.SigningKey, error) {
+	const op = "internal - usecase - repo_memory - AuthRepo.GetSigningKey"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.signingKeysByKid[kid]
+	if !ok {
+		return entity.SigningKey{}, fmt.Errorf("%s: %w", op, entity.ErrSigningKeyNotFound)
+	}
+
+	return r.signingKey(id), nil
+}
+
+// GetPublicSigningKeys returns asymmetric keys that still verify tokens at now.
+func (r *AuthRepo) GetPublicSigningKeys(_ context.Context, now time.Time) ([]entity.SigningKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var keys []entity.SigningKey
+	for id, key := range r.signingKeys {
+		if key.PublicKey == nil || (!key.RetiresAt.IsZero() && !key.RetiresAt.After(now)) {
+			continue
+		}
+		keys = append(keys, r.signingKey(id))
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+
+	return keys, nil
+}
+
+func (r *AuthRepo) DeleteRetiredSigningKeys(_ context.Context, now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for id, key := range r.signingKeys {
+		if key.RetiresAt.IsZero() || key.RetiresAt.After(now) {
+			continue
+		}
+
+		delete(r.signingKeys, id)
+		delete(r.signingKeysByKid, key.KeyID)
+		count++
+	}
+
+	return count, nil
+}
+
+func (r *AuthRepo) signingKey(id int) entity.SigningKey {
+	key := r.signingKeys[id]
+	key.PrivateKey = cloneBytes(key.PrivateKey)
+	key.PublicKey = cloneBytes(key.PublicKey)
+
+	return key
+}
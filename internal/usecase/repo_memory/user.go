@@ -0,0 +1,225 @@
+package repo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+func (r *AuthRepo) InsertUser(_ context.Context, email string, passHash []byte) (int, error) {
+	const op = "internal - usecase - repo_memory - AuthRepo.InsertUser"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.usersByEmail[email]; ok {
+		return 0, fmt.Errorf("%s: %w", op, entity.ErrUserExists)
+	}
+
+	id := r.nextID()
+	r.users[id] = entity.User{ID: id, Email: email, PassHash: cloneBytes(passHash)}
+	r.usersByEmail[email] = id
+
+	return id, nil
+}
+
+func (r *AuthRepo) GetUser(_ context.Context, email string) (entity.User, error) {
+	const op = "internal - usecase - repo_memory - AuthRepo.GetUser"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.usersByEmail[email]
+	if !ok {
+		return entity.User{}, fmt.Errorf("%s: %w", op, entity.ErrUserNotFound)
+	}
+
+	return r.user(id), nil
+}
+
+func (r *AuthRepo) GetUserByID(_ context.Context, id int) (entity.User, error) {
+	const op = "internal - usecase - repo_memory - AuthRepo.GetUserByID"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.users[id]; !ok {
+		return entity.User{}, fmt.Errorf("%s: %w", op, entity.ErrUserNotFound)
+	}
+
+	return r.user(id), nil
+}
+
+func (r *AuthRepo) IsAdmin(_ context.Context, userID int) (bool, error) {
+	const op = "internal - usecase - repo_memory - AuthRepo.IsAdmin"
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[userID]
+	if !ok {
+		return false, fmt.Errorf("%s: %w", op, entity.ErrUserNotFound)
+	}
+
+	return user.IsAdmin, nil
+}
+
+func (r *AuthRepo) SetUserAdmin(_ context.Context, userID int, isAdmin bool) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[userID]
+	if !ok {
+		return 0, nil
+	}
+
+	user.IsAdmin = isAdmin
+	r.users[userID] = user
+
+	return 1, nil
+}
+
+func (r *AuthRepo) UpdateUserPassword(_ context.Context, userID int, passHash []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[userID]
+	if !ok {
+		return 0, nil
+	}
+
+	user.PassHash = cloneBytes(passHash)
+	r.users[userID] = user
+
+	return 1, nil
+}
+
+func (r *AuthRepo) RehashUserPassword(_ context.Context, userID int, oldHash []byte, newHash []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[userID]
+	if !ok || !bytes.Equal(user.PassHash, oldHash) {
+		return 0, nil
+	}
+
+	user.PassHash = cloneBytes(newHash)
+	r.users[userID] = user
+
+	return 1, nil
+}
+
+func (r *AuthRepo) ListEmailCollisions(_ context.Context) ([]entity.EmailCollision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collisions := make([]entity.EmailCollision, 0, len(r.emailCollisions))
+	for email, ids := range r.emailCollisions {
+		collision := entity.EmailCollision{Email: email}
+		for id := range ids {
+			collision.UserIDs = append(collision.UserIDs, id)
+		}
+		slices.Sort(collision.UserIDs)
+
+		collisions = append(collisions, collision)
+	}
+
+	slices.SortFunc(collisions, func(a, b entity.EmailCollision) int {
+		return strings.Compare(a.Email, b.Email)
+	})
+
+	return collisions, nil
+}
+
+func (r *AuthRepo) ListUnnormalizedEmails(_ context.Context) ([]entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var users []entity.User
+	for id, user := range r.users {
+		if user.Email == strings.ToLower(user.Email) && utf8.RuneCountInString(user.Email) == len(user.Email) {
+			continue
+		}
+
+		if r.isEmailCollision(id) {
+			continue
+		}
+
+		users = append(users, entity.User{ID: id, Email: user.Email})
+	}
+
+	slices.SortFunc(users, func(a, b entity.User) int {
+		return a.ID - b.ID
+	})
+
+	return users, nil
+}
+
+func (r *AuthRepo) UpdateUserEmail(_ context.Context, userID int, email string) (int, error) {
+	const op = "internal - usecase - repo_memory - AuthRepo.UpdateUserEmail"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[userID]
+	if !ok {
+		return 0, nil
+	}
+
+	if id, ok := r.usersByEmail[email]; ok && id != userID {
+		return 0, fmt.Errorf("%s: %w", op, entity.ErrUserExists)
+	}
+
+	delete(r.usersByEmail, user.Email)
+	user.Email = email
+	r.users[userID] = user
+	r.usersByEmail[email] = userID
+
+	return 1, nil
+}
+
+func (r *AuthRepo) AddEmailCollision(_ context.Context, email string, userID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := r.emailCollisions[email]
+	if ids == nil {
+		ids = make(map[int]struct{})
+	}
+
+	if _, ok := r.users[userID]; ok {
+		ids[userID] = struct{}{}
+	}
+
+	if id, ok := r.usersByEmail[email]; ok {
+		ids[id] = struct{}{}
+	}
+
+	if len(ids) > 0 {
+		r.emailCollisions[email] = ids
+	}
+
+	return nil
+}
+
+func (r *AuthRepo) isEmailCollision(userID int) bool {
+	for _, ids := range r.emailCollisions {
+		if _, ok := ids[userID]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *AuthRepo) user(id int) entity.User {
+	user := r.users[id]
+	user.PassHash = cloneBytes(user.PassHash)
+
+	return user
+}
@@ -0,0 +1,209 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+const appColumns = `id, name, pass_hash, secret, ttl_hours, signing_alg, disabled, require_verified_email`
+
+func (r *AuthRepo) GetAppForUser(ctx context.Context, id int) (entity.App, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.GetAppForUser"
+
+	app, err := scanApp(r.DB.QueryRowContext(ctx, `SELECT `+appColumns+` FROM apps WHERE id = $1`, id))
+	if err != nil {
+		return entity.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return app, nil
+}
+
+func (r *AuthRepo) GetAppByName(ctx context.Context, name string) (entity.App, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.GetAppByName"
+
+	app, err := scanApp(r.DB.QueryRowContext(ctx, `SELECT `+appColumns+` FROM apps WHERE name = $1`, name))
+	if err != nil {
+		return entity.App{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return app, nil
+}
+
+func (r *AuthRepo) InsertApp(ctx context.Context, name string, passHash []byte, secret string, ttlHour int) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.InsertApp"
+
+	var id int
+	err := r.DB.QueryRowContext(ctx,
+		`INSERT INTO apps(name, pass_hash, secret, ttl_hours) VALUES($1, $2, $3, $4) RETURNING id`,
+		name, passHash, secret, ttlHour,
+	).Scan(&id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("%s: %w", op, entity.ErrAppExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *AuthRepo) UpdateApp(ctx context.Context, id_ int, secret string, ttlHour int) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.UpdateApp"
+
+	res, err := r.DB.ExecContext(ctx, `UPDATE apps SET secret = $1, ttl_hours = $2 WHERE id = $3`, secret, ttlHour, id_)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+func (r *AuthRepo) UpdateAppSigningAlg(ctx context.Context, id_ int, alg string) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.UpdateAppSigningAlg"
+
+	res, err := r.DB.ExecContext(ctx, `UPDATE apps SET signing_alg = $1 WHERE id = $2`, alg, id_)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+func (r *AuthRepo) UpdateAppPassword(ctx context.Context, id_ int, passHash []byte) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.UpdateAppPassword"
+
+	res, err := r.DB.ExecContext(ctx, `UPDATE apps SET pass_hash = $1 WHERE id = $2`, passHash, id_)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+// DeleteApp removes the app with its revoked tokens. Signing keys, refresh
+// tokens and roles go with it through the foreign keys. Auth events are kept
+// as the audit trail.
+func (r *AuthRepo) DeleteApp(ctx context.Context, id_ int) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.DeleteApp"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM revoked_tokens WHERE app_id = $1`, id_); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM apps WHERE id = $1`, id_)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+func (r *AuthRepo) SetAppDisabled(ctx context.Context, id_ int, disabled bool) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.SetAppDisabled"
+
+	res, err := r.DB.ExecContext(ctx, `UPDATE apps SET disabled = $1 WHERE id = $2`, disabled, id_)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+// ListApps returns up to limit apps with ids above afterID in id order.
+func (r *AuthRepo) ListApps(ctx context.Context, afterID int, limit int) ([]entity.App, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.ListApps"
+
+	rows, err := r.DB.QueryContext(ctx, `SELECT `+appColumns+` FROM apps WHERE id > $1 ORDER BY id LIMIT $2`, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var apps []entity.App
+	for rows.Next() {
+		app, err := scanApp(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		apps = append(apps, app)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return apps, nil
+}
+
+func (r *AuthRepo) SetAppEmailPolicy(ctx context.Context, id_ int, requireVerified bool) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.SetAppEmailPolicy"
+
+	res, err := r.DB.ExecContext(ctx, `UPDATE apps SET require_verified_email = $1 WHERE id = $2`, requireVerified, id_)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanApp(row scanner) (entity.App, error) {
+	var app entity.App
+	err := row.Scan(&app.ID, &app.Name, &app.PassHash, &app.Secret, &app.TTLHours, &app.SigningAlg, &app.Disabled, &app.RequireVerifiedEmail)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.App{}, entity.ErrAppNotFound
+		}
+
+		return entity.App{}, err
+	}
+
+	return app, nil
+}
@@ -0,0 +1,61 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+)
+
+// SetAppScopes replaces the scopes of the app with scopes in one transaction.
+func (r *AuthRepo) SetAppScopes(ctx context.Context, appID int, scopes []string) error {
+	const op = "internal - usecase - repo_postgres - AuthRepo.SetAppScopes"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM app_scopes WHERE app_id = $1`, appID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, scope := range scopes {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO app_scopes(app_id, scope) VALUES($1, $2) ON CONFLICT DO NOTHING`,
+			appID, scope,
+		)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepo) GetAppScopes(ctx context.Context, appID int) ([]string, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.GetAppScopes"
+
+	rows, err := r.DB.QueryContext(ctx, `SELECT scope FROM app_scopes WHERE app_id = $1 ORDER BY scope`, appID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var scopes []string
+	for rows.Next() {
+		var scope string
+		if err := rows.Scan(&scope); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		scopes = append(scopes, scope)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return scopes, nil
+}
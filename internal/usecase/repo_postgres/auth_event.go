@@ -0,0 +1,91 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+func (r *AuthRepo) InsertAuthEvent(ctx context.Context, event entity.AuthEvent) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.InsertAuthEvent"
+
+	var id int
+	err := r.DB.QueryRowContext(ctx,
+		`INSERT INTO auth_events(type, created_at, app_id, user_id, email, peer_addr, user_agent, details)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		event.Type, event.CreatedAt.Unix(), nullInt(event.AppID), nullInt(event.UserID),
+		event.Email, event.PeerAddr, event.UserAgent, event.Details,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// ListAuthEvents returns events matching filter, newest first.
+func (r *AuthRepo) ListAuthEvents(ctx context.Context, filter entity.AuthEventFilter) ([]entity.AuthEvent, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.ListAuthEvents"
+
+	var where []string
+	var p placeholders
+
+	if filter.UserID != 0 {
+		where = append(where, "user_id = "+p.add(filter.UserID))
+	}
+	if filter.AppID != 0 {
+		where = append(where, "app_id = "+p.add(filter.AppID))
+	}
+	if len(filter.Types) > 0 {
+		types := make([]string, 0, len(filter.Types))
+		for _, t := range filter.Types {
+			types = append(types, p.add(t))
+		}
+		where = append(where, "type IN ("+strings.Join(types, ", ")+")")
+	}
+	if filter.Email != "" {
+		where = append(where, "email = "+p.add(filter.Email))
+	}
+	if !filter.Since.IsZero() {
+		where = append(where, "created_at >= "+p.add(filter.Since.Unix()))
+	}
+	if !filter.Until.IsZero() {
+		where = append(where, "created_at < "+p.add(filter.Until.Unix()))
+	}
+	if filter.BeforeID != 0 {
+		where = append(where, "id < "+p.add(filter.BeforeID))
+	}
+
+	query := `SELECT id, type, created_at, COALESCE(app_id, 0), COALESCE(user_id, 0), email, peer_addr, user_agent, details FROM auth_events`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY id DESC LIMIT " + p.add(filter.Limit)
+
+	rows, err := r.DB.QueryContext(ctx, query, p.args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var events []entity.AuthEvent
+	for rows.Next() {
+		var event entity.AuthEvent
+		var createdAt int64
+		err := rows.Scan(&event.ID, &event.Type, &createdAt, &event.AppID, &event.UserID,
+			&event.Email, &event.PeerAddr, &event.UserAgent, &event.Details)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		event.CreatedAt = time.Unix(createdAt, 0)
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,114 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+)
+
+// SetAppClaims replaces the claim templates of the app with claims in one
+// transaction.
+func (r *AuthRepo) SetAppClaims(ctx context.Context, appID int, claims map[string]string) error {
+	const op = "internal - usecase - repo_postgres - AuthRepo.SetAppClaims"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM app_claims WHERE app_id = $1`, appID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for name, template := range claims {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO app_claims(app_id, name, template) VALUES($1, $2, $3)`,
+			appID, name, template,
+		)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepo) GetAppClaims(ctx context.Context, appID int) (map[string]string, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.GetAppClaims"
+
+	values, err := r.queryMap(ctx, `SELECT name, template FROM app_claims WHERE app_id = $1`, appID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return values, nil
+}
+
+func (r *AuthRepo) SetUserAttribute(ctx context.Context, userID int, key string, value string) error {
+	const op = "internal - usecase - repo_postgres - AuthRepo.SetUserAttribute"
+
+	_, err := r.DB.ExecContext(ctx,
+		`INSERT INTO user_attributes(user_id, key, value) VALUES($1, $2, $3)
+		ON CONFLICT(user_id, key) DO UPDATE SET value = excluded.value`,
+		userID, key, value,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepo) DeleteUserAttribute(ctx context.Context, userID int, key string) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.DeleteUserAttribute"
+
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM user_attributes WHERE user_id = $1 AND key = $2`, userID, key)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+func (r *AuthRepo) GetUserAttributes(ctx context.Context, userID int) (map[string]string, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.GetUserAttributes"
+
+	values, err := r.queryMap(ctx, `SELECT key, value FROM user_attributes WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return values, nil
+}
+
+// queryMap runs a query selecting key and value columns.
+func (r *AuthRepo) queryMap(ctx context.Context, query string, args ...any) (map[string]string, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
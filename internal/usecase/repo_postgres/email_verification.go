@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+func (r *AuthRepo) InsertEmailVerification(ctx context.Context, verification entity.EmailVerification) error {
+	const op = "internal - usecase - repo_postgres - AuthRepo.InsertEmailVerification"
+
+	_, err := r.DB.ExecContext(ctx,
+		`INSERT INTO email_verifications(token_hash, user_id, created_at, expires_at) VALUES($1, $2, $3, $4)`,
+		verification.TokenHash, verification.UserID, verification.CreatedAt.Unix(), verification.ExpiresAt.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// VerifyEmail consumes the verification token with tokenHash and marks the
+// email of its user as verified in one transaction. Other outstanding tokens
+// of the user are dropped. It fails with entity.ErrEmailVerificationNotFound
+// if the token is unknown, already used or expired at now.
+func (r *AuthRepo) VerifyEmail(ctx context.Context, tokenHash []byte, now time.Time) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.VerifyEmail"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var userID int
+	err = tx.QueryRowContext(ctx,
+		`DELETE FROM email_verifications WHERE token_hash = $1 AND expires_at > $2 RETURNING user_id`,
+		tokenHash, now.Unix(),
+	).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, entity.ErrEmailVerificationNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE users SET email_verified = TRUE WHERE id = $1`, userID); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM email_verifications WHERE user_id = $1`, userID); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userID, nil
+}
+
+func (r *AuthRepo) DeleteExpiredEmailVerifications(ctx context.Context, now time.Time) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.DeleteExpiredEmailVerifications"
+
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM email_verifications WHERE expires_at <= $1`, now.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
@@ -0,0 +1,104 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+// GetLoginFailure returns the failures recorded for key. A key without
+// failures yields a zero LoginFailure.
+func (r *AuthRepo) GetLoginFailure(ctx context.Context, key string) (entity.LoginFailure, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.GetLoginFailure"
+
+	failure := entity.LoginFailure{Key: key}
+	var lastFailedAt, lockedUntil int64
+
+	err := r.DB.QueryRowContext(ctx,
+		`SELECT failures, last_failed_at, locked_until FROM login_failures WHERE key = $1`, key,
+	).Scan(&failure.Failures, &lastFailedAt, &lockedUntil)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return failure, nil
+		}
+
+		return entity.LoginFailure{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	failure.LastFailedAt = time.UnixMilli(lastFailedAt)
+	failure.LockedUntil = time.UnixMilli(lockedUntil)
+
+	return failure, nil
+}
+
+// RecordLoginFailure increments the failures of key, starting over when the
+// previous failure happened before windowStart, and returns the new count.
+func (r *AuthRepo) RecordLoginFailure(ctx context.Context, key string, now time.Time, windowStart time.Time) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.RecordLoginFailure"
+
+	var failures int
+	err := r.DB.QueryRowContext(ctx,
+		`INSERT INTO login_failures(key, failures, last_failed_at) VALUES($1, 1, $2)
+		ON CONFLICT(key) DO UPDATE SET
+			failures = CASE WHEN login_failures.last_failed_at < $3 THEN 1 ELSE login_failures.failures + 1 END,
+			last_failed_at = excluded.last_failed_at
+		RETURNING failures`,
+		key, now.UnixMilli(), windowStart.UnixMilli(),
+	).Scan(&failures)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return failures, nil
+}
+
+func (r *AuthRepo) LockLogin(ctx context.Context, key string, until time.Time) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.LockLogin"
+
+	res, err := r.DB.ExecContext(ctx, `UPDATE login_failures SET locked_until = $1 WHERE key = $2`, until.UnixMilli(), key)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+func (r *AuthRepo) DeleteLoginFailure(ctx context.Context, key string) error {
+	const op = "internal - usecase - repo_postgres - AuthRepo.DeleteLoginFailure"
+
+	if _, err := r.DB.ExecContext(ctx, `DELETE FROM login_failures WHERE key = $1`, key); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteStaleLoginFailures removes unlocked records whose last failure happened
+// before windowStart.
+func (r *AuthRepo) DeleteStaleLoginFailures(ctx context.Context, now time.Time, windowStart time.Time) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.DeleteStaleLoginFailures"
+
+	res, err := r.DB.ExecContext(ctx,
+		`DELETE FROM login_failures WHERE last_failed_at < $1 AND locked_until <= $2`,
+		windowStart.UnixMilli(), now.UnixMilli(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
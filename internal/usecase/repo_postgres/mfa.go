@@ -0,0 +1,255 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+// SaveTOTP stores an unconfirmed enrollment of the user, replacing a previous
+// unconfirmed one. It returns 0 and stores nothing if the user already has
+// TOTP enabled.
+func (r *AuthRepo) SaveTOTP(ctx context.Context, totp entity.TOTP) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.SaveTOTP"
+
+	res, err := r.DB.ExecContext(ctx,
+		`INSERT INTO user_totp(user_id, secret, confirmed, last_step, created_at) VALUES($1, $2, FALSE, 0, $3)
+		ON CONFLICT(user_id) DO UPDATE SET secret = excluded.secret, last_step = 0, created_at = excluded.created_at
+		WHERE user_totp.confirmed = FALSE`,
+		totp.UserID, totp.Secret, totp.CreatedAt.Unix(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+func (r *AuthRepo) GetTOTP(ctx context.Context, userID int) (entity.TOTP, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.GetTOTP"
+
+	var totp entity.TOTP
+	var createdAt int64
+	err := r.DB.QueryRowContext(ctx,
+		`SELECT user_id, secret, confirmed, last_step, created_at FROM user_totp WHERE user_id = $1`,
+		userID,
+	).Scan(&totp.UserID, &totp.Secret, &totp.Confirmed, &totp.LastStep, &createdAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.TOTP{}, fmt.Errorf("%s: %w", op, entity.ErrTOTPNotFound)
+		}
+
+		return entity.TOTP{}, fmt.Errorf("%s: %w", op, err)
+	}
+	totp.CreatedAt = time.Unix(createdAt, 0)
+
+	return totp, nil
+}
+
+// ConfirmTOTP enables the pending enrollment of the user, accepting step as
+// its first code, and replaces the user's recovery codes with codeHashes in
+// one transaction. It returns 0 and changes nothing if there is no pending
+// enrollment.
+func (r *AuthRepo) ConfirmTOTP(ctx context.Context, userID int, step int64, codeHashes [][]byte) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.ConfirmTOTP"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE user_totp SET confirmed = TRUE, last_step = $1 WHERE user_id = $2 AND confirmed = FALSE`,
+		step, userID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		return 0, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, hash := range codeHashes {
+		_, err := tx.ExecContext(ctx, `INSERT INTO recovery_codes(code_hash, user_id) VALUES($1, $2)`, hash, userID)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+// UseTOTPStep records step as the last accepted code of the user. It returns
+// 0 if TOTP is not enabled or a code of step or a later one was already
+// accepted.
+func (r *AuthRepo) UseTOTPStep(ctx context.Context, userID int, step int64) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.UseTOTPStep"
+
+	res, err := r.DB.ExecContext(ctx,
+		`UPDATE user_totp SET last_step = $1 WHERE user_id = $2 AND confirmed = TRUE AND last_step < $1`,
+		step, userID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+// UseRecoveryCode consumes the recovery code of the user with codeHash. It
+// returns 0 if there is no such code.
+func (r *AuthRepo) UseRecoveryCode(ctx context.Context, userID int, codeHash []byte) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.UseRecoveryCode"
+
+	res, err := r.DB.ExecContext(ctx,
+		`DELETE FROM recovery_codes WHERE code_hash = $1 AND user_id = $2`,
+		codeHash, userID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+// DeleteTOTP removes the enrollment of the user together with its recovery
+// codes and outstanding challenges.
+func (r *AuthRepo) DeleteTOTP(ctx context.Context, userID int) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.DeleteTOTP"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM user_totp WHERE user_id = $1`, userID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_challenges WHERE user_id = $1`, userID); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+func (r *AuthRepo) InsertMFAChallenge(ctx context.Context, challenge entity.MFAChallenge) error {
+	const op = "internal - usecase - repo_postgres - AuthRepo.InsertMFAChallenge"
+
+	_, err := r.DB.ExecContext(ctx,
+		`INSERT INTO mfa_challenges(token_hash, user_id, app_id, with_refresh, created_at, expires_at)
+		VALUES($1, $2, $3, $4, $5, $6)`,
+		challenge.TokenHash, challenge.UserID, challenge.AppID, challenge.WithRefresh,
+		challenge.CreatedAt.Unix(), challenge.ExpiresAt.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetMFAChallenge fails with entity.ErrMFAChallengeNotFound if the challenge
+// with tokenHash is unknown, already used or expired at now.
+func (r *AuthRepo) GetMFAChallenge(ctx context.Context, tokenHash []byte, now time.Time) (entity.MFAChallenge, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.GetMFAChallenge"
+
+	var challenge entity.MFAChallenge
+	var createdAt, expiresAt int64
+	err := r.DB.QueryRowContext(ctx,
+		`SELECT token_hash, user_id, app_id, with_refresh, created_at, expires_at FROM mfa_challenges
+		WHERE token_hash = $1 AND expires_at > $2`,
+		tokenHash, now.Unix(),
+	).Scan(&challenge.TokenHash, &challenge.UserID, &challenge.AppID, &challenge.WithRefresh, &createdAt, &expiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.MFAChallenge{}, fmt.Errorf("%s: %w", op, entity.ErrMFAChallengeNotFound)
+		}
+
+		return entity.MFAChallenge{}, fmt.Errorf("%s: %w", op, err)
+	}
+	challenge.CreatedAt = time.Unix(createdAt, 0)
+	challenge.ExpiresAt = time.Unix(expiresAt, 0)
+
+	return challenge, nil
+}
+
+func (r *AuthRepo) DeleteMFAChallenge(ctx context.Context, tokenHash []byte) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.DeleteMFAChallenge"
+
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM mfa_challenges WHERE token_hash = $1`, tokenHash)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+func (r *AuthRepo) DeleteExpiredMFAChallenges(ctx context.Context, now time.Time) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.DeleteExpiredMFAChallenges"
+
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM mfa_challenges WHERE expires_at <= $1`, now.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
@@ -0,0 +1,175 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+// SetAppRedirectURIs replaces the redirect uris of the app with uris in one
+// transaction.
+func (r *AuthRepo) SetAppRedirectURIs(ctx context.Context, appID int, uris []string) error {
+	const op = "internal - usecase - repo_postgres - AuthRepo.SetAppRedirectURIs"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM app_redirect_uris WHERE app_id = $1`, appID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, uri := range uris {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO app_redirect_uris(app_id, uri) VALUES($1, $2) ON CONFLICT DO NOTHING`,
+			appID, uri,
+		)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepo) GetAppRedirectURIs(ctx context.Context, appID int) ([]string, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.GetAppRedirectURIs"
+
+	rows, err := r.DB.QueryContext(ctx, `SELECT uri FROM app_redirect_uris WHERE app_id = $1 ORDER BY uri`, appID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var uris []string
+	for rows.Next() {
+		var uri string
+		if err := rows.Scan(&uri); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		uris = append(uris, uri)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return uris, nil
+}
+
+func (r *AuthRepo) InsertAuthorizationCode(ctx context.Context, code entity.AuthorizationCode) error {
+	const op = "internal - usecase - repo_postgres - AuthRepo.InsertAuthorizationCode"
+
+	_, err := r.DB.ExecContext(ctx,
+		`INSERT INTO authorization_codes(code_hash, app_id, user_id, redirect_uri, code_challenge, scope, nonce, created_at, expires_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		code.CodeHash, code.AppID, code.UserID, code.RedirectURI, code.CodeChallenge, code.Scope, code.Nonce,
+		code.CreatedAt.Unix(), code.ExpiresAt.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ConsumeAuthorizationCode marks the code with codeHash used and returns it.
+// A code that was used before is returned unchanged with Used set. It fails
+// with entity.ErrAuthorizationCodeNotFound if the code is unknown or expired
+// at now.
+func (r *AuthRepo) ConsumeAuthorizationCode(ctx context.Context, codeHash []byte, now time.Time) (entity.AuthorizationCode, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.ConsumeAuthorizationCode"
+
+	var code entity.AuthorizationCode
+	var createdAt, expiresAt int64
+	err := r.DB.QueryRowContext(ctx,
+		`UPDATE authorization_codes SET used = TRUE WHERE code_hash = $1 AND used = FALSE AND expires_at > $2
+		RETURNING code_hash, app_id, user_id, redirect_uri, code_challenge, scope, nonce, created_at, expires_at`,
+		codeHash, now.Unix(),
+	).Scan(
+		&code.CodeHash, &code.AppID, &code.UserID, &code.RedirectURI, &code.CodeChallenge, &code.Scope, &code.Nonce,
+		&createdAt, &expiresAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		used, err := r.getUsedAuthorizationCode(ctx, codeHash, now)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return entity.AuthorizationCode{}, fmt.Errorf("%s: %w", op, entity.ErrAuthorizationCodeNotFound)
+			}
+
+			return entity.AuthorizationCode{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return used, nil
+	}
+	if err != nil {
+		return entity.AuthorizationCode{}, fmt.Errorf("%s: %w", op, err)
+	}
+	code.CreatedAt = time.Unix(createdAt, 0)
+	code.ExpiresAt = time.Unix(expiresAt, 0)
+
+	return code, nil
+}
+
+func (r *AuthRepo) getUsedAuthorizationCode(ctx context.Context, codeHash []byte, now time.Time) (entity.AuthorizationCode, error) {
+	code := entity.AuthorizationCode{Used: true}
+	var createdAt, expiresAt, tokenExpiresAt int64
+	err := r.DB.QueryRowContext(ctx,
+		`SELECT code_hash, app_id, user_id, redirect_uri, code_challenge, scope, nonce, created_at, expires_at,
+		token_id, token_expires_at, family_id FROM authorization_codes WHERE code_hash = $1 AND used = TRUE AND expires_at > $2`,
+		codeHash, now.Unix(),
+	).Scan(
+		&code.CodeHash, &code.AppID, &code.UserID, &code.RedirectURI, &code.CodeChallenge, &code.Scope, &code.Nonce,
+		&createdAt, &expiresAt, &code.TokenID, &tokenExpiresAt, &code.FamilyID,
+	)
+	if err != nil {
+		return entity.AuthorizationCode{}, err
+	}
+	code.CreatedAt = time.Unix(createdAt, 0)
+	code.ExpiresAt = time.Unix(expiresAt, 0)
+	code.TokenExpiresAt = time.Unix(tokenExpiresAt, 0)
+
+	return code, nil
+}
+
+// SetAuthorizationCodeTokens records the tokens issued for the used code
+// code.CodeHash and keeps the code until code.ExpiresAt.
+func (r *AuthRepo) SetAuthorizationCodeTokens(ctx context.Context, code entity.AuthorizationCode) error {
+	const op = "internal - usecase - repo_postgres - AuthRepo.SetAuthorizationCodeTokens"
+
+	_, err := r.DB.ExecContext(ctx,
+		`UPDATE authorization_codes SET token_id = $1, token_expires_at = $2, family_id = $3, expires_at = $4
+		WHERE code_hash = $5`,
+		code.TokenID, code.TokenExpiresAt.Unix(), code.FamilyID, code.ExpiresAt.Unix(), code.CodeHash,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepo) DeleteExpiredAuthorizationCodes(ctx context.Context, now time.Time) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.DeleteExpiredAuthorizationCodes"
+
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM authorization_codes WHERE expires_at <= $1`, now.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
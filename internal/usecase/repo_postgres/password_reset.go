@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+func (r *AuthRepo) InsertPasswordReset(ctx context.Context, reset entity.PasswordReset) error {
+	const op = "internal - usecase - repo_postgres - AuthRepo.InsertPasswordReset"
+
+	_, err := r.DB.ExecContext(ctx,
+		`INSERT INTO password_resets(token_hash, user_id, created_at, expires_at) VALUES($1, $2, $3, $4)`,
+		reset.TokenHash, reset.UserID, reset.CreatedAt.Unix(), reset.ExpiresAt.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ResetPassword consumes the reset token with tokenHash and stores passHash for
+// its user in one transaction. All other outstanding resets of the user are
+// dropped as well. It fails with entity.ErrPasswordResetNotFound if the token
+// is unknown, already used or expired at now.
+func (r *AuthRepo) ResetPassword(ctx context.Context, tokenHash []byte, now time.Time, passHash []byte) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.ResetPassword"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var userID int
+	err = tx.QueryRowContext(ctx,
+		`DELETE FROM password_resets WHERE token_hash = $1 AND expires_at > $2 RETURNING user_id`,
+		tokenHash, now.Unix(),
+	).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, entity.ErrPasswordResetNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE users SET pass_hash = $1 WHERE id = $2`, passHash, userID); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM password_resets WHERE user_id = $1`, userID); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userID, nil
+}
+
+func (r *AuthRepo) DeleteExpiredPasswordResets(ctx context.Context, now time.Time) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.DeleteExpiredPasswordResets"
+
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM password_resets WHERE expires_at <= $1`, now.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
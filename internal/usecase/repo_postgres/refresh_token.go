@@ -0,0 +1,131 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+func (r *AuthRepo) InsertRefreshToken(ctx context.Context, token entity.RefreshToken) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.InsertRefreshToken"
+
+	var id int
+	err := r.DB.QueryRowContext(ctx,
+		`INSERT INTO refresh_tokens(token_hash, family_id, user_id, app_id, expires_at) VALUES($1, $2, $3, $4, $5) RETURNING id`,
+		token.TokenHash, token.FamilyID, token.UserID, token.AppID, token.ExpiresAt.Unix(),
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *AuthRepo) GetRefreshToken(ctx context.Context, tokenHash []byte) (entity.RefreshToken, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.GetRefreshToken"
+
+	row := r.DB.QueryRowContext(ctx,
+		`SELECT id, token_hash, family_id, user_id, app_id, expires_at, rotated_at IS NOT NULL, revoked_at IS NOT NULL FROM refresh_tokens WHERE token_hash = $1`,
+		tokenHash,
+	)
+
+	var token entity.RefreshToken
+	var expiresAt int64
+	err := row.Scan(&token.ID, &token.TokenHash, &token.FamilyID, &token.UserID, &token.AppID, &expiresAt, &token.Rotated, &token.Revoked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.RefreshToken{}, fmt.Errorf("%s: %w", op, entity.ErrRefreshTokenNotFound)
+		}
+
+		return entity.RefreshToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+	token.ExpiresAt = time.Unix(expiresAt, 0)
+
+	return token, nil
+}
+
+// RotateRefreshToken marks the token with id_ as used and stores its successor
+// in one transaction. It fails with entity.ErrRefreshTokenReused if the token
+// has already been rotated or revoked concurrently.
+func (r *AuthRepo) RotateRefreshToken(ctx context.Context, id_ int, next entity.RefreshToken) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.RotateRefreshToken"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE refresh_tokens SET rotated_at = $1 WHERE id = $2 AND rotated_at IS NULL AND revoked_at IS NULL`,
+		time.Now().Unix(), id_,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return 0, fmt.Errorf("%s: %w", op, entity.ErrRefreshTokenReused)
+	}
+
+	var id int
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO refresh_tokens(token_hash, family_id, user_id, app_id, expires_at) VALUES($1, $2, $3, $4, $5) RETURNING id`,
+		next.TokenHash, next.FamilyID, next.UserID, next.AppID, next.ExpiresAt.Unix(),
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *AuthRepo) RevokeRefreshTokenFamily(ctx context.Context, familyID string) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.RevokeRefreshTokenFamily"
+
+	res, err := r.DB.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL`,
+		time.Now().Unix(), familyID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+func (r *AuthRepo) RevokeUserRefreshTokens(ctx context.Context, userID int) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.RevokeUserRefreshTokens"
+
+	res, err := r.DB.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL`,
+		time.Now().Unix(), userID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
@@ -0,0 +1,45 @@
+// Package repo implements usecase.AuthRepo on top of Postgres.
+//
+// Queries are run directly rather than through DB.Prepare: pgx already caches
+// prepared statements per connection.
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/1kovalevskiy/sso/pkg/postgres"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const _uniqueViolation = "23505"
+
+type AuthRepo struct {
+	*postgres.Postgres
+}
+
+func New(pg *postgres.Postgres) *AuthRepo {
+	return &AuthRepo{pg}
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && pgErr.Code == _uniqueViolation
+}
+
+func nullInt(v int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(v), Valid: v != 0}
+}
+
+// placeholders collects query arguments and returns their $N placeholders.
+type placeholders struct {
+	args []any
+}
+
+func (p *placeholders) add(v any) string {
+	p.args = append(p.args, v)
+
+	return "$" + strconv.Itoa(len(p.args))
+}
@@ -0,0 +1,197 @@
+//go:build postgres
+
+// These tests need a Postgres database: POSTGRES_TEST_URL=postgres://... go
+// test -tags postgres ./internal/usecase/repo_postgres/. The migrations are
+// applied to it first, every test works on its own rows.
+package repo_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	repo "github.com/1kovalevskiy/sso/internal/usecase/repo_postgres"
+	"github.com/1kovalevskiy/sso/pkg/migrator"
+	"github.com/1kovalevskiy/sso/pkg/postgres"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const _migrationsPath = "../../../migrations/postgres"
+
+var _url = os.Getenv("POSTGRES_TEST_URL")
+
+func TestMain(m *testing.M) {
+	if _url != "" {
+		migrator.MigrateUp(_migrationsPath, migrator.PostgresURL(_url, "migrations"))
+	}
+
+	os.Exit(m.Run())
+}
+
+func newRepo(t *testing.T) (context.Context, *repo.AuthRepo) {
+	t.Helper()
+
+	if _url == "" {
+		t.Skip("POSTGRES_TEST_URL is not set")
+	}
+
+	pg, err := postgres.New(_url, "5s")
+	require.NoError(t, err)
+	t.Cleanup(pg.Close)
+
+	return context.Background(), repo.New(pg)
+}
+
+func insertUser(t *testing.T, ctx context.Context, r *repo.AuthRepo) (int, string) {
+	t.Helper()
+
+	email := gofakeit.Email()
+	id, err := r.InsertUser(ctx, email, []byte("hash"))
+	require.NoError(t, err)
+
+	return id, email
+}
+
+func insertApp(t *testing.T, ctx context.Context, r *repo.AuthRepo) int {
+	t.Helper()
+
+	id, err := r.InsertApp(ctx, gofakeit.UUID(), []byte("hash"), "secret", 1)
+	require.NoError(t, err)
+
+	return id
+}
+
+func TestUniqueViolations(t *testing.T) {
+	ctx, r := newRepo(t)
+
+	userID, email := insertUser(t, ctx, r)
+	_, err := r.InsertUser(ctx, email, []byte("hash"))
+	assert.ErrorIs(t, err, entity.ErrUserExists)
+
+	otherID, _ := insertUser(t, ctx, r)
+	_, err = r.UpdateUserEmail(ctx, otherID, email)
+	assert.ErrorIs(t, err, entity.ErrUserExists)
+
+	appID := insertApp(t, ctx, r)
+	app, err := r.GetAppForUser(ctx, appID)
+	require.NoError(t, err)
+	_, err = r.InsertApp(ctx, app.Name, []byte("hash"), "secret", 1)
+	assert.ErrorIs(t, err, entity.ErrAppExists)
+
+	require.NoError(t, r.AddEmailCollision(ctx, email, otherID))
+	collisions, err := r.ListEmailCollisions(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, collisions, entity.EmailCollision{Email: email, UserIDs: []int{userID, otherID}})
+}
+
+func TestRevokeRefreshTokenFamily(t *testing.T) {
+	ctx, r := newRepo(t)
+
+	appID := insertApp(t, ctx, r)
+	userID, _ := insertUser(t, ctx, r)
+
+	familyID := gofakeit.UUID()
+	hashes := [][]byte{[]byte(gofakeit.UUID()), []byte(gofakeit.UUID())}
+	for _, hash := range hashes {
+		_, err := r.InsertRefreshToken(ctx, entity.RefreshToken{
+			TokenHash: hash,
+			FamilyID:  familyID,
+			UserID:    userID,
+			AppID:     appID,
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+	}
+
+	count, err := r.RevokeRefreshTokenFamily(ctx, familyID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	for _, hash := range hashes {
+		token, err := r.GetRefreshToken(ctx, hash)
+		require.NoError(t, err)
+		assert.True(t, token.Revoked)
+	}
+
+	_, err = r.GetRefreshToken(ctx, []byte(gofakeit.UUID()))
+	assert.ErrorIs(t, err, entity.ErrRefreshTokenNotFound)
+}
+
+func TestGetUserAccess(t *testing.T) {
+	ctx, r := newRepo(t)
+
+	appID := insertApp(t, ctx, r)
+	userID, _ := insertUser(t, ctx, r)
+
+	roleID, err := r.UpsertRole(ctx, appID, "editor", []string{"posts:write"})
+	require.NoError(t, err)
+	require.NoError(t, r.AssignRole(ctx, userID, roleID))
+
+	access, err := r.GetUserAccess(ctx, userID, appID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"editor"}, access.Roles)
+	assert.Equal(t, []string{"posts:write"}, access.Permissions)
+
+	otherAppID := insertApp(t, ctx, r)
+	access, err = r.GetUserAccess(ctx, userID, otherAppID)
+	require.NoError(t, err)
+	assert.Empty(t, access.Roles)
+}
+
+func TestDeleteAppCascades(t *testing.T) {
+	ctx, r := newRepo(t)
+
+	appID := insertApp(t, ctx, r)
+	userID, _ := insertUser(t, ctx, r)
+
+	roleID, err := r.UpsertRole(ctx, appID, "editor", []string{"posts:write"})
+	require.NoError(t, err)
+	require.NoError(t, r.AssignRole(ctx, userID, roleID))
+	require.NoError(t, r.SetAppScopes(ctx, appID, []string{"orders:read"}))
+	require.NoError(t, r.SetAppClaims(ctx, appID, map[string]string{"tenant": "{{tenant}}"}))
+
+	tokenHash := []byte(gofakeit.UUID())
+	_, err = r.InsertRefreshToken(ctx, entity.RefreshToken{
+		TokenHash: tokenHash,
+		FamilyID:  gofakeit.UUID(),
+		UserID:    userID,
+		AppID:     appID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	count, err := r.DeleteApp(ctx, appID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	_, err = r.GetAppForUser(ctx, appID)
+	assert.ErrorIs(t, err, entity.ErrAppNotFound)
+
+	_, err = r.GetRole(ctx, appID, "editor")
+	assert.ErrorIs(t, err, entity.ErrRoleNotFound)
+
+	_, err = r.GetRefreshToken(ctx, tokenHash)
+	assert.ErrorIs(t, err, entity.ErrRefreshTokenNotFound)
+
+	access, err := r.GetUserAccess(ctx, userID, appID)
+	require.NoError(t, err)
+	assert.Empty(t, access.Roles)
+	assert.Empty(t, access.Permissions)
+
+	scopes, err := r.GetAppScopes(ctx, appID)
+	require.NoError(t, err)
+	assert.Empty(t, scopes)
+
+	claims, err := r.GetAppClaims(ctx, appID)
+	require.NoError(t, err)
+	assert.Empty(t, claims)
+
+	// The user outlives the app.
+	_, err = r.GetUserByID(ctx, userID)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,49 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func (r *AuthRepo) InsertRevokedToken(ctx context.Context, jti string, userID int, appID int, expiresAt time.Time) error {
+	const op = "internal - usecase - repo_postgres - AuthRepo.InsertRevokedToken"
+
+	_, err := r.DB.ExecContext(ctx,
+		`INSERT INTO revoked_tokens(jti, user_id, app_id, expires_at, revoked_at) VALUES($1, $2, $3, $4, $5) ON CONFLICT(jti) DO NOTHING`,
+		jti, userID, appID, expiresAt.Unix(), time.Now().Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepo) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.IsTokenRevoked"
+
+	var revoked bool
+	err := r.DB.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = $1)`, jti).Scan(&revoked)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return revoked, nil
+}
+
+func (r *AuthRepo) DeleteExpiredRevokedTokens(ctx context.Context, now time.Time) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.DeleteExpiredRevokedTokens"
+
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM revoked_tokens WHERE expires_at <= $1`, now.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
@@ -0,0 +1,150 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+// UpsertRole creates the role within the app or replaces the permissions of
+// an existing one.
+func (r *AuthRepo) UpsertRole(ctx context.Context, appID int, name string, permissions []string) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.UpsertRole"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO roles(app_id, name) VALUES($1, $2) ON CONFLICT(app_id, name) DO UPDATE SET name = excluded.name RETURNING id`,
+		appID, name,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM role_permissions WHERE role_id = $1`, id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, permission := range permissions {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO role_permissions(role_id, permission) VALUES($1, $2) ON CONFLICT DO NOTHING`,
+			id, permission,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *AuthRepo) GetRole(ctx context.Context, appID int, name string) (entity.Role, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.GetRole"
+
+	row := r.DB.QueryRowContext(ctx, `SELECT id, app_id, name FROM roles WHERE app_id = $1 AND name = $2`, appID, name)
+
+	var role entity.Role
+	err := row.Scan(&role.ID, &role.AppID, &role.Name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Role{}, fmt.Errorf("%s: %w", op, entity.ErrRoleNotFound)
+		}
+
+		return entity.Role{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return role, nil
+}
+
+func (r *AuthRepo) AssignRole(ctx context.Context, userID int, roleID int) error {
+	const op = "internal - usecase - repo_postgres - AuthRepo.AssignRole"
+
+	_, err := r.DB.ExecContext(ctx, `INSERT INTO user_roles(user_id, role_id) VALUES($1, $2) ON CONFLICT DO NOTHING`, userID, roleID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepo) UnassignRole(ctx context.Context, userID int, roleID int) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.UnassignRole"
+
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM user_roles WHERE user_id = $1 AND role_id = $2`, userID, roleID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+// GetUserAccess returns the roles of the user within the app and the union of
+// their permissions, both sorted.
+func (r *AuthRepo) GetUserAccess(ctx context.Context, userID int, appID int) (entity.Access, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.GetUserAccess"
+
+	var access entity.Access
+
+	roles, err := r.queryStrings(ctx,
+		`SELECT r.name FROM roles r JOIN user_roles ur ON ur.role_id = r.id
+		WHERE ur.user_id = $1 AND r.app_id = $2 ORDER BY r.name`,
+		userID, appID,
+	)
+	if err != nil {
+		return entity.Access{}, fmt.Errorf("%s: %w", op, err)
+	}
+	access.Roles = roles
+
+	permissions, err := r.queryStrings(ctx,
+		`SELECT DISTINCT rp.permission FROM role_permissions rp
+		JOIN roles r ON r.id = rp.role_id
+		JOIN user_roles ur ON ur.role_id = r.id
+		WHERE ur.user_id = $1 AND r.app_id = $2 ORDER BY rp.permission`,
+		userID, appID,
+	)
+	if err != nil {
+		return entity.Access{}, fmt.Errorf("%s: %w", op, err)
+	}
+	access.Permissions = permissions
+
+	return access, nil
+}
+
+func (r *AuthRepo) queryStrings(ctx context.Context, query string, args ...any) ([]string, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
@@ -0,0 +1,136 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+const signingKeyColumns = `id, kid, app_id, alg, COALESCE(secret, ''), private_key, public_key, created_at, COALESCE(retires_at, 0)`
+
+// RotateSigningKey schedules the current key of the app for retirement at
+// retiresAt and stores next as the new current key in one transaction.
+func (r *AuthRepo) RotateSigningKey(ctx context.Context, next entity.SigningKey, retiresAt time.Time) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.RotateSigningKey"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE signing_keys SET retires_at = $1 WHERE app_id = $2 AND retires_at IS NULL`,
+		retiresAt.Unix(), next.AppID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	secret := sql.NullString{String: next.Secret, Valid: next.Secret != ""}
+
+	var id int
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO signing_keys(kid, app_id, alg, secret, private_key, public_key, created_at) VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		next.KeyID, next.AppID, next.Alg, secret, next.PrivateKey, next.PublicKey, next.CreatedAt.Unix(),
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *AuthRepo) GetActiveSigningKey(ctx context.Context, appID int) (entity.SigningKey, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.GetActiveSigningKey"
+
+	key, err := scanSigningKey(r.DB.QueryRowContext(ctx,
+		`SELECT `+signingKeyColumns+` FROM signing_keys WHERE app_id = $1 AND retires_at IS NULL`, appID))
+	if err != nil {
+		return entity.SigningKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+func (r *AuthRepo) GetSigningKey(ctx context.Context, kid string) (entity.SigningKey, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.GetSigningKey"
+
+	key, err := scanSigningKey(r.DB.QueryRowContext(ctx,
+		`SELECT `+signingKeyColumns+` FROM signing_keys WHERE kid = $1`, kid))
+	if err != nil {
+		return entity.SigningKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+// GetPublicSigningKeys returns asymmetric keys that still verify tokens at now.
+func (r *AuthRepo) GetPublicSigningKeys(ctx context.Context, now time.Time) ([]entity.SigningKey, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.GetPublicSigningKeys"
+
+	rows, err := r.DB.QueryContext(ctx, `SELECT `+signingKeyColumns+` FROM signing_keys
+		WHERE public_key IS NOT NULL AND (retires_at IS NULL OR retires_at > $1) ORDER BY id`, now.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var keys []entity.SigningKey
+	for rows.Next() {
+		key, err := scanSigningKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+func (r *AuthRepo) DeleteRetiredSigningKeys(ctx context.Context, now time.Time) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.DeleteRetiredSigningKeys"
+
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM signing_keys WHERE retires_at IS NOT NULL AND retires_at <= $1`, now.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+func scanSigningKey(row scanner) (entity.SigningKey, error) {
+	var key entity.SigningKey
+	var createdAt, retiresAt int64
+	err := row.Scan(&key.ID, &key.KeyID, &key.AppID, &key.Alg, &key.Secret, &key.PrivateKey, &key.PublicKey, &createdAt, &retiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.SigningKey{}, entity.ErrSigningKeyNotFound
+		}
+
+		return entity.SigningKey{}, err
+	}
+
+	key.CreatedAt = time.Unix(createdAt, 0)
+	if retiresAt != 0 {
+		key.RetiresAt = time.Unix(retiresAt, 0)
+	}
+
+	return key, nil
+}
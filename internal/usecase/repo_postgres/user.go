@@ -0,0 +1,228 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+func (r *AuthRepo) InsertUser(ctx context.Context, email string, passHash []byte) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.InsertUser"
+
+	var id int
+	err := r.DB.QueryRowContext(ctx,
+		`INSERT INTO users(email, pass_hash) VALUES($1, $2) RETURNING id`,
+		email, passHash,
+	).Scan(&id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("%s: %w", op, entity.ErrUserExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *AuthRepo) GetUser(ctx context.Context, email string) (entity.User, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.GetUser"
+
+	row := r.DB.QueryRowContext(ctx, `SELECT id, email, pass_hash, is_admin, email_verified FROM users WHERE email = $1`, email)
+
+	var user entity.User
+	err := row.Scan(&user.ID, &user.Email, &user.PassHash, &user.IsAdmin, &user.EmailVerified)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.User{}, fmt.Errorf("%s: %w", op, entity.ErrUserNotFound)
+		}
+
+		return entity.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+func (r *AuthRepo) GetUserByID(ctx context.Context, id int) (entity.User, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.GetUserByID"
+
+	row := r.DB.QueryRowContext(ctx, `SELECT id, email, pass_hash, is_admin, email_verified FROM users WHERE id = $1`, id)
+
+	var user entity.User
+	err := row.Scan(&user.ID, &user.Email, &user.PassHash, &user.IsAdmin, &user.EmailVerified)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.User{}, fmt.Errorf("%s: %w", op, entity.ErrUserNotFound)
+		}
+
+		return entity.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+func (r *AuthRepo) IsAdmin(ctx context.Context, userID int) (bool, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.IsAdmin"
+
+	var isAdmin bool
+	err := r.DB.QueryRowContext(ctx, `SELECT is_admin FROM users WHERE id = $1`, userID).Scan(&isAdmin)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, fmt.Errorf("%s: %w", op, entity.ErrUserNotFound)
+		}
+
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return isAdmin, nil
+}
+
+func (r *AuthRepo) SetUserAdmin(ctx context.Context, userID int, isAdmin bool) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.SetUserAdmin"
+
+	res, err := r.DB.ExecContext(ctx, `UPDATE users SET is_admin = $1 WHERE id = $2`, isAdmin, userID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+func (r *AuthRepo) UpdateUserPassword(ctx context.Context, userID int, passHash []byte) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.UpdateUserPassword"
+
+	res, err := r.DB.ExecContext(ctx, `UPDATE users SET pass_hash = $1 WHERE id = $2`, passHash, userID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+// RehashUserPassword replaces the password hash of the user only while it
+// still is oldHash, so a password changed meanwhile is never overwritten.
+func (r *AuthRepo) RehashUserPassword(ctx context.Context, userID int, oldHash []byte, newHash []byte) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.RehashUserPassword"
+
+	res, err := r.DB.ExecContext(ctx, `UPDATE users SET pass_hash = $1 WHERE id = $2 AND pass_hash = $3`, newHash, userID, oldHash)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+func (r *AuthRepo) ListEmailCollisions(ctx context.Context) ([]entity.EmailCollision, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.ListEmailCollisions"
+
+	rows, err := r.DB.QueryContext(ctx, `SELECT email, user_id FROM email_collisions ORDER BY email, user_id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var collisions []entity.EmailCollision
+	for rows.Next() {
+		var email string
+		var userID int
+		if err := rows.Scan(&email, &userID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if n := len(collisions); n > 0 && collisions[n-1].Email == email {
+			collisions[n-1].UserIDs = append(collisions[n-1].UserIDs, userID)
+		} else {
+			collisions = append(collisions, entity.EmailCollision{Email: email, UserIDs: []int{userID}})
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return collisions, nil
+}
+
+// ListUnnormalizedEmails returns the id and email of the users whose email
+// has uppercase or non-ASCII characters and is not flagged as a collision.
+func (r *AuthRepo) ListUnnormalizedEmails(ctx context.Context) ([]entity.User, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.ListUnnormalizedEmails"
+
+	rows, err := r.DB.QueryContext(ctx, `SELECT id, email FROM users
+		WHERE (email <> lower(email) OR octet_length(email) <> char_length(email))
+			AND id NOT IN (SELECT user_id FROM email_collisions)
+		ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var users []entity.User
+	for rows.Next() {
+		var user entity.User
+		if err := rows.Scan(&user.ID, &user.Email); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+func (r *AuthRepo) UpdateUserEmail(ctx context.Context, userID int, email string) (int, error) {
+	const op = "internal - usecase - repo_postgres - AuthRepo.UpdateUserEmail"
+
+	res, err := r.DB.ExecContext(ctx, `UPDATE users SET email = $1 WHERE id = $2`, email, userID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("%s: %w", op, entity.ErrUserExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+// AddEmailCollision flags the user together with the account that already
+// holds email.
+func (r *AuthRepo) AddEmailCollision(ctx context.Context, email string, userID int) error {
+	const op = "internal - usecase - repo_postgres - AuthRepo.AddEmailCollision"
+
+	_, err := r.DB.ExecContext(ctx, `INSERT INTO email_collisions(email, user_id)
+		SELECT $1, id FROM users WHERE id = $2 OR email = $1
+		ON CONFLICT DO NOTHING`, email, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
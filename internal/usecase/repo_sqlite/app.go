@@ -10,10 +10,24 @@ import (
 	"github.com/mattn/go-sqlite3"
 )
 
+const (
+	_queryGetAppForUser       = `SELECT id, name, pass_hash, secret, ttl_hours, signing_alg, disabled, require_verified_email FROM apps WHERE id = ?`
+	_queryGetAppByName        = `SELECT id, name, pass_hash, secret, ttl_hours, signing_alg, disabled, require_verified_email FROM apps WHERE name = ?`
+	_queryInsertApp           = `INSERT INTO apps(name, pass_hash, secret, ttl_hours) VALUES(?, ?, ?, ?)`
+	_queryUpdateApp           = `UPDATE apps SET secret = ?, ttl_hours = ? WHERE id = ?`
+	_queryUpdateAppSigningAlg = `UPDATE apps SET signing_alg = ? WHERE id = ?`
+	_queryUpdateAppPassword   = `UPDATE apps SET pass_hash = ? WHERE id = ?`
+	_queryDeleteApp           = `DELETE FROM apps WHERE id = ?`
+	_queryListApps            = `SELECT id, name, pass_hash, secret, ttl_hours, signing_alg, disabled, require_verified_email FROM apps WHERE id > ? ORDER BY id LIMIT ?`
+	_querySetAppDisabled      = `UPDATE apps SET disabled = ? WHERE id = ?`
+	_querySetAppEmailPolicy   = `UPDATE apps SET require_verified_email = ? WHERE id = ?`
+	_queryDeleteAppRevoked    = `DELETE FROM revoked_tokens WHERE app_id = ?`
+)
+
 func (r *AuthRepo) GetAppForUser(ctx context.Context, id int) (entity.App, error) {
 	const op = "internal - usecase - repo_sqlite - AuthRepo.GetAppForUser"
 
-	stmt, err := r.DB.Prepare(`SELECT id, name, pass_hash, secret, ttl_hours FROM apps WHERE id = ?`)
+	stmt, err := r.Stmt(_queryGetAppForUser)
 	if err != nil {
 		return entity.App{}, fmt.Errorf("%s: %w", op, err)
 	}
@@ -21,7 +35,7 @@ func (r *AuthRepo) GetAppForUser(ctx context.Context, id int) (entity.App, error
 	row := stmt.QueryRowContext(ctx, id)
 
 	var app entity.App
-	err = row.Scan(&app.ID, &app.Name, &app.PassHash, &app.Secret, &app.TTLHours)
+	err = row.Scan(&app.ID, &app.Name, &app.PassHash, &app.Secret, &app.TTLHours, &app.SigningAlg, &app.Disabled, &app.RequireVerifiedEmail)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return entity.App{}, fmt.Errorf("%s: %w", op, entity.ErrAppNotFound)
@@ -37,7 +51,7 @@ func (r *AuthRepo) GetAppForUser(ctx context.Context, id int) (entity.App, error
 func (r *AuthRepo) GetAppByName(ctx context.Context, name string) (entity.App, error) {
 	const op = "internal - usecase - repo_sqlite - AuthRepo.GetAppByName"
 
-	stmt, err := r.DB.Prepare(`SELECT id, name, pass_hash, secret, ttl_hours FROM apps WHERE name = ?`)
+	stmt, err := r.Stmt(_queryGetAppByName)
 	if err != nil {
 		return entity.App{}, fmt.Errorf("%s: %w", op, err)
 	}
@@ -45,7 +59,7 @@ func (r *AuthRepo) GetAppByName(ctx context.Context, name string) (entity.App, e
 	row := stmt.QueryRowContext(ctx, name)
 
 	var app entity.App
-	err = row.Scan(&app.ID, &app.Name, &app.PassHash, &app.Secret, &app.TTLHours)
+	err = row.Scan(&app.ID, &app.Name, &app.PassHash, &app.Secret, &app.TTLHours, &app.SigningAlg, &app.Disabled, &app.RequireVerifiedEmail)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return entity.App{}, fmt.Errorf("%s: %w", op, entity.ErrAppNotFound)
@@ -61,7 +75,7 @@ func (r *AuthRepo) GetAppByName(ctx context.Context, name string) (entity.App, e
 func (r *AuthRepo) InsertApp(ctx context.Context, name string, passHash []byte, secret string, ttlHour int) (int, error) {
 	const op = "internal - usecase - repo_sqlite - AuthRepo.InsertApp"
 
-	stmt, err := r.DB.Prepare(`INSERT INTO apps(name, pass_hash, secret, ttl_hours) VALUES(?, ?, ?, ?)`)
+	stmt, err := r.Stmt(_queryInsertApp)
 	if err != nil {
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
@@ -87,7 +101,7 @@ func (r *AuthRepo) InsertApp(ctx context.Context, name string, passHash []byte,
 func (r *AuthRepo) UpdateApp(ctx context.Context, id_ int, secret string, ttlHour int) (int, error) {
 	const op = "internal - usecase - repo_sqlite - AuthRepo.UpdateApp"
 
-	stmt, err := r.DB.Prepare(`UPDATE apps SET secret = ?, ttl_hours = ? WHERE id = ?`)
+	stmt, err := r.Stmt(_queryUpdateApp)
 	if err != nil {
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
@@ -105,3 +119,158 @@ func (r *AuthRepo) UpdateApp(ctx context.Context, id_ int, secret string, ttlHou
 	return int(id), nil
 }
 
+func (r *AuthRepo) UpdateAppSigningAlg(ctx context.Context, id_ int, alg string) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.UpdateAppSigningAlg"
+
+	stmt, err := r.Stmt(_queryUpdateAppSigningAlg)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, alg, id_)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(id), nil
+}
+
+func (r *AuthRepo) UpdateAppPassword(ctx context.Context, id_ int, passHash []byte) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.UpdateAppPassword"
+
+	stmt, err := r.Stmt(_queryUpdateAppPassword)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, passHash, id_)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(id), nil
+}
+
+// DeleteApp removes the app with its revoked tokens. Signing keys, refresh
+// tokens and roles go with it through the foreign keys. Auth events are kept
+// as the audit trail.
+func (r *AuthRepo) DeleteApp(ctx context.Context, id_ int) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.DeleteApp"
+
+	deleteRevoked, err := r.Stmt(_queryDeleteAppRevoked)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	deleteApp, err := r.Stmt(_queryDeleteApp)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.StmtContext(ctx, deleteRevoked).ExecContext(ctx, id_); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.StmtContext(ctx, deleteApp).ExecContext(ctx, id_)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(id), nil
+}
+
+func (r *AuthRepo) SetAppDisabled(ctx context.Context, id_ int, disabled bool) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.SetAppDisabled"
+
+	stmt, err := r.Stmt(_querySetAppDisabled)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, disabled, id_)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(id), nil
+}
+
+// ListApps returns up to limit apps with ids above afterID in id order.
+func (r *AuthRepo) ListApps(ctx context.Context, afterID int, limit int) ([]entity.App, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.ListApps"
+
+	stmt, err := r.Stmt(_queryListApps)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var apps []entity.App
+	for rows.Next() {
+		var app entity.App
+		if err := rows.Scan(&app.ID, &app.Name, &app.PassHash, &app.Secret, &app.TTLHours, &app.SigningAlg, &app.Disabled, &app.RequireVerifiedEmail); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		apps = append(apps, app)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return apps, nil
+}
+
+func (r *AuthRepo) SetAppEmailPolicy(ctx context.Context, id_ int, requireVerified bool) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.SetAppEmailPolicy"
+
+	stmt, err := r.Stmt(_querySetAppEmailPolicy)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, requireVerified, id_)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
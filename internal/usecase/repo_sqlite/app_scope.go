@@ -0,0 +1,77 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	_queryGetAppScopes    = `SELECT scope FROM app_scopes WHERE app_id = ? ORDER BY scope`
+	_queryInsertAppScope  = `INSERT INTO app_scopes(app_id, scope) VALUES(?, ?) ON CONFLICT DO NOTHING`
+	_queryDeleteAppScopes = `DELETE FROM app_scopes WHERE app_id = ?`
+)
+
+// SetAppScopes replaces the scopes of the app with scopes in one transaction.
+func (r *AuthRepo) SetAppScopes(ctx context.Context, appID int, scopes []string) error {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.SetAppScopes"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	deleteScopes, err := r.Stmt(_queryDeleteAppScopes)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	insert, err := r.Stmt(_queryInsertAppScope)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.StmtContext(ctx, deleteScopes).ExecContext(ctx, appID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, scope := range scopes {
+		if _, err := tx.StmtContext(ctx, insert).ExecContext(ctx, appID, scope); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepo) GetAppScopes(ctx context.Context, appID int) ([]string, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.GetAppScopes"
+
+	stmt, err := r.Stmt(_queryGetAppScopes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var scopes []string
+	for rows.Next() {
+		var scope string
+		if err := rows.Scan(&scope); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		scopes = append(scopes, scope)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return scopes, nil
+}
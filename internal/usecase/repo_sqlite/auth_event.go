@@ -0,0 +1,113 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+const (
+	_queryInsertAuthEvent = `INSERT INTO auth_events(type, created_at, app_id, user_id, email, peer_addr, user_agent, details) VALUES(?, ?, ?, ?, ?, ?, ?, ?)`
+)
+
+func (r *AuthRepo) InsertAuthEvent(ctx context.Context, event entity.AuthEvent) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.InsertAuthEvent"
+
+	stmt, err := r.Stmt(_queryInsertAuthEvent)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx,
+		event.Type, event.CreatedAt.Unix(), nullInt(event.AppID), nullInt(event.UserID),
+		event.Email, event.PeerAddr, event.UserAgent, event.Details,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(id), nil
+}
+
+// ListAuthEvents returns events matching filter, newest first.
+func (r *AuthRepo) ListAuthEvents(ctx context.Context, filter entity.AuthEventFilter) ([]entity.AuthEvent, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.ListAuthEvents"
+
+	var where []string
+	var args []any
+
+	if filter.UserID != 0 {
+		where = append(where, "user_id = ?")
+		args = append(args, filter.UserID)
+	}
+	if filter.AppID != 0 {
+		where = append(where, "app_id = ?")
+		args = append(args, filter.AppID)
+	}
+	if len(filter.Types) > 0 {
+		where = append(where, "type IN (?"+strings.Repeat(", ?", len(filter.Types)-1)+")")
+		for _, t := range filter.Types {
+			args = append(args, t)
+		}
+	}
+	if filter.Email != "" {
+		where = append(where, "email = ?")
+		args = append(args, filter.Email)
+	}
+	if !filter.Since.IsZero() {
+		where = append(where, "created_at >= ?")
+		args = append(args, filter.Since.Unix())
+	}
+	if !filter.Until.IsZero() {
+		where = append(where, "created_at < ?")
+		args = append(args, filter.Until.Unix())
+	}
+	if filter.BeforeID != 0 {
+		where = append(where, "id < ?")
+		args = append(args, filter.BeforeID)
+	}
+
+	query := `SELECT id, type, created_at, COALESCE(app_id, 0), COALESCE(user_id, 0), email, peer_addr, user_agent, details FROM auth_events`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, filter.Limit)
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var events []entity.AuthEvent
+	for rows.Next() {
+		var event entity.AuthEvent
+		var createdAt int64
+		err := rows.Scan(&event.ID, &event.Type, &createdAt, &event.AppID, &event.UserID,
+			&event.Email, &event.PeerAddr, &event.UserAgent, &event.Details)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		event.CreatedAt = time.Unix(createdAt, 0)
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
+
+func nullInt(v int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(v), Valid: v != 0}
+}
@@ -0,0 +1,140 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	_queryGetAppClaims    = `SELECT name, template FROM app_claims WHERE app_id = ?`
+	_queryInsertAppClaim  = `INSERT INTO app_claims(app_id, name, template) VALUES(?, ?, ?)`
+	_queryDeleteAppClaims = `DELETE FROM app_claims WHERE app_id = ?`
+
+	_querySetUserAttribute = `INSERT INTO user_attributes(user_id, key, value) VALUES(?, ?, ?)
+		ON CONFLICT(user_id, key) DO UPDATE SET value = excluded.value`
+	_queryDeleteUserAttribute = `DELETE FROM user_attributes WHERE user_id = ? AND key = ?`
+	_queryGetUserAttributes   = `SELECT key, value FROM user_attributes WHERE user_id = ?`
+)
+
+// SetAppClaims replaces the claim templates of the app with claims in one
+// transaction.
+func (r *AuthRepo) SetAppClaims(ctx context.Context, appID int, claims map[string]string) error {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.SetAppClaims"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	deleteClaims, err := r.Stmt(_queryDeleteAppClaims)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	insert, err := r.Stmt(_queryInsertAppClaim)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.StmtContext(ctx, deleteClaims).ExecContext(ctx, appID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for name, template := range claims {
+		if _, err := tx.StmtContext(ctx, insert).ExecContext(ctx, appID, name, template); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepo) GetAppClaims(ctx context.Context, appID int) (map[string]string, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.GetAppClaims"
+
+	values, err := r.queryMap(ctx, _queryGetAppClaims, appID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return values, nil
+}
+
+func (r *AuthRepo) SetUserAttribute(ctx context.Context, userID int, key string, value string) error {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.SetUserAttribute"
+
+	stmt, err := r.Stmt(_querySetUserAttribute)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := stmt.ExecContext(ctx, userID, key, value); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepo) DeleteUserAttribute(ctx context.Context, userID int, key string) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.DeleteUserAttribute"
+
+	stmt, err := r.Stmt(_queryDeleteUserAttribute)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, userID, key)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+func (r *AuthRepo) GetUserAttributes(ctx context.Context, userID int) (map[string]string, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.GetUserAttributes"
+
+	values, err := r.queryMap(ctx, _queryGetUserAttributes, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return values, nil
+}
+
+// queryMap runs a prepared query selecting key and value columns.
+func (r *AuthRepo) queryMap(ctx context.Context, query string, args ...any) (map[string]string, error) {
+	stmt, err := r.Stmt(query)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
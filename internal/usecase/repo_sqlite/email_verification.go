@@ -0,0 +1,109 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+const (
+	_queryInsertEmailVerification         = `INSERT INTO email_verifications(token_hash, user_id, created_at, expires_at) VALUES(?, ?, ?, ?)`
+	_queryConsumeEmailVerification        = `DELETE FROM email_verifications WHERE token_hash = ? AND expires_at > ? RETURNING user_id`
+	_querySetEmailVerified                = `UPDATE users SET email_verified = TRUE WHERE id = ?`
+	_queryDeleteUserEmailVerifications    = `DELETE FROM email_verifications WHERE user_id = ?`
+	_queryDeleteExpiredEmailVerifications = `DELETE FROM email_verifications WHERE expires_at <= ?`
+)
+
+func (r *AuthRepo) InsertEmailVerification(ctx context.Context, verification entity.EmailVerification) error {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.InsertEmailVerification"
+
+	stmt, err := r.Stmt(_queryInsertEmailVerification)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx,
+		verification.TokenHash, verification.UserID, verification.CreatedAt.Unix(), verification.ExpiresAt.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// VerifyEmail consumes the verification token with tokenHash and marks the
+// email of its user as verified in one transaction. Other outstanding tokens
+// of the user are dropped. It fails with entity.ErrEmailVerificationNotFound
+// if the token is unknown, already used or expired at now.
+func (r *AuthRepo) VerifyEmail(ctx context.Context, tokenHash []byte, now time.Time) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.VerifyEmail"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	consume, err := r.Stmt(_queryConsumeEmailVerification)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	verify, err := r.Stmt(_querySetEmailVerified)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	deleteVerifications, err := r.Stmt(_queryDeleteUserEmailVerifications)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var userID int
+	err = tx.StmtContext(ctx, consume).QueryRowContext(ctx, tokenHash, now.Unix()).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, entity.ErrEmailVerificationNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.StmtContext(ctx, verify).ExecContext(ctx, userID); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.StmtContext(ctx, deleteVerifications).ExecContext(ctx, userID); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userID, nil
+}
+
+func (r *AuthRepo) DeleteExpiredEmailVerifications(ctx context.Context, now time.Time) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.DeleteExpiredEmailVerifications"
+
+	stmt, err := r.Stmt(_queryDeleteExpiredEmailVerifications)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, now.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
@@ -0,0 +1,128 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+const (
+	_queryGetLoginFailure    = `SELECT failures, last_failed_at, locked_until FROM login_failures WHERE key = ?`
+	_queryRecordLoginFailure = `INSERT INTO login_failures(key, failures, last_failed_at) VALUES(?, 1, ?)
+		ON CONFLICT(key) DO UPDATE SET
+			failures = CASE WHEN last_failed_at < ? THEN 1 ELSE failures + 1 END,
+			last_failed_at = excluded.last_failed_at
+		RETURNING failures`
+	_queryLockLogin                = `UPDATE login_failures SET locked_until = ? WHERE key = ?`
+	_queryDeleteLoginFailure       = `DELETE FROM login_failures WHERE key = ?`
+	_queryDeleteStaleLoginFailures = `DELETE FROM login_failures WHERE last_failed_at < ? AND locked_until <= ?`
+)
+
+// GetLoginFailure returns the failures recorded for key. A key without
+// failures yields a zero LoginFailure.
+func (r *AuthRepo) GetLoginFailure(ctx context.Context, key string) (entity.LoginFailure, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.GetLoginFailure"
+
+	stmt, err := r.Stmt(_queryGetLoginFailure)
+	if err != nil {
+		return entity.LoginFailure{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	failure := entity.LoginFailure{Key: key}
+	var lastFailedAt, lockedUntil int64
+
+	err = stmt.QueryRowContext(ctx, key).Scan(&failure.Failures, &lastFailedAt, &lockedUntil)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return failure, nil
+		}
+
+		return entity.LoginFailure{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	failure.LastFailedAt = time.UnixMilli(lastFailedAt)
+	failure.LockedUntil = time.UnixMilli(lockedUntil)
+
+	return failure, nil
+}
+
+// RecordLoginFailure increments the failures of key, starting over when the
+// previous failure happened before windowStart, and returns the new count.
+func (r *AuthRepo) RecordLoginFailure(ctx context.Context, key string, now time.Time, windowStart time.Time) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.RecordLoginFailure"
+
+	stmt, err := r.Stmt(_queryRecordLoginFailure)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var failures int
+	if err := stmt.QueryRowContext(ctx, key, now.UnixMilli(), windowStart.UnixMilli()).Scan(&failures); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return failures, nil
+}
+
+func (r *AuthRepo) LockLogin(ctx context.Context, key string, until time.Time) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.LockLogin"
+
+	stmt, err := r.Stmt(_queryLockLogin)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, until.UnixMilli(), key)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+func (r *AuthRepo) DeleteLoginFailure(ctx context.Context, key string) error {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.DeleteLoginFailure"
+
+	stmt, err := r.Stmt(_queryDeleteLoginFailure)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := stmt.ExecContext(ctx, key); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteStaleLoginFailures removes unlocked records whose last failure happened
+// before windowStart.
+func (r *AuthRepo) DeleteStaleLoginFailures(ctx context.Context, now time.Time, windowStart time.Time) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.DeleteStaleLoginFailures"
+
+	stmt, err := r.Stmt(_queryDeleteStaleLoginFailures)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, windowStart.UnixMilli(), now.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
@@ -0,0 +1,322 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+const (
+	_querySaveTOTP = `INSERT INTO user_totp(user_id, secret, confirmed, last_step, created_at) VALUES(?, ?, FALSE, 0, ?)
+		ON CONFLICT(user_id) DO UPDATE SET secret = excluded.secret, last_step = 0, created_at = excluded.created_at
+		WHERE user_totp.confirmed = FALSE`
+	_queryGetTOTP     = `SELECT user_id, secret, confirmed, last_step, created_at FROM user_totp WHERE user_id = ?`
+	_queryConfirmTOTP = `UPDATE user_totp SET confirmed = TRUE, last_step = ? WHERE user_id = ? AND confirmed = FALSE`
+	_queryUseTOTPStep = `UPDATE user_totp SET last_step = ? WHERE user_id = ? AND confirmed = TRUE AND last_step < ?`
+	_queryDeleteTOTP  = `DELETE FROM user_totp WHERE user_id = ?`
+
+	_queryInsertRecoveryCode      = `INSERT INTO recovery_codes(code_hash, user_id) VALUES(?, ?)`
+	_queryUseRecoveryCode         = `DELETE FROM recovery_codes WHERE code_hash = ? AND user_id = ?`
+	_queryDeleteUserRecoveryCodes = `DELETE FROM recovery_codes WHERE user_id = ?`
+
+	_queryInsertMFAChallenge = `INSERT INTO mfa_challenges(token_hash, user_id, app_id, with_refresh, created_at, expires_at)
+		VALUES(?, ?, ?, ?, ?, ?)`
+	_queryGetMFAChallenge = `SELECT token_hash, user_id, app_id, with_refresh, created_at, expires_at FROM mfa_challenges
+		WHERE token_hash = ? AND expires_at > ?`
+	_queryDeleteMFAChallenge         = `DELETE FROM mfa_challenges WHERE token_hash = ?`
+	_queryDeleteUserMFAChallenges    = `DELETE FROM mfa_challenges WHERE user_id = ?`
+	_queryDeleteExpiredMFAChallenges = `DELETE FROM mfa_challenges WHERE expires_at <= ?`
+)
+
+// SaveTOTP stores an unconfirmed enrollment of the user, replacing a previous
+// unconfirmed one. It returns 0 and stores nothing if the user already has
+// TOTP enabled.
+func (r *AuthRepo) SaveTOTP(ctx context.Context, totp entity.TOTP) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.SaveTOTP"
+
+	stmt, err := r.Stmt(_querySaveTOTP)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, totp.UserID, totp.Secret, totp.CreatedAt.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+func (r *AuthRepo) GetTOTP(ctx context.Context, userID int) (entity.TOTP, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.GetTOTP"
+
+	stmt, err := r.Stmt(_queryGetTOTP)
+	if err != nil {
+		return entity.TOTP{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var totp entity.TOTP
+	var createdAt int64
+	err = stmt.QueryRowContext(ctx, userID).Scan(&totp.UserID, &totp.Secret, &totp.Confirmed, &totp.LastStep, &createdAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.TOTP{}, fmt.Errorf("%s: %w", op, entity.ErrTOTPNotFound)
+		}
+
+		return entity.TOTP{}, fmt.Errorf("%s: %w", op, err)
+	}
+	totp.CreatedAt = time.Unix(createdAt, 0)
+
+	return totp, nil
+}
+
+// ConfirmTOTP enables the pending enrollment of the user, accepting step as
+// its first code, and replaces the user's recovery codes with codeHashes in
+// one transaction. It returns 0 and changes nothing if there is no pending
+// enrollment.
+func (r *AuthRepo) ConfirmTOTP(ctx context.Context, userID int, step int64, codeHashes [][]byte) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.ConfirmTOTP"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	confirm, err := r.Stmt(_queryConfirmTOTP)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	deleteCodes, err := r.Stmt(_queryDeleteUserRecoveryCodes)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	insertCode, err := r.Stmt(_queryInsertRecoveryCode)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.StmtContext(ctx, confirm).ExecContext(ctx, step, userID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		return 0, nil
+	}
+
+	if _, err := tx.StmtContext(ctx, deleteCodes).ExecContext(ctx, userID); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	insert := tx.StmtContext(ctx, insertCode)
+	for _, hash := range codeHashes {
+		if _, err := insert.ExecContext(ctx, hash, userID); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+// UseTOTPStep records step as the last accepted code of the user. It returns
+// 0 if TOTP is not enabled or a code of step or a later one was already
+// accepted.
+func (r *AuthRepo) UseTOTPStep(ctx context.Context, userID int, step int64) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.UseTOTPStep"
+
+	stmt, err := r.Stmt(_queryUseTOTPStep)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, step, userID, step)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+// UseRecoveryCode consumes the recovery code of the user with codeHash. It
+// returns 0 if there is no such code.
+func (r *AuthRepo) UseRecoveryCode(ctx context.Context, userID int, codeHash []byte) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.UseRecoveryCode"
+
+	stmt, err := r.Stmt(_queryUseRecoveryCode)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, codeHash, userID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+// DeleteTOTP removes the enrollment of the user together with its recovery
+// codes and outstanding challenges.
+func (r *AuthRepo) DeleteTOTP(ctx context.Context, userID int) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.DeleteTOTP"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	deleteTOTP, err := r.Stmt(_queryDeleteTOTP)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	deleteCodes, err := r.Stmt(_queryDeleteUserRecoveryCodes)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	deleteChallenges, err := r.Stmt(_queryDeleteUserMFAChallenges)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.StmtContext(ctx, deleteTOTP).ExecContext(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.StmtContext(ctx, deleteCodes).ExecContext(ctx, userID); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.StmtContext(ctx, deleteChallenges).ExecContext(ctx, userID); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+func (r *AuthRepo) InsertMFAChallenge(ctx context.Context, challenge entity.MFAChallenge) error {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.InsertMFAChallenge"
+
+	stmt, err := r.Stmt(_queryInsertMFAChallenge)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx,
+		challenge.TokenHash, challenge.UserID, challenge.AppID, challenge.WithRefresh,
+		challenge.CreatedAt.Unix(), challenge.ExpiresAt.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetMFAChallenge fails with entity.ErrMFAChallengeNotFound if the challenge
+// with tokenHash is unknown, already used or expired at now.
+func (r *AuthRepo) GetMFAChallenge(ctx context.Context, tokenHash []byte, now time.Time) (entity.MFAChallenge, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.GetMFAChallenge"
+
+	stmt, err := r.Stmt(_queryGetMFAChallenge)
+	if err != nil {
+		return entity.MFAChallenge{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var challenge entity.MFAChallenge
+	var createdAt, expiresAt int64
+	err = stmt.QueryRowContext(ctx, tokenHash, now.Unix()).Scan(
+		&challenge.TokenHash, &challenge.UserID, &challenge.AppID, &challenge.WithRefresh, &createdAt, &expiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.MFAChallenge{}, fmt.Errorf("%s: %w", op, entity.ErrMFAChallengeNotFound)
+		}
+
+		return entity.MFAChallenge{}, fmt.Errorf("%s: %w", op, err)
+	}
+	challenge.CreatedAt = time.Unix(createdAt, 0)
+	challenge.ExpiresAt = time.Unix(expiresAt, 0)
+
+	return challenge, nil
+}
+
+func (r *AuthRepo) DeleteMFAChallenge(ctx context.Context, tokenHash []byte) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.DeleteMFAChallenge"
+
+	stmt, err := r.Stmt(_queryDeleteMFAChallenge)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, tokenHash)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+func (r *AuthRepo) DeleteExpiredMFAChallenges(ctx context.Context, now time.Time) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.DeleteExpiredMFAChallenges"
+
+	stmt, err := r.Stmt(_queryDeleteExpiredMFAChallenges)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, now.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
@@ -0,0 +1,214 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+const (
+	_queryGetAppRedirectURIs    = `SELECT uri FROM app_redirect_uris WHERE app_id = ? ORDER BY uri`
+	_queryInsertAppRedirectURI  = `INSERT INTO app_redirect_uris(app_id, uri) VALUES(?, ?) ON CONFLICT DO NOTHING`
+	_queryDeleteAppRedirectURIs = `DELETE FROM app_redirect_uris WHERE app_id = ?`
+
+	_queryInsertAuthorizationCode = `INSERT INTO authorization_codes(code_hash, app_id, user_id, redirect_uri, code_challenge, scope, nonce, created_at, expires_at)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_queryConsumeAuthorizationCode = `UPDATE authorization_codes SET used = TRUE WHERE code_hash = ? AND used = FALSE AND expires_at > ?
+		RETURNING code_hash, app_id, user_id, redirect_uri, code_challenge, scope, nonce, created_at, expires_at`
+	_queryGetUsedAuthorizationCode = `SELECT code_hash, app_id, user_id, redirect_uri, code_challenge, scope, nonce, created_at, expires_at,
+		token_id, token_expires_at, family_id FROM authorization_codes WHERE code_hash = ? AND used = TRUE AND expires_at > ?`
+	_querySetAuthorizationCodeTokens = `UPDATE authorization_codes SET token_id = ?, token_expires_at = ?, family_id = ?, expires_at = ?
+		WHERE code_hash = ?`
+	_queryDeleteExpiredAuthorizationCodes = `DELETE FROM authorization_codes WHERE expires_at <= ?`
+)
+
+// SetAppRedirectURIs replaces the redirect uris of the app with uris in one
+// transaction.
+func (r *AuthRepo) SetAppRedirectURIs(ctx context.Context, appID int, uris []string) error {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.SetAppRedirectURIs"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	deleteURIs, err := r.Stmt(_queryDeleteAppRedirectURIs)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	insert, err := r.Stmt(_queryInsertAppRedirectURI)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.StmtContext(ctx, deleteURIs).ExecContext(ctx, appID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, uri := range uris {
+		if _, err := tx.StmtContext(ctx, insert).ExecContext(ctx, appID, uri); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepo) GetAppRedirectURIs(ctx context.Context, appID int) ([]string, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.GetAppRedirectURIs"
+
+	stmt, err := r.Stmt(_queryGetAppRedirectURIs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var uris []string
+	for rows.Next() {
+		var uri string
+		if err := rows.Scan(&uri); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		uris = append(uris, uri)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return uris, nil
+}
+
+func (r *AuthRepo) InsertAuthorizationCode(ctx context.Context, code entity.AuthorizationCode) error {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.InsertAuthorizationCode"
+
+	stmt, err := r.Stmt(_queryInsertAuthorizationCode)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx,
+		code.CodeHash, code.AppID, code.UserID, code.RedirectURI, code.CodeChallenge, code.Scope, code.Nonce,
+		code.CreatedAt.Unix(), code.ExpiresAt.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ConsumeAuthorizationCode marks the code with codeHash used and returns it.
+// A code that was used before is returned unchanged with Used set. It fails
+// with entity.ErrAuthorizationCodeNotFound if the code is unknown or expired
+// at now.
+func (r *AuthRepo) ConsumeAuthorizationCode(ctx context.Context, codeHash []byte, now time.Time) (entity.AuthorizationCode, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.ConsumeAuthorizationCode"
+
+	stmt, err := r.Stmt(_queryConsumeAuthorizationCode)
+	if err != nil {
+		return entity.AuthorizationCode{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var code entity.AuthorizationCode
+	var createdAt, expiresAt int64
+	err = stmt.QueryRowContext(ctx, codeHash, now.Unix()).Scan(
+		&code.CodeHash, &code.AppID, &code.UserID, &code.RedirectURI, &code.CodeChallenge, &code.Scope, &code.Nonce,
+		&createdAt, &expiresAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		used, err := r.getUsedAuthorizationCode(ctx, codeHash, now)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return entity.AuthorizationCode{}, fmt.Errorf("%s: %w", op, entity.ErrAuthorizationCodeNotFound)
+			}
+
+			return entity.AuthorizationCode{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return used, nil
+	}
+	if err != nil {
+		return entity.AuthorizationCode{}, fmt.Errorf("%s: %w", op, err)
+	}
+	code.CreatedAt = time.Unix(createdAt, 0)
+	code.ExpiresAt = time.Unix(expiresAt, 0)
+
+	return code, nil
+}
+
+func (r *AuthRepo) getUsedAuthorizationCode(ctx context.Context, codeHash []byte, now time.Time) (entity.AuthorizationCode, error) {
+	stmt, err := r.Stmt(_queryGetUsedAuthorizationCode)
+	if err != nil {
+		return entity.AuthorizationCode{}, err
+	}
+
+	code := entity.AuthorizationCode{Used: true}
+	var createdAt, expiresAt, tokenExpiresAt int64
+	err = stmt.QueryRowContext(ctx, codeHash, now.Unix()).Scan(
+		&code.CodeHash, &code.AppID, &code.UserID, &code.RedirectURI, &code.CodeChallenge, &code.Scope, &code.Nonce,
+		&createdAt, &expiresAt, &code.TokenID, &tokenExpiresAt, &code.FamilyID,
+	)
+	if err != nil {
+		return entity.AuthorizationCode{}, err
+	}
+	code.CreatedAt = time.Unix(createdAt, 0)
+	code.ExpiresAt = time.Unix(expiresAt, 0)
+	code.TokenExpiresAt = time.Unix(tokenExpiresAt, 0)
+
+	return code, nil
+}
+
+// SetAuthorizationCodeTokens records the tokens issued for the used code
+// code.CodeHash and keeps the code until code.ExpiresAt.
+func (r *AuthRepo) SetAuthorizationCodeTokens(ctx context.Context, code entity.AuthorizationCode) error {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.SetAuthorizationCodeTokens"
+
+	stmt, err := r.Stmt(_querySetAuthorizationCodeTokens)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx,
+		code.TokenID, code.TokenExpiresAt.Unix(), code.FamilyID, code.ExpiresAt.Unix(), code.CodeHash,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepo) DeleteExpiredAuthorizationCodes(ctx context.Context, now time.Time) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.DeleteExpiredAuthorizationCodes"
+
+	stmt, err := r.Stmt(_queryDeleteExpiredAuthorizationCodes)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, now.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
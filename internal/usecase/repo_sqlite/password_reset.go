@@ -0,0 +1,106 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+const (
+	_queryInsertPasswordReset         = `INSERT INTO password_resets(token_hash, user_id, created_at, expires_at) VALUES(?, ?, ?, ?)`
+	_queryConsumePasswordReset        = `DELETE FROM password_resets WHERE token_hash = ? AND expires_at > ? RETURNING user_id`
+	_queryDeleteUserPasswordResets    = `DELETE FROM password_resets WHERE user_id = ?`
+	_queryDeleteExpiredPasswordResets = `DELETE FROM password_resets WHERE expires_at <= ?`
+)
+
+func (r *AuthRepo) InsertPasswordReset(ctx context.Context, reset entity.PasswordReset) error {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.InsertPasswordReset"
+
+	stmt, err := r.Stmt(_queryInsertPasswordReset)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx, reset.TokenHash, reset.UserID, reset.CreatedAt.Unix(), reset.ExpiresAt.Unix())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ResetPassword consumes the reset token with tokenHash and stores passHash for
+// its user in one transaction. All other outstanding resets of the user are
+// dropped as well. It fails with entity.ErrPasswordResetNotFound if the token
+// is unknown, already used or expired at now.
+func (r *AuthRepo) ResetPassword(ctx context.Context, tokenHash []byte, now time.Time, passHash []byte) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.ResetPassword"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	consume, err := r.Stmt(_queryConsumePasswordReset)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	update, err := r.Stmt(_queryUpdateUserPassword)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	deleteResets, err := r.Stmt(_queryDeleteUserPasswordResets)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var userID int
+	err = tx.StmtContext(ctx, consume).QueryRowContext(ctx, tokenHash, now.Unix()).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, entity.ErrPasswordResetNotFound)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.StmtContext(ctx, update).ExecContext(ctx, passHash, userID); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.StmtContext(ctx, deleteResets).ExecContext(ctx, userID); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userID, nil
+}
+
+func (r *AuthRepo) DeleteExpiredPasswordResets(ctx context.Context, now time.Time) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.DeleteExpiredPasswordResets"
+
+	stmt, err := r.Stmt(_queryDeleteExpiredPasswordResets)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, now.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
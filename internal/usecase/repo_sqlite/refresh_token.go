@@ -0,0 +1,160 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+const (
+	_queryRotateRefreshToken       = `UPDATE refresh_tokens SET rotated_at = ? WHERE id = ? AND rotated_at IS NULL AND revoked_at IS NULL`
+	_queryInsertRefreshToken       = `INSERT INTO refresh_tokens(token_hash, family_id, user_id, app_id, expires_at) VALUES(?, ?, ?, ?, ?)`
+	_queryGetRefreshToken          = `SELECT id, token_hash, family_id, user_id, app_id, expires_at, rotated_at IS NOT NULL, revoked_at IS NOT NULL FROM refresh_tokens WHERE token_hash = ?`
+	_queryRevokeRefreshTokenFamily = `UPDATE refresh_tokens SET revoked_at = ? WHERE family_id = ? AND revoked_at IS NULL`
+	_queryRevokeUserRefreshTokens  = `UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`
+)
+
+func (r *AuthRepo) InsertRefreshToken(ctx context.Context, token entity.RefreshToken) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.InsertRefreshToken"
+
+	stmt, err := r.Stmt(_queryInsertRefreshToken)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, token.TokenHash, token.FamilyID, token.UserID, token.AppID, token.ExpiresAt.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(id), nil
+}
+
+func (r *AuthRepo) GetRefreshToken(ctx context.Context, tokenHash []byte) (entity.RefreshToken, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.GetRefreshToken"
+
+	stmt, err := r.Stmt(_queryGetRefreshToken)
+	if err != nil {
+		return entity.RefreshToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	row := stmt.QueryRowContext(ctx, tokenHash)
+
+	var token entity.RefreshToken
+	var expiresAt int64
+	err = row.Scan(&token.ID, &token.TokenHash, &token.FamilyID, &token.UserID, &token.AppID, &expiresAt, &token.Rotated, &token.Revoked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.RefreshToken{}, fmt.Errorf("%s: %w", op, entity.ErrRefreshTokenNotFound)
+		}
+
+		return entity.RefreshToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+	token.ExpiresAt = time.Unix(expiresAt, 0)
+
+	return token, nil
+}
+
+// RotateRefreshToken marks the token with id_ as used and stores its successor
+// in one transaction. It fails with entity.ErrRefreshTokenReused if the token
+// has already been rotated or revoked concurrently.
+func (r *AuthRepo) RotateRefreshToken(ctx context.Context, id_ int, next entity.RefreshToken) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.RotateRefreshToken"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	rotate, err := r.Stmt(_queryRotateRefreshToken)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	insert, err := r.Stmt(_queryInsertRefreshToken)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.StmtContext(ctx, rotate).ExecContext(ctx, time.Now().Unix(), id_)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return 0, fmt.Errorf("%s: %w", op, entity.ErrRefreshTokenReused)
+	}
+
+	res, err = tx.StmtContext(ctx, insert).ExecContext(ctx,
+		next.TokenHash, next.FamilyID, next.UserID, next.AppID, next.ExpiresAt.Unix(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(id), nil
+}
+
+func (r *AuthRepo) RevokeRefreshTokenFamily(ctx context.Context, familyID string) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.RevokeRefreshTokenFamily"
+
+	stmt, err := r.Stmt(_queryRevokeRefreshTokenFamily)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, time.Now().Unix(), familyID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(id), nil
+}
+
+func (r *AuthRepo) RevokeUserRefreshTokens(ctx context.Context, userID int) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.RevokeUserRefreshTokens"
+
+	stmt, err := r.Stmt(_queryRevokeUserRefreshTokens)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, time.Now().Unix(), userID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
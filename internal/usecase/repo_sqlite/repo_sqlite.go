@@ -1,13 +1,54 @@
 package repo
 
 import (
+	"fmt"
+
 	"github.com/1kovalevskiy/sso/pkg/sqlite"
 )
 
+// _queries are prepared once when the repo is created, so a broken statement
+// fails the startup instead of the first request that needs it.
+var _queries = []string{
+	_queryInsertUser, _queryGetUser, _queryGetUserByID, _queryIsAdmin, _querySetUserAdmin, _queryUpdateUserPassword,
+	_queryRehashUserPassword, _queryListEmailCollisions, _queryListUnnormalizedEmails, _queryUpdateUserEmail,
+	_queryAddEmailCollision,
+	_queryGetAppForUser, _queryGetAppByName, _queryInsertApp, _queryUpdateApp, _queryUpdateAppSigningAlg,
+	_queryUpdateAppPassword, _queryDeleteApp, _queryDeleteAppRevoked, _queryListApps, _querySetAppDisabled,
+	_querySetAppEmailPolicy,
+	_queryInsertRefreshToken, _queryGetRefreshToken, _queryRotateRefreshToken, _queryRevokeRefreshTokenFamily,
+	_queryRevokeUserRefreshTokens,
+	_queryRetireSigningKeys, _queryInsertSigningKey, _queryGetActiveSigningKey, _queryGetSigningKey,
+	_queryGetPublicSigningKeys, _queryDeleteRetiredSigningKeys,
+	_queryInsertRevokedToken, _queryIsTokenRevoked, _queryDeleteExpiredRevokedTokens,
+	_queryUpsertRole, _queryDeleteRolePermissions, _queryInsertRolePermission, _queryGetRole,
+	_queryAssignRole, _queryUnassignRole, _queryUserRoles, _queryUserPermissions,
+	_queryInsertAuthEvent,
+	_queryGetLoginFailure, _queryRecordLoginFailure, _queryLockLogin, _queryDeleteLoginFailure,
+	_queryDeleteStaleLoginFailures,
+	_queryInsertPasswordReset, _queryConsumePasswordReset, _queryDeleteUserPasswordResets,
+	_queryDeleteExpiredPasswordResets,
+	_queryInsertEmailVerification, _queryConsumeEmailVerification, _querySetEmailVerified,
+	_queryDeleteUserEmailVerifications, _queryDeleteExpiredEmailVerifications,
+	_querySaveTOTP, _queryGetTOTP, _queryConfirmTOTP, _queryUseTOTPStep, _queryDeleteTOTP,
+	_queryInsertRecoveryCode, _queryUseRecoveryCode, _queryDeleteUserRecoveryCodes,
+	_queryInsertMFAChallenge, _queryGetMFAChallenge, _queryDeleteMFAChallenge, _queryDeleteUserMFAChallenges,
+	_queryDeleteExpiredMFAChallenges,
+	_queryGetAppRedirectURIs, _queryInsertAppRedirectURI, _queryDeleteAppRedirectURIs,
+	_queryInsertAuthorizationCode, _queryConsumeAuthorizationCode, _queryGetUsedAuthorizationCode,
+	_querySetAuthorizationCodeTokens, _queryDeleteExpiredAuthorizationCodes,
+	_queryGetAppScopes, _queryInsertAppScope, _queryDeleteAppScopes,
+	_queryGetAppClaims, _queryInsertAppClaim, _queryDeleteAppClaims,
+	_querySetUserAttribute, _queryDeleteUserAttribute, _queryGetUserAttributes,
+}
+
 type AuthRepo struct {
 	*sqlite.SQLite
 }
 
-func New(mysql_ *sqlite.SQLite) *AuthRepo {
-	return &AuthRepo{mysql_}
+func New(mysql_ *sqlite.SQLite) (*AuthRepo, error) {
+	if err := mysql_.Prepare(_queries...); err != nil {
+		return nil, fmt.Errorf("internal - usecase - repo_sqlite - New: %w", err)
+	}
+
+	return &AuthRepo{mysql_}, nil
 }
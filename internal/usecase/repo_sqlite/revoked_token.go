@@ -0,0 +1,66 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	_queryInsertRevokedToken         = `INSERT INTO revoked_tokens(jti, user_id, app_id, expires_at, revoked_at) VALUES(?, ?, ?, ?, ?) ON CONFLICT(jti) DO NOTHING`
+	_queryIsTokenRevoked             = `SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = ?)`
+	_queryDeleteExpiredRevokedTokens = `DELETE FROM revoked_tokens WHERE expires_at <= ?`
+)
+
+func (r *AuthRepo) InsertRevokedToken(ctx context.Context, jti string, userID int, appID int, expiresAt time.Time) error {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.InsertRevokedToken"
+
+	stmt, err := r.Stmt(_queryInsertRevokedToken)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.ExecContext(ctx, jti, userID, appID, expiresAt.Unix(), time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepo) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.IsTokenRevoked"
+
+	stmt, err := r.Stmt(_queryIsTokenRevoked)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var revoked bool
+	if err := stmt.QueryRowContext(ctx, jti).Scan(&revoked); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return revoked, nil
+}
+
+func (r *AuthRepo) DeleteExpiredRevokedTokens(ctx context.Context, now time.Time) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.DeleteExpiredRevokedTokens"
+
+	stmt, err := r.Stmt(_queryDeleteExpiredRevokedTokens)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, now.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(id), nil
+}
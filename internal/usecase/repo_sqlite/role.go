@@ -0,0 +1,182 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+const (
+	_queryUpsertRole            = `INSERT INTO roles(app_id, name) VALUES(?, ?) ON CONFLICT(app_id, name) DO UPDATE SET name = excluded.name RETURNING id`
+	_queryDeleteRolePermissions = `DELETE FROM role_permissions WHERE role_id = ?`
+	_queryInsertRolePermission  = `INSERT INTO role_permissions(role_id, permission) VALUES(?, ?) ON CONFLICT DO NOTHING`
+	_queryUserRoles             = `SELECT r.name FROM roles r JOIN user_roles ur ON ur.role_id = r.id
+		WHERE ur.user_id = ? AND r.app_id = ? ORDER BY r.name`
+	_queryUserPermissions = `SELECT DISTINCT rp.permission FROM role_permissions rp
+		JOIN roles r ON r.id = rp.role_id
+		JOIN user_roles ur ON ur.role_id = r.id
+		WHERE ur.user_id = ? AND r.app_id = ? ORDER BY rp.permission`
+	_queryGetRole      = `SELECT id, app_id, name FROM roles WHERE app_id = ? AND name = ?`
+	_queryAssignRole   = `INSERT INTO user_roles(user_id, role_id) VALUES(?, ?) ON CONFLICT DO NOTHING`
+	_queryUnassignRole = `DELETE FROM user_roles WHERE user_id = ? AND role_id = ?`
+)
+
+// UpsertRole creates the role within the app or replaces the permissions of
+// an existing one.
+func (r *AuthRepo) UpsertRole(ctx context.Context, appID int, name string, permissions []string) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.UpsertRole"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	upsert, err := r.Stmt(_queryUpsertRole)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	deletePermissions, err := r.Stmt(_queryDeleteRolePermissions)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	insert, err := r.Stmt(_queryInsertRolePermission)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var id int
+	err = tx.StmtContext(ctx, upsert).QueryRowContext(ctx, appID, name).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.StmtContext(ctx, deletePermissions).ExecContext(ctx, id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	insert = tx.StmtContext(ctx, insert)
+	for _, permission := range permissions {
+		_, err := insert.ExecContext(ctx, id, permission)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *AuthRepo) GetRole(ctx context.Context, appID int, name string) (entity.Role, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.GetRole"
+
+	stmt, err := r.Stmt(_queryGetRole)
+	if err != nil {
+		return entity.Role{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	row := stmt.QueryRowContext(ctx, appID, name)
+
+	var role entity.Role
+	err = row.Scan(&role.ID, &role.AppID, &role.Name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Role{}, fmt.Errorf("%s: %w", op, entity.ErrRoleNotFound)
+		}
+
+		return entity.Role{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return role, nil
+}
+
+func (r *AuthRepo) AssignRole(ctx context.Context, userID int, roleID int) error {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.AssignRole"
+
+	stmt, err := r.Stmt(_queryAssignRole)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := stmt.ExecContext(ctx, userID, roleID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepo) UnassignRole(ctx context.Context, userID int, roleID int) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.UnassignRole"
+
+	stmt, err := r.Stmt(_queryUnassignRole)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, userID, roleID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(id), nil
+}
+
+// GetUserAccess returns the roles of the user within the app and the union of
+// their permissions, both sorted.
+func (r *AuthRepo) GetUserAccess(ctx context.Context, userID int, appID int) (entity.Access, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.GetUserAccess"
+
+	var access entity.Access
+
+	roles, err := r.queryStrings(ctx, _queryUserRoles, userID, appID)
+	if err != nil {
+		return entity.Access{}, fmt.Errorf("%s: %w", op, err)
+	}
+	access.Roles = roles
+
+	permissions, err := r.queryStrings(ctx, _queryUserPermissions, userID, appID)
+	if err != nil {
+		return entity.Access{}, fmt.Errorf("%s: %w", op, err)
+	}
+	access.Permissions = permissions
+
+	return access, nil
+}
+
+func (r *AuthRepo) queryStrings(ctx context.Context, query string, args ...any) ([]string, error) {
+	stmt, err := r.Stmt(query)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
@@ -0,0 +1,179 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+const signingKeyColumns = `id, kid, app_id, alg, COALESCE(secret, ''), private_key, public_key, created_at, COALESCE(retires_at, 0)`
+
+const (
+	_queryRetireSigningKeys    = `UPDATE signing_keys SET retires_at = ? WHERE app_id = ? AND retires_at IS NULL`
+	_queryInsertSigningKey     = `INSERT INTO signing_keys(kid, app_id, alg, secret, private_key, public_key, created_at) VALUES(?, ?, ?, ?, ?, ?, ?)`
+	_queryGetActiveSigningKey  = `SELECT ` + signingKeyColumns + ` FROM signing_keys WHERE app_id = ? AND retires_at IS NULL`
+	_queryGetSigningKey        = `SELECT ` + signingKeyColumns + ` FROM signing_keys WHERE kid = ?`
+	_queryGetPublicSigningKeys = `SELECT ` + signingKeyColumns + ` FROM signing_keys
+		WHERE public_key IS NOT NULL AND (retires_at IS NULL OR retires_at > ?) ORDER BY id`
+	_queryDeleteRetiredSigningKeys = `DELETE FROM signing_keys WHERE retires_at IS NOT NULL AND retires_at <= ?`
+)
+
+// RotateSigningKey schedules the current key of the app for retirement at
+// retiresAt and stores next as the new current key in one transaction.
+func (r *AuthRepo) RotateSigningKey(ctx context.Context, next entity.SigningKey, retiresAt time.Time) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.RotateSigningKey"
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	retire, err := r.Stmt(_queryRetireSigningKeys)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	insert, err := r.Stmt(_queryInsertSigningKey)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.StmtContext(ctx, retire).ExecContext(ctx, retiresAt.Unix(), next.AppID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var secret any
+	if next.Secret != "" {
+		secret = next.Secret
+	}
+
+	res, err := tx.StmtContext(ctx, insert).ExecContext(ctx,
+		next.KeyID, next.AppID, next.Alg, secret, next.PrivateKey, next.PublicKey, next.CreatedAt.Unix(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(id), nil
+}
+
+func (r *AuthRepo) GetActiveSigningKey(ctx context.Context, appID int) (entity.SigningKey, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.GetActiveSigningKey"
+
+	stmt, err := r.Stmt(_queryGetActiveSigningKey)
+	if err != nil {
+		return entity.SigningKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	key, err := scanSigningKey(stmt.QueryRowContext(ctx, appID))
+	if err != nil {
+		return entity.SigningKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+func (r *AuthRepo) GetSigningKey(ctx context.Context, kid string) (entity.SigningKey, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.GetSigningKey"
+
+	stmt, err := r.Stmt(_queryGetSigningKey)
+	if err != nil {
+		return entity.SigningKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	key, err := scanSigningKey(stmt.QueryRowContext(ctx, kid))
+	if err != nil {
+		return entity.SigningKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+// GetPublicSigningKeys returns asymmetric keys that still verify tokens at now.
+func (r *AuthRepo) GetPublicSigningKeys(ctx context.Context, now time.Time) ([]entity.SigningKey, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.GetPublicSigningKeys"
+
+	stmt, err := r.Stmt(_queryGetPublicSigningKeys)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, now.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var keys []entity.SigningKey
+	for rows.Next() {
+		key, err := scanSigningKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+func (r *AuthRepo) DeleteRetiredSigningKeys(ctx context.Context, now time.Time) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.DeleteRetiredSigningKeys"
+
+	stmt, err := r.Stmt(_queryDeleteRetiredSigningKeys)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, now.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(id), nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSigningKey(row scanner) (entity.SigningKey, error) {
+	var key entity.SigningKey
+	var createdAt, retiresAt int64
+	err := row.Scan(&key.ID, &key.KeyID, &key.AppID, &key.Alg, &key.Secret, &key.PrivateKey, &key.PublicKey, &createdAt, &retiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.SigningKey{}, entity.ErrSigningKeyNotFound
+		}
+
+		return entity.SigningKey{}, err
+	}
+
+	key.CreatedAt = time.Unix(createdAt, 0)
+	if retiresAt != 0 {
+		key.RetiresAt = time.Unix(retiresAt, 0)
+	}
+
+	return key, nil
+}
@@ -10,10 +10,30 @@ import (
 	"github.com/mattn/go-sqlite3"
 )
 
+const (
+	_queryInsertUser   = `INSERT INTO users(email, pass_hash) VALUES(?, ?)`
+	_queryGetUser      = `SELECT id, email, pass_hash, is_admin, email_verified FROM users WHERE email = ?`
+	_queryGetUserByID  = `SELECT id, email, pass_hash, is_admin, email_verified FROM users WHERE id = ?`
+	_queryIsAdmin      = `SELECT is_admin FROM users WHERE id = ?`
+	_querySetUserAdmin = `UPDATE users SET is_admin = ? WHERE id = ?`
+
+	_queryUpdateUserPassword  = `UPDATE users SET pass_hash = ? WHERE id = ?`
+	_queryRehashUserPassword  = `UPDATE users SET pass_hash = ? WHERE id = ? AND pass_hash = ?`
+	_queryListEmailCollisions = `SELECT email, user_id FROM email_collisions ORDER BY email, user_id`
+
+	_queryListUnnormalizedEmails = `SELECT id, email FROM users
+		WHERE (email <> lower(email) OR length(CAST(email AS BLOB)) <> length(email))
+			AND id NOT IN (SELECT user_id FROM email_collisions)
+		ORDER BY id`
+	_queryUpdateUserEmail   = `UPDATE users SET email = ? WHERE id = ?`
+	_queryAddEmailCollision = `INSERT OR IGNORE INTO email_collisions(email, user_id)
+		SELECT ?, id FROM users WHERE id = ? OR email = ?`
+)
+
 func (r *AuthRepo) InsertUser(ctx context.Context, email string, passHash []byte) (int, error) {
 	const op = "internal - usecase - repo_sqlite - AuthRepo.SaveUser"
 
-	stmt, err := r.DB.Prepare(`INSERT INTO users(email, pass_hash) VALUES(?, ?)`)
+	stmt, err := r.Stmt(_queryInsertUser)
 	if err != nil {
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
@@ -39,7 +59,7 @@ func (r *AuthRepo) InsertUser(ctx context.Context, email string, passHash []byte
 func (r *AuthRepo) GetUser(ctx context.Context, email string) (entity.User, error) {
 	const op = "internal - usecase - repo_sqlite - AuthRepo.GetUser"
 
-	stmt, err := r.DB.Prepare(`SELECT id, email, pass_hash FROM users WHERE email = ?`)
+	stmt, err := r.Stmt(_queryGetUser)
 	if err != nil {
 		return entity.User{}, fmt.Errorf("%s: %w", op, err)
 	}
@@ -47,7 +67,31 @@ func (r *AuthRepo) GetUser(ctx context.Context, email string) (entity.User, erro
 	row := stmt.QueryRowContext(ctx, email)
 
 	var user entity.User
-	err = row.Scan(&user.ID, &user.Email, &user.PassHash)
+	err = row.Scan(&user.ID, &user.Email, &user.PassHash, &user.IsAdmin, &user.EmailVerified)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.User{}, fmt.Errorf("%s: %w", op, entity.ErrUserNotFound)
+		}
+
+		return entity.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+
+}
+
+func (r *AuthRepo) GetUserByID(ctx context.Context, id int) (entity.User, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.GetUserByID"
+
+	stmt, err := r.Stmt(_queryGetUserByID)
+	if err != nil {
+		return entity.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	row := stmt.QueryRowContext(ctx, id)
+
+	var user entity.User
+	err = row.Scan(&user.ID, &user.Email, &user.PassHash, &user.IsAdmin, &user.EmailVerified)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return entity.User{}, fmt.Errorf("%s: %w", op, entity.ErrUserNotFound)
@@ -59,3 +103,203 @@ func (r *AuthRepo) GetUser(ctx context.Context, email string) (entity.User, erro
 	return user, nil
 
 }
+
+func (r *AuthRepo) IsAdmin(ctx context.Context, userID int) (bool, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.IsAdmin"
+
+	stmt, err := r.Stmt(_queryIsAdmin)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	row := stmt.QueryRowContext(ctx, userID)
+
+	var isAdmin bool
+	err = row.Scan(&isAdmin)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, fmt.Errorf("%s: %w", op, entity.ErrUserNotFound)
+		}
+
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return isAdmin, nil
+}
+
+func (r *AuthRepo) SetUserAdmin(ctx context.Context, userID int, isAdmin bool) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.SetUserAdmin"
+
+	stmt, err := r.Stmt(_querySetUserAdmin)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, isAdmin, userID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(id), nil
+}
+
+func (r *AuthRepo) UpdateUserPassword(ctx context.Context, userID int, passHash []byte) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.UpdateUserPassword"
+
+	stmt, err := r.Stmt(_queryUpdateUserPassword)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, passHash, userID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+// RehashUserPassword replaces the password hash of the user only while it
+// still is oldHash, so a password changed meanwhile is never overwritten.
+func (r *AuthRepo) RehashUserPassword(ctx context.Context, userID int, oldHash []byte, newHash []byte) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.RehashUserPassword"
+
+	stmt, err := r.Stmt(_queryRehashUserPassword)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, newHash, userID, oldHash)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+func (r *AuthRepo) ListEmailCollisions(ctx context.Context) ([]entity.EmailCollision, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.ListEmailCollisions"
+
+	stmt, err := r.Stmt(_queryListEmailCollisions)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var collisions []entity.EmailCollision
+	for rows.Next() {
+		var email string
+		var userID int
+		if err := rows.Scan(&email, &userID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if n := len(collisions); n > 0 && collisions[n-1].Email == email {
+			collisions[n-1].UserIDs = append(collisions[n-1].UserIDs, userID)
+		} else {
+			collisions = append(collisions, entity.EmailCollision{Email: email, UserIDs: []int{userID}})
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return collisions, nil
+}
+
+// ListUnnormalizedEmails returns the id and email of the users whose email
+// has uppercase or non-ASCII characters and is not flagged as a collision.
+func (r *AuthRepo) ListUnnormalizedEmails(ctx context.Context) ([]entity.User, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.ListUnnormalizedEmails"
+
+	stmt, err := r.Stmt(_queryListUnnormalizedEmails)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var users []entity.User
+	for rows.Next() {
+		var user entity.User
+		if err := rows.Scan(&user.ID, &user.Email); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+func (r *AuthRepo) UpdateUserEmail(ctx context.Context, userID int, email string) (int, error) {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.UpdateUserEmail"
+
+	stmt, err := r.Stmt(_queryUpdateUserEmail)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := stmt.ExecContext(ctx, email, userID)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return 0, fmt.Errorf("%s: %w", op, entity.ErrUserExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(count), nil
+}
+
+// AddEmailCollision flags the user together with the account that already
+// holds email.
+func (r *AuthRepo) AddEmailCollision(ctx context.Context, email string, userID int) error {
+	const op = "internal - usecase - repo_sqlite - AuthRepo.AddEmailCollision"
+
+	stmt, err := r.Stmt(_queryAddEmailCollision)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := stmt.ExecContext(ctx, email, userID, email); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,510 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+)
+
+// timedRepo bounds every call of the wrapped repo by timeout and logs calls
+// running longer than slow.
+type timedRepo struct {
+	repo    AuthRepo
+	log     *slog.Logger
+	timeout time.Duration
+	slow    time.Duration
+}
+
+func (r *timedRepo) do(ctx context.Context, method string, fn func(ctx context.Context) error) error {
+	const op = "internal - usecase - timedRepo.do"
+
+	queryCtx := ctx
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		queryCtx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := fn(queryCtx)
+	elapsed := time.Since(start)
+
+	if r.slow > 0 && elapsed >= r.slow {
+		r.log.Warn("slow query",
+			slog.String("op", op),
+			slog.String("method", method),
+			slog.Duration("elapsed", elapsed),
+		)
+	}
+
+	if err != nil && errors.Is(queryCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", entity.ErrQueryTimeout, err)
+	}
+
+	return err
+}
+
+func timed[T any](ctx context.Context, r *timedRepo, method string, fn func(ctx context.Context) (T, error)) (T, error) {
+	var v T
+	err := r.do(ctx, method, func(ctx context.Context) error {
+		var err error
+		v, err = fn(ctx)
+		return err
+	})
+
+	return v, err
+}
+
+func (r *timedRepo) InsertUser(ctx context.Context, email string, passHash []byte) (int, error) {
+	return timed(ctx, r, "InsertUser", func(ctx context.Context) (int, error) {
+		return r.repo.InsertUser(ctx, email, passHash)
+	})
+}
+
+func (r *timedRepo) GetUser(ctx context.Context, email string) (entity.User, error) {
+	return timed(ctx, r, "GetUser", func(ctx context.Context) (entity.User, error) {
+		return r.repo.GetUser(ctx, email)
+	})
+}
+
+func (r *timedRepo) GetAppForUser(ctx context.Context, id int) (entity.App, error) {
+	return timed(ctx, r, "GetAppForUser", func(ctx context.Context) (entity.App, error) {
+		return r.repo.GetAppForUser(ctx, id)
+	})
+}
+
+func (r *timedRepo) GetAppByName(ctx context.Context, name string) (entity.App, error) {
+	return timed(ctx, r, "GetAppByName", func(ctx context.Context) (entity.App, error) {
+		return r.repo.GetAppByName(ctx, name)
+	})
+}
+
+func (r *timedRepo) InsertApp(ctx context.Context, name string, passHash []byte, secret string, ttlHour int) (int, error) {
+	return timed(ctx, r, "InsertApp", func(ctx context.Context) (int, error) {
+		return r.repo.InsertApp(ctx, name, passHash, secret, ttlHour)
+	})
+}
+
+func (r *timedRepo) UpdateApp(ctx context.Context, id_ int, secret string, ttlHour int) (int, error) {
+	return timed(ctx, r, "UpdateApp", func(ctx context.Context) (int, error) {
+		return r.repo.UpdateApp(ctx, id_, secret, ttlHour)
+	})
+}
+
+func (r *timedRepo) UpdateAppPassword(ctx context.Context, id_ int, passHash []byte) (int, error) {
+	return timed(ctx, r, "UpdateAppPassword", func(ctx context.Context) (int, error) {
+		return r.repo.UpdateAppPassword(ctx, id_, passHash)
+	})
+}
+
+func (r *timedRepo) DeleteApp(ctx context.Context, id_ int) (int, error) {
+	return timed(ctx, r, "DeleteApp", func(ctx context.Context) (int, error) {
+		return r.repo.DeleteApp(ctx, id_)
+	})
+}
+
+func (r *timedRepo) SetAppDisabled(ctx context.Context, id_ int, disabled bool) (int, error) {
+	return timed(ctx, r, "SetAppDisabled", func(ctx context.Context) (int, error) {
+		return r.repo.SetAppDisabled(ctx, id_, disabled)
+	})
+}
+
+func (r *timedRepo) SetAppEmailPolicy(ctx context.Context, id_ int, requireVerified bool) (int, error) {
+	return timed(ctx, r, "SetAppEmailPolicy", func(ctx context.Context) (int, error) {
+		return r.repo.SetAppEmailPolicy(ctx, id_, requireVerified)
+	})
+}
+
+func (r *timedRepo) ListApps(ctx context.Context, afterID int, limit int) ([]entity.App, error) {
+	return timed(ctx, r, "ListApps", func(ctx context.Context) ([]entity.App, error) {
+		return r.repo.ListApps(ctx, afterID, limit)
+	})
+}
+
+func (r *timedRepo) GetUserByID(ctx context.Context, id int) (entity.User, error) {
+	return timed(ctx, r, "GetUserByID", func(ctx context.Context) (entity.User, error) {
+		return r.repo.GetUserByID(ctx, id)
+	})
+}
+
+func (r *timedRepo) IsAdmin(ctx context.Context, userID int) (bool, error) {
+	return timed(ctx, r, "IsAdmin", func(ctx context.Context) (bool, error) {
+		return r.repo.IsAdmin(ctx, userID)
+	})
+}
+
+func (r *timedRepo) SetUserAdmin(ctx context.Context, userID int, isAdmin bool) (int, error) {
+	return timed(ctx, r, "SetUserAdmin", func(ctx context.Context) (int, error) {
+		return r.repo.SetUserAdmin(ctx, userID, isAdmin)
+	})
+}
+
+func (r *timedRepo) UpdateUserPassword(ctx context.Context, userID int, passHash []byte) (int, error) {
+	return timed(ctx, r, "UpdateUserPassword", func(ctx context.Context) (int, error) {
+		return r.repo.UpdateUserPassword(ctx, userID, passHash)
+	})
+}
+
+func (r *timedRepo) RehashUserPassword(ctx context.Context, userID int, oldHash []byte, newHash []byte) (int, error) {
+	return timed(ctx, r, "RehashUserPassword", func(ctx context.Context) (int, error) {
+		return r.repo.RehashUserPassword(ctx, userID, oldHash, newHash)
+	})
+}
+
+func (r *timedRepo) ListEmailCollisions(ctx context.Context) ([]entity.EmailCollision, error) {
+	return timed(ctx, r, "ListEmailCollisions", func(ctx context.Context) ([]entity.EmailCollision, error) {
+		return r.repo.ListEmailCollisions(ctx)
+	})
+}
+
+func (r *timedRepo) ListUnnormalizedEmails(ctx context.Context) ([]entity.User, error) {
+	return timed(ctx, r, "ListUnnormalizedEmails", func(ctx context.Context) ([]entity.User, error) {
+		return r.repo.ListUnnormalizedEmails(ctx)
+	})
+}
+
+func (r *timedRepo) UpdateUserEmail(ctx context.Context, userID int, email string) (int, error) {
+	return timed(ctx, r, "UpdateUserEmail", func(ctx context.Context) (int, error) {
+		return r.repo.UpdateUserEmail(ctx, userID, email)
+	})
+}
+
+func (r *timedRepo) AddEmailCollision(ctx context.Context, email string, userID int) error {
+	return r.do(ctx, "AddEmailCollision", func(ctx context.Context) error {
+		return r.repo.AddEmailCollision(ctx, email, userID)
+	})
+}
+
+func (r *timedRepo) InsertRefreshToken(ctx context.Context, token entity.RefreshToken) (int, error) {
+	return timed(ctx, r, "InsertRefreshToken", func(ctx context.Context) (int, error) {
+		return r.repo.InsertRefreshToken(ctx, token)
+	})
+}
+
+func (r *timedRepo) GetRefreshToken(ctx context.Context, tokenHash []byte) (entity.RefreshToken, error) {
+	return timed(ctx, r, "GetRefreshToken", func(ctx context.Context) (entity.RefreshToken, error) {
+		return r.repo.GetRefreshToken(ctx, tokenHash)
+	})
+}
+
+func (r *timedRepo) RotateRefreshToken(ctx context.Context, id_ int, next entity.RefreshToken) (int, error) {
+	return timed(ctx, r, "RotateRefreshToken", func(ctx context.Context) (int, error) {
+		return r.repo.RotateRefreshToken(ctx, id_, next)
+	})
+}
+
+func (r *timedRepo) RevokeRefreshTokenFamily(ctx context.Context, familyID string) (int, error) {
+	return timed(ctx, r, "RevokeRefreshTokenFamily", func(ctx context.Context) (int, error) {
+		return r.repo.RevokeRefreshTokenFamily(ctx, familyID)
+	})
+}
+
+func (r *timedRepo) RevokeUserRefreshTokens(ctx context.Context, userID int) (int, error) {
+	return timed(ctx, r, "RevokeUserRefreshTokens", func(ctx context.Context) (int, error) {
+		return r.repo.RevokeUserRefreshTokens(ctx, userID)
+	})
+}
+
+func (r *timedRepo) UpdateAppSigningAlg(ctx context.Context, id_ int, alg string) (int, error) {
+	return timed(ctx, r, "UpdateAppSigningAlg", func(ctx context.Context) (int, error) {
+		return r.repo.UpdateAppSigningAlg(ctx, id_, alg)
+	})
+}
+
+func (r *timedRepo) RotateSigningKey(ctx context.Context, next entity.SigningKey, retiresAt time.Time) (int, error) {
+	return timed(ctx, r, "RotateSigningKey", func(ctx context.Context) (int, error) {
+		return r.repo.RotateSigningKey(ctx, next, retiresAt)
+	})
+}
+
+func (r *timedRepo) GetActiveSigningKey(ctx context.Context, appID int) (entity.SigningKey, error) {
+	return timed(ctx, r, "GetActiveSigningKey", func(ctx context.Context) (entity.SigningKey, error) {
+		return r.repo.GetActiveSigningKey(ctx, appID)
+	})
+}
+
+func (r *timedRepo) GetSigningKey(ctx context.Context, kid string) (entity.SigningKey, error) {
+	return timed(ctx, r, "GetSigningKey", func(ctx context.Context) (entity.SigningKey, error) {
+		return r.repo.GetSigningKey(ctx, kid)
+	})
+}
+
+func (r *timedRepo) GetPublicSigningKeys(ctx context.Context, now time.Time) ([]entity.SigningKey, error) {
+	return timed(ctx, r, "GetPublicSigningKeys", func(ctx context.Context) ([]entity.SigningKey, error) {
+		return r.repo.GetPublicSigningKeys(ctx, now)
+	})
+}
+
+func (r *timedRepo) DeleteRetiredSigningKeys(ctx context.Context, now time.Time) (int, error) {
+	return timed(ctx, r, "DeleteRetiredSigningKeys", func(ctx context.Context) (int, error) {
+		return r.repo.DeleteRetiredSigningKeys(ctx, now)
+	})
+}
+
+func (r *timedRepo) InsertRevokedToken(ctx context.Context, jti string, userID int, appID int, expiresAt time.Time) error {
+	return r.do(ctx, "InsertRevokedToken", func(ctx context.Context) error {
+		return r.repo.InsertRevokedToken(ctx, jti, userID, appID, expiresAt)
+	})
+}
+
+func (r *timedRepo) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	return timed(ctx, r, "IsTokenRevoked", func(ctx context.Context) (bool, error) {
+		return r.repo.IsTokenRevoked(ctx, jti)
+	})
+}
+
+func (r *timedRepo) DeleteExpiredRevokedTokens(ctx context.Context, now time.Time) (int, error) {
+	return timed(ctx, r, "DeleteExpiredRevokedTokens", func(ctx context.Context) (int, error) {
+		return r.repo.DeleteExpiredRevokedTokens(ctx, now)
+	})
+}
+
+func (r *timedRepo) UpsertRole(ctx context.Context, appID int, name string, permissions []string) (int, error) {
+	return timed(ctx, r, "UpsertRole", func(ctx context.Context) (int, error) {
+		return r.repo.UpsertRole(ctx, appID, name, permissions)
+	})
+}
+
+func (r *timedRepo) GetRole(ctx context.Context, appID int, name string) (entity.Role, error) {
+	return timed(ctx, r, "GetRole", func(ctx context.Context) (entity.Role, error) {
+		return r.repo.GetRole(ctx, appID, name)
+	})
+}
+
+func (r *timedRepo) AssignRole(ctx context.Context, userID int, roleID int) error {
+	return r.do(ctx, "AssignRole", func(ctx context.Context) error {
+		return r.repo.AssignRole(ctx, userID, roleID)
+	})
+}
+
+func (r *timedRepo) UnassignRole(ctx context.Context, userID int, roleID int) (int, error) {
+	return timed(ctx, r, "UnassignRole", func(ctx context.Context) (int, error) {
+		return r.repo.UnassignRole(ctx, userID, roleID)
+	})
+}
+
+func (r *timedRepo) GetUserAccess(ctx context.Context, userID int, appID int) (entity.Access, error) {
+	return timed(ctx, r, "GetUserAccess", func(ctx context.Context) (entity.Access, error) {
+		return r.repo.GetUserAccess(ctx, userID, appID)
+	})
+}
+
+func (r *timedRepo) InsertAuthEvent(ctx context.Context, event entity.AuthEvent) (int, error) {
+	return timed(ctx, r, "InsertAuthEvent", func(ctx context.Context) (int, error) {
+		return r.repo.InsertAuthEvent(ctx, event)
+	})
+}
+
+func (r *timedRepo) ListAuthEvents(ctx context.Context, filter entity.AuthEventFilter) ([]entity.AuthEvent, error) {
+	return timed(ctx, r, "ListAuthEvents", func(ctx context.Context) ([]entity.AuthEvent, error) {
+		return r.repo.ListAuthEvents(ctx, filter)
+	})
+}
+
+func (r *timedRepo) GetLoginFailure(ctx context.Context, key string) (entity.LoginFailure, error) {
+	return timed(ctx, r, "GetLoginFailure", func(ctx context.Context) (entity.LoginFailure, error) {
+		return r.repo.GetLoginFailure(ctx, key)
+	})
+}
+
+func (r *timedRepo) RecordLoginFailure(ctx context.Context, key string, now time.Time, windowStart time.Time) (int, error) {
+	return timed(ctx, r, "RecordLoginFailure", func(ctx context.Context) (int, error) {
+		return r.repo.RecordLoginFailure(ctx, key, now, windowStart)
+	})
+}
+
+func (r *timedRepo) LockLogin(ctx context.Context, key string, until time.Time) (int, error) {
+	return timed(ctx, r, "LockLogin", func(ctx context.Context) (int, error) {
+		return r.repo.LockLogin(ctx, key, until)
+	})
+}
+
+func (r *timedRepo) DeleteLoginFailure(ctx context.Context, key string) error {
+	return r.do(ctx, "DeleteLoginFailure", func(ctx context.Context) error {
+		return r.repo.DeleteLoginFailure(ctx, key)
+	})
+}
+
+func (r *timedRepo) DeleteStaleLoginFailures(ctx context.Context, now time.Time, windowStart time.Time) (int, error) {
+	return timed(ctx, r, "DeleteStaleLoginFailures", func(ctx context.Context) (int, error) {
+		return r.repo.DeleteStaleLoginFailures(ctx, now, windowStart)
+	})
+}
+
+func (r *timedRepo) InsertPasswordReset(ctx context.Context, reset entity.PasswordReset) error {
+	return r.do(ctx, "InsertPasswordReset", func(ctx context.Context) error {
+		return r.repo.InsertPasswordReset(ctx, reset)
+	})
+}
+
+func (r *timedRepo) ResetPassword(ctx context.Context, tokenHash []byte, now time.Time, passHash []byte) (int, error) {
+	return timed(ctx, r, "ResetPassword", func(ctx context.Context) (int, error) {
+		return r.repo.ResetPassword(ctx, tokenHash, now, passHash)
+	})
+}
+
+func (r *timedRepo) DeleteExpiredPasswordResets(ctx context.Context, now time.Time) (int, error) {
+	return timed(ctx, r, "DeleteExpiredPasswordResets", func(ctx context.Context) (int, error) {
+		return r.repo.DeleteExpiredPasswordResets(ctx, now)
+	})
+}
+
+func (r *timedRepo) InsertEmailVerification(ctx context.Context, verification entity.EmailVerification) error {
+	return r.do(ctx, "InsertEmailVerification", func(ctx context.Context) error {
+		return r.repo.InsertEmailVerification(ctx, verification)
+	})
+}
+
+func (r *timedRepo) VerifyEmail(ctx context.Context, tokenHash []byte, now time.Time) (int, error) {
+	return timed(ctx, r, "VerifyEmail", func(ctx context.Context) (int, error) {
+		return r.repo.VerifyEmail(ctx, tokenHash, now)
+	})
+}
+
+func (r *timedRepo) DeleteExpiredEmailVerifications(ctx context.Context, now time.Time) (int, error) {
+	return timed(ctx, r, "DeleteExpiredEmailVerifications", func(ctx context.Context) (int, error) {
+		return r.repo.DeleteExpiredEmailVerifications(ctx, now)
+	})
+}
+
+func (r *timedRepo) SaveTOTP(ctx context.Context, totp entity.TOTP) (int, error) {
+	return timed(ctx, r, "SaveTOTP", func(ctx context.Context) (int, error) {
+		return r.repo.SaveTOTP(ctx, totp)
+	})
+}
+
+func (r *timedRepo) GetTOTP(ctx context.Context, userID int) (entity.TOTP, error) {
+	return timed(ctx, r, "GetTOTP", func(ctx context.Context) (entity.TOTP, error) {
+		return r.repo.GetTOTP(ctx, userID)
+	})
+}
+
+func (r *timedRepo) ConfirmTOTP(ctx context.Context, userID int, step int64, codeHashes [][]byte) (int, error) {
+	return timed(ctx, r, "ConfirmTOTP", func(ctx context.Context) (int, error) {
+		return r.repo.ConfirmTOTP(ctx, userID, step, codeHashes)
+	})
+}
+
+func (r *timedRepo) UseTOTPStep(ctx context.Context, userID int, step int64) (int, error) {
+	return timed(ctx, r, "UseTOTPStep", func(ctx context.Context) (int, error) {
+		return r.repo.UseTOTPStep(ctx, userID, step)
+	})
+}
+
+func (r *timedRepo) UseRecoveryCode(ctx context.Context, userID int, codeHash []byte) (int, error) {
+	return timed(ctx, r, "UseRecoveryCode", func(ctx context.Context) (int, error) {
+		return r.repo.UseRecoveryCode(ctx, userID, codeHash)
+	})
+}
+
+func (r *timedRepo) DeleteTOTP(ctx context.Context, userID int) (int, error) {
+	return timed(ctx, r, "DeleteTOTP", func(ctx context.Context) (int, error) {
+		return r.repo.DeleteTOTP(ctx, userID)
+	})
+}
+
+func (r *timedRepo) InsertMFAChallenge(ctx context.Context, challenge entity.MFAChallenge) error {
+	return r.do(ctx, "InsertMFAChallenge", func(ctx context.Context) error {
+		return r.repo.InsertMFAChallenge(ctx, challenge)
+	})
+}
+
+func (r *timedRepo) GetMFAChallenge(ctx context.Context, tokenHash []byte, now time.Time) (entity.MFAChallenge, error) {
+	return timed(ctx, r, "GetMFAChallenge", func(ctx context.Context) (entity.MFAChallenge, error) {
+		return r.repo.GetMFAChallenge(ctx, tokenHash, now)
+	})
+}
+
+func (r *timedRepo) DeleteMFAChallenge(ctx context.Context, tokenHash []byte) (int, error) {
+	return timed(ctx, r, "DeleteMFAChallenge", func(ctx context.Context) (int, error) {
+		return r.repo.DeleteMFAChallenge(ctx, tokenHash)
+	})
+}
+
+func (r *timedRepo) DeleteExpiredMFAChallenges(ctx context.Context, now time.Time) (int, error) {
+	return timed(ctx, r, "DeleteExpiredMFAChallenges", func(ctx context.Context) (int, error) {
+		return r.repo.DeleteExpiredMFAChallenges(ctx, now)
+	})
+}
+
+func (r *timedRepo) SetAppRedirectURIs(ctx context.Context, appID int, uris []string) error {
+	return r.do(ctx, "SetAppRedirectURIs", func(ctx context.Context) error {
+		return r.repo.SetAppRedirectURIs(ctx, appID, uris)
+	})
+}
+
+func (r *timedRepo) GetAppRedirectURIs(ctx context.Context, appID int) ([]string, error) {
+	return timed(ctx, r, "GetAppRedirectURIs", func(ctx context.Context) ([]string, error) {
+		return r.repo.GetAppRedirectURIs(ctx, appID)
+	})
+}
+
+func (r *timedRepo) InsertAuthorizationCode(ctx context.Context, code entity.AuthorizationCode) error {
+	return r.do(ctx, "InsertAuthorizationCode", func(ctx context.Context) error {
+		return r.repo.InsertAuthorizationCode(ctx, code)
+	})
+}
+
+func (r *timedRepo) ConsumeAuthorizationCode(ctx context.Context, codeHash []byte, now time.Time) (entity.AuthorizationCode, error) {
+	return timed(ctx, r, "ConsumeAuthorizationCode", func(ctx context.Context) (entity.AuthorizationCode, error) {
+		return r.repo.ConsumeAuthorizationCode(ctx, codeHash, now)
+	})
+}
+
+func (r *timedRepo) SetAuthorizationCodeTokens(ctx context.Context, code entity.AuthorizationCode) error {
+	return r.do(ctx, "SetAuthorizationCodeTokens", func(ctx context.Context) error {
+		return r.repo.SetAuthorizationCodeTokens(ctx, code)
+	})
+}
+
+func (r *timedRepo) DeleteExpiredAuthorizationCodes(ctx context.Context, now time.Time) (int, error) {
+	return timed(ctx, r, "DeleteExpiredAuthorizationCodes", func(ctx context.Context) (int, error) {
+		return r.repo.DeleteExpiredAuthorizationCodes(ctx, now)
+	})
+}
+
+func (r *timedRepo) SetAppScopes(ctx context.Context, appID int, scopes []string) error {
+	return r.do(ctx, "SetAppScopes", func(ctx context.Context) error {
+		return r.repo.SetAppScopes(ctx, appID, scopes)
+	})
+}
+
+func (r *timedRepo) GetAppScopes(ctx context.Context, appID int) ([]string, error) {
+	return timed(ctx, r, "GetAppScopes", func(ctx context.Context) ([]string, error) {
+		return r.repo.GetAppScopes(ctx, appID)
+	})
+}
+
+func (r *timedRepo) SetAppClaims(ctx context.Context, appID int, claims map[string]string) error {
+	return r.do(ctx, "SetAppClaims", func(ctx context.Context) error {
+		return r.repo.SetAppClaims(ctx, appID, claims)
+	})
+}
+
+func (r *timedRepo) GetAppClaims(ctx context.Context, appID int) (map[string]string, error) {
+	return timed(ctx, r, "GetAppClaims", func(ctx context.Context) (map[string]string, error) {
+		return r.repo.GetAppClaims(ctx, appID)
+	})
+}
+
+func (r *timedRepo) SetUserAttribute(ctx context.Context, userID int, key string, value string) error {
+	return r.do(ctx, "SetUserAttribute", func(ctx context.Context) error {
+		return r.repo.SetUserAttribute(ctx, userID, key, value)
+	})
+}
+
+func (r *timedRepo) DeleteUserAttribute(ctx context.Context, userID int, key string) (int, error) {
+	return timed(ctx, r, "DeleteUserAttribute", func(ctx context.Context) (int, error) {
+		return r.repo.DeleteUserAttribute(ctx, userID, key)
+	})
+}
+
+func (r *timedRepo) GetUserAttributes(ctx context.Context, userID int) (map[string]string, error) {
+	return timed(ctx, r, "GetUserAttributes", func(ctx context.Context) (map[string]string, error) {
+		return r.repo.GetUserAttributes(ctx, userID)
+	})
+}
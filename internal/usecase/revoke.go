@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+)
+
+// RevokeToken puts the jti of a valid access token on the revocation list
+// until the token expires.
+func (a *AuthUseCase) RevokeToken(ctx context.Context, token string) error {
+	const op = "internal - usecase - Auth.RevokeToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	claims, err := a.revocableClaims(ctx, log, token)
+	if err != nil {
+		if errors.Is(err, entity.ErrTokenRevoked) {
+			return nil
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.repo.InsertRevokedToken(ctx, claims.ID, claims.UserID, claims.AppID, claims.ExpiresAt); err != nil {
+		log.Error("failed to revoke token", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("token revoked", slog.Int("user_id", claims.UserID), slog.String("jti", claims.ID))
+
+	return nil
+}
+
+// Logout revokes the access token and, if given, the whole family of the
+// refresh token issued alongside it.
+func (a *AuthUseCase) Logout(ctx context.Context, accessToken string, refreshToken string) error {
+	const op = "internal - usecase - Auth.Logout"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	claims, err := a.revocableClaims(ctx, log, accessToken)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int("user_id", claims.UserID))
+
+	var familyID string
+	if refreshToken != "" {
+		current, err := a.repo.GetRefreshToken(ctx, hashToken(refreshToken))
+		if err != nil {
+			if errors.Is(err, entity.ErrRefreshTokenNotFound) {
+				log.Warn("refresh token not found", error_.Err(err))
+
+				return fmt.Errorf("%s: %w", op, entity.ErrRefreshTokenNotFound)
+			}
+
+			log.Error("failed to get refresh token", error_.Err(err))
+
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if current.UserID != claims.UserID || current.AppID != claims.AppID {
+			log.Warn("refresh token belongs to another session")
+
+			return fmt.Errorf("%s: %w", op, entity.ErrRefreshTokenNotFound)
+		}
+
+		familyID = current.FamilyID
+	}
+
+	if err := a.repo.InsertRevokedToken(ctx, claims.ID, claims.UserID, claims.AppID, claims.ExpiresAt); err != nil {
+		log.Error("failed to revoke token", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if familyID != "" {
+		if _, err := a.repo.RevokeRefreshTokenFamily(ctx, familyID); err != nil {
+			log.Error("failed to revoke token family", error_.Err(err))
+
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	log.Info("user logged out")
+
+	return nil
+}
+
+// revocableClaims validates token and makes sure it carries a jti.
+func (a *AuthUseCase) revocableClaims(ctx context.Context, log *slog.Logger, token string) (entity.Claims, error) {
+	claims, err := a.ValidateToken(ctx, token)
+	if err != nil {
+		return entity.Claims{}, err
+	}
+
+	if claims.ID == "" {
+		log.Warn("token has no jti")
+
+		return entity.Claims{}, entity.ErrTokenNotRevocable
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+)
+
+// SetRole creates a role within the app or replaces its permissions.
+func (a *AuthUseCase) SetRole(ctx context.Context, appName string, appPassword string, role string, permissions []string) (int, error) {
+	const op = "internal - usecase - Auth.SetRole"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("service_name", appName),
+		slog.String("role", role),
+	)
+
+	appID, err := a.getAppByName(ctx, appName, appPassword)
+	if err != nil {
+		return 0, err
+	}
+
+	log.Info("attempting to set role")
+
+	id, err := a.repo.UpsertRole(ctx, appID, role, permissions)
+	if err != nil {
+		log.Error("failed to save role", error_.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (a *AuthUseCase) AssignRole(ctx context.Context, appName string, appPassword string, userID int, role string) error {
+	const op = "internal - usecase - Auth.AssignRole"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("service_name", appName),
+		slog.Int("user_id", userID),
+		slog.String("role", role),
+	)
+
+	roleID, err := a.appUserRole(ctx, log, appName, appPassword, userID, role)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.repo.AssignRole(ctx, userID, roleID); err != nil {
+		log.Error("failed to assign role", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("role assigned")
+
+	return nil
+}
+
+func (a *AuthUseCase) UnassignRole(ctx context.Context, appName string, appPassword string, userID int, role string) error {
+	const op = "internal - usecase - Auth.UnassignRole"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("service_name", appName),
+		slog.Int("user_id", userID),
+		slog.String("role", role),
+	)
+
+	roleID, err := a.appUserRole(ctx, log, appName, appPassword, userID, role)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := a.repo.UnassignRole(ctx, userID, roleID); err != nil {
+		log.Error("failed to unassign role", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("role unassigned")
+
+	return nil
+}
+
+// appUserRole authenticates the app and resolves the user and the app's role.
+func (a *AuthUseCase) appUserRole(ctx context.Context, log *slog.Logger, appName string, appPassword string, userID int, role string) (int, error) {
+	appID, err := a.getAppByName(ctx, appName, appPassword)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := a.repo.GetUserByID(ctx, userID); err != nil {
+		if errors.Is(err, entity.ErrUserNotFound) {
+			log.Warn("user not found", error_.Err(err))
+
+			return 0, entity.ErrUserNotFound
+		}
+
+		log.Error("failed to get user", error_.Err(err))
+
+		return 0, err
+	}
+
+	r, err := a.repo.GetRole(ctx, appID, role)
+	if err != nil {
+		if errors.Is(err, entity.ErrRoleNotFound) {
+			log.Warn("role not found", error_.Err(err))
+
+			return 0, entity.ErrRoleNotFound
+		}
+
+		log.Error("failed to get role", error_.Err(err))
+
+		return 0, err
+	}
+
+	return r.ID, nil
+}
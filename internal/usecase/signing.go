@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	_rsaKeyBits = 2048
+	_idBytes    = 16
+)
+
+var _validSigningMethods = []string{
+	entity.SigningAlgHS256,
+	entity.SigningAlgRS256,
+	entity.SigningAlgEdDSA,
+}
+
+func (a *AuthUseCase) SetSigningAlgorithm(ctx context.Context, name string, password string, alg string) (int, error) {
+	const op = "internal - usecase - Auth.SetSigningAlgorithm"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("service_name", name),
+		slog.String("alg", alg),
+	)
+
+	id, err := a.getAppByName(ctx, name, password)
+	if err != nil {
+		return 0, err
+	}
+
+	log.Info("attempting to set signing algorithm")
+
+	app, err := a.repo.GetAppForUser(ctx, id)
+	if err != nil {
+		log.Error("failed to get app", error_.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := a.rotateSigningKey(ctx, app, alg); err != nil {
+		log.Warn("failed to rotate signing key", error_.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := a.repo.UpdateAppSigningAlg(ctx, id, alg); err != nil {
+		log.Error("failed to save signing algorithm", error_.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("signing algorithm updated")
+
+	a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventAppUpdated, AppID: id, Details: "signing algorithm set to " + alg})
+
+	return id, nil
+}
+
+func (a *AuthUseCase) RotateSigningKey(ctx context.Context, name string, password string) (string, error) {
+	const op = "internal - usecase - Auth.RotateSigningKey"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("service_name", name),
+	)
+
+	id, err := a.getAppByName(ctx, name, password)
+	if err != nil {
+		return "", err
+	}
+
+	log.Info("attempting to rotate signing key")
+
+	app, err := a.repo.GetAppForUser(ctx, id)
+	if err != nil {
+		log.Error("failed to get app", error_.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	key, err := a.rotateSigningKey(ctx, app, app.SigningAlg)
+	if err != nil {
+		log.Error("failed to rotate signing key", error_.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("signing key rotated", slog.String("kid", key.KeyID))
+
+	a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventAppUpdated, AppID: id, Details: "signing key rotated to " + key.KeyID})
+
+	return key.KeyID, nil
+}
+
+func (a *AuthUseCase) JWKS(ctx context.Context) ([]entity.JWK, error) {
+	const op = "internal - usecase - Auth.JWKS"
+
+	signingKeys, err := a.repo.GetPublicSigningKeys(ctx, time.Now())
+	if err != nil {
+		a.log.Error("failed to get public keys", slog.String("op", op), error_.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	keys := make([]entity.JWK, 0, len(signingKeys))
+	for _, key := range signingKeys {
+		publicKey, err := x509.ParsePKIXPublicKey(key.PublicKey)
+		if err != nil {
+			a.log.Error("failed to parse public key", slog.String("op", op), slog.String("kid", key.KeyID), error_.Err(err))
+
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		keys = append(keys, entity.JWK{
+			KeyID:     key.KeyID,
+			Algorithm: key.Alg,
+			PublicKey: publicKey,
+		})
+	}
+
+	return keys, nil
+}
+
+// rotateSigningKey makes a fresh alg key current for app. The previous key keeps
+// verifying for the overlap period, or the app's token TTL if that is longer,
+// so tokens issued right before the rotation stay valid.
+func (a *AuthUseCase) rotateSigningKey(ctx context.Context, app entity.App, alg string) (entity.SigningKey, error) {
+	privateKey, publicKey, err := generateKeyPair(alg)
+	if err != nil {
+		return entity.SigningKey{}, err
+	}
+
+	kid, err := newID()
+	if err != nil {
+		return entity.SigningKey{}, err
+	}
+
+	now := time.Now()
+	key := entity.SigningKey{
+		KeyID:      kid,
+		AppID:      app.ID,
+		Alg:        alg,
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+		CreatedAt:  now,
+	}
+	if alg == entity.SigningAlgHS256 {
+		key.Secret = app.Secret
+	}
+
+	overlap := max(a.keyOverlap, time.Duration(app.TTLHours)*time.Hour)
+
+	id, err := a.repo.RotateSigningKey(ctx, key, now.Add(overlap))
+	if err != nil {
+		return entity.SigningKey{}, err
+	}
+	key.ID = id
+
+	return key, nil
+}
+
+func (a *AuthUseCase) activeSigningKey(ctx context.Context, app entity.App) (entity.SigningKey, error) {
+	key, err := a.repo.GetActiveSigningKey(ctx, app.ID)
+	if err != nil {
+		if !errors.Is(err, entity.ErrSigningKeyNotFound) {
+			return entity.SigningKey{}, err
+		}
+
+		return a.rotateSigningKey(ctx, app, app.SigningAlg)
+	}
+
+	return key, nil
+}
+
+// retireSigningKeys deletes keys whose overlap period is over.
+func (a *AuthUseCase) retireSigningKeys(ctx context.Context) (int, error) {
+	return a.repo.DeleteRetiredSigningKeys(ctx, time.Now())
+}
+
+// generateKeyPair returns DER encoded PKCS #8 private and PKIX public keys.
+// HS256 apps sign with their shared secret and get no key pair.
+func generateKeyPair(alg string) ([]byte, []byte, error) {
+	var privateKey, publicKey any
+
+	switch alg {
+	case entity.SigningAlgHS256:
+		return nil, nil, nil
+	case entity.SigningAlgRS256:
+		key, err := rsa.GenerateKey(rand.Reader, _rsaKeyBits)
+		if err != nil {
+			return nil, nil, err
+		}
+		privateKey, publicKey = key, &key.PublicKey
+	case entity.SigningAlgEdDSA:
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		privateKey, publicKey = priv, pub
+	default:
+		return nil, nil, entity.ErrUnsupportedSigningAlg
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return privateDER, publicDER, nil
+}
+
+// signingKey returns the method and key NewToken signs with.
+func signingKey(key entity.SigningKey) (jwt.SigningMethod, any, error) {
+	switch key.Alg {
+	case entity.SigningAlgHS256:
+		return jwt.SigningMethodHS256, []byte(key.Secret), nil
+	case entity.SigningAlgRS256, entity.SigningAlgEdDSA:
+		privateKey, err := x509.ParsePKCS8PrivateKey(key.PrivateKey)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return jwt.GetSigningMethod(key.Alg), privateKey, nil
+	}
+
+	return nil, nil, entity.ErrUnsupportedSigningAlg
+}
+
+// verificationKey returns the key a token is verified with. The token's alg
+// header must match the algorithm of the signing key.
+func verificationKey(key entity.SigningKey, token *jwt.Token) (any, error) {
+	if token.Method.Alg() != key.Alg {
+		return nil, jwt.ErrTokenSignatureInvalid
+	}
+
+	if key.Alg == entity.SigningAlgHS256 {
+		return []byte(key.Secret), nil
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(key.PublicKey)
+	if err != nil {
+		return nil, errors.Join(entity.ErrUnsupportedSigningAlg, err)
+	}
+
+	return publicKey, nil
+}
+
+// newID returns a random identifier used for kid and jti values.
+func newID() (string, error) {
+	b := make([]byte, _idBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// legacyKeyID is the kid of keys created before rotation was introduced;
+// tokens without a kid header were signed with it.
+func legacyKeyID(appID int) string {
+	return "app-" + strconv.Itoa(appID)
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+)
+
+// RunSweeper periodically removes expired data until ctx is done.
+func (a *AuthUseCase) RunSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.sweep(ctx)
+		}
+	}
+}
+
+func (a *AuthUseCase) sweep(ctx context.Context) {
+	const op = "internal - usecase - Auth.sweep"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	retired, err := a.retireSigningKeys(ctx)
+	if err != nil {
+		log.Error("failed to retire signing keys", error_.Err(err))
+	} else if retired > 0 {
+		log.Info("signing keys retired", slog.Int("count", retired))
+	}
+
+	pruned, err := a.repo.DeleteExpiredRevokedTokens(ctx, time.Now())
+	if err != nil {
+		log.Error("failed to prune revoked tokens", error_.Err(err))
+	} else if pruned > 0 {
+		log.Info("expired revoked tokens pruned", slog.Int("count", pruned))
+	}
+
+	now := time.Now()
+	stale, err := a.repo.DeleteStaleLoginFailures(ctx, now, now.Add(-a.lockout.Window))
+	if err != nil {
+		log.Error("failed to prune login failures", error_.Err(err))
+	} else if stale > 0 {
+		log.Info("stale login failures pruned", slog.Int("count", stale))
+	}
+
+	resets, err := a.repo.DeleteExpiredPasswordResets(ctx, now)
+	if err != nil {
+		log.Error("failed to prune password resets", error_.Err(err))
+	} else if resets > 0 {
+		log.Info("expired password resets pruned", slog.Int("count", resets))
+	}
+
+	verifications, err := a.repo.DeleteExpiredEmailVerifications(ctx, now)
+	if err != nil {
+		log.Error("failed to prune email verifications", error_.Err(err))
+	} else if verifications > 0 {
+		log.Info("expired email verifications pruned", slog.Int("count", verifications))
+	}
+
+	challenges, err := a.repo.DeleteExpiredMFAChallenges(ctx, now)
+	if err != nil {
+		log.Error("failed to prune mfa challenges", error_.Err(err))
+	} else if challenges > 0 {
+		log.Info("expired mfa challenges pruned", slog.Int("count", challenges))
+	}
+
+	codes, err := a.repo.DeleteExpiredAuthorizationCodes(ctx, now)
+	if err != nil {
+		log.Error("failed to prune authorization codes", error_.Err(err))
+	} else if codes > 0 {
+		log.Info("expired authorization codes pruned", slog.Int("count", codes))
+	}
+}
@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func (a *AuthUseCase) ValidateToken(ctx context.Context, tokenString string) (entity.Claims, error) {
+	const op = "internal - usecase - Auth.ValidateToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("attempting to validate token")
+
+	parsed, err := jwt.Parse(tokenString, a.appKeyFunc(ctx),
+		jwt.WithValidMethods(_validSigningMethods),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrTokenUnknownApp):
+			log.Warn("token issued for unknown app", error_.Err(err))
+			return entity.Claims{}, fmt.Errorf("%s: %w", op, entity.ErrTokenUnknownApp)
+		case errors.Is(err, entity.ErrTokenUnknownKey):
+			log.Warn("token signed with unknown key", error_.Err(err))
+			return entity.Claims{}, fmt.Errorf("%s: %w", op, entity.ErrTokenUnknownKey)
+		case errors.Is(err, jwt.ErrTokenExpired):
+			log.Info("token expired", error_.Err(err))
+			return entity.Claims{}, fmt.Errorf("%s: %w", op, entity.ErrTokenExpired)
+		case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+			log.Warn("invalid token signature", error_.Err(err))
+			return entity.Claims{}, fmt.Errorf("%s: %w", op, entity.ErrTokenInvalidSignature)
+		case errors.Is(err, entity.ErrTokenMalformed),
+			errors.Is(err, jwt.ErrTokenMalformed),
+			errors.Is(err, jwt.ErrTokenInvalidClaims),
+			errors.Is(err, jwt.ErrTokenRequiredClaimMissing):
+			log.Info("malformed token", error_.Err(err))
+			return entity.Claims{}, fmt.Errorf("%s: %w", op, entity.ErrTokenMalformed)
+		}
+
+		log.Error("failed to validate token", error_.Err(err))
+
+		return entity.Claims{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	claims, err := claimsFromToken(parsed)
+	if err != nil {
+		log.Info("malformed token", error_.Err(err))
+
+		return entity.Claims{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if claims.ID != "" {
+		revoked, err := a.repo.IsTokenRevoked(ctx, claims.ID)
+		if err != nil {
+			log.Error("failed to check token revocation", error_.Err(err))
+
+			return entity.Claims{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if revoked {
+			log.Info("token revoked", slog.String("jti", claims.ID))
+
+			return entity.Claims{}, fmt.Errorf("%s: %w", op, entity.ErrTokenRevoked)
+		}
+	}
+
+	log.Info("token successfully validated")
+
+	return claims, nil
+}
+
+// newToken signs an access token for user with the current key of app,
+// embedding the user's roles and permissions within the app and the custom
+// claims of the app.
+func (a *AuthUseCase) newToken(ctx context.Context, user entity.User, app entity.App) (string, error) {
+	key, err := a.activeSigningKey(ctx, app)
+	if err != nil {
+		return "", err
+	}
+
+	access, err := a.repo.GetUserAccess(ctx, user.ID, app.ID)
+	if err != nil {
+		return "", err
+	}
+
+	custom, err := a.customClaims(ctx, user, app)
+	if err != nil {
+		return "", err
+	}
+
+	return NewToken(user, app, key, access, custom)
+}
+
+// appKeyFunc resolves the verification key by the kid header, making sure it
+// belongs to the app referenced by the app_id claim and is not retired.
+func (a *AuthUseCase) appKeyFunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			return nil, entity.ErrTokenMalformed
+		}
+
+		appID, ok := claims["app_id"].(float64)
+		if !ok {
+			return nil, entity.ErrTokenMalformed
+		}
+
+		app, err := a.repo.GetAppForUser(ctx, int(appID))
+		if err != nil {
+			if errors.Is(err, entity.ErrAppNotFound) {
+				return nil, entity.ErrTokenUnknownApp
+			}
+
+			return nil, err
+		}
+
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			kid = legacyKeyID(app.ID)
+		}
+
+		key, err := a.repo.GetSigningKey(ctx, kid)
+		if err != nil {
+			if errors.Is(err, entity.ErrSigningKeyNotFound) {
+				return nil, entity.ErrTokenUnknownKey
+			}
+
+			return nil, err
+		}
+
+		if key.AppID != app.ID || (!key.RetiresAt.IsZero() && !time.Now().Before(key.RetiresAt)) {
+			return nil, entity.ErrTokenUnknownKey
+		}
+
+		return verificationKey(key, token)
+	}
+}
+
+func claimsFromToken(token *jwt.Token) (entity.Claims, error) {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return entity.Claims{}, entity.ErrTokenMalformed
+	}
+
+	appID, ok := claims["app_id"].(float64)
+	if !ok {
+		return entity.Claims{}, entity.ErrTokenMalformed
+	}
+
+	// Client credentials tokens belong to the app and carry no user.
+	var uid float64
+	var email string
+	subject, _ := claims["sub"].(string)
+	if strings.HasPrefix(subject, entity.AppSubjectPrefix) {
+		if subject != appSubject(int(appID)) {
+			return entity.Claims{}, entity.ErrTokenMalformed
+		}
+	} else {
+		subject = ""
+
+		if uid, ok = claims["uid"].(float64); !ok {
+			return entity.Claims{}, entity.ErrTokenMalformed
+		}
+
+		if email, ok = claims["email"].(string); !ok {
+			return entity.Claims{}, entity.ErrTokenMalformed
+		}
+	}
+
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return entity.Claims{}, entity.ErrTokenMalformed
+	}
+
+	// Tokens issued before jti, roles and scope were introduced have none.
+	jti, _ := claims["jti"].(string)
+
+	var access entity.Access
+	if roles, ok := claims["roles"].([]interface{}); ok {
+		for _, role := range roles {
+			if name, ok := role.(string); ok {
+				access.Roles = append(access.Roles, name)
+			}
+		}
+	}
+	if scope, ok := claims["scope"].(string); ok {
+		access.Permissions = strings.Fields(scope)
+	}
+
+	var custom map[string]string
+	for name, value := range claims {
+		if value, ok := value.(string); ok && !reservedClaim(name) {
+			if custom == nil {
+				custom = make(map[string]string)
+			}
+			custom[name] = value
+		}
+	}
+
+	return entity.Claims{
+		ID:        jti,
+		Subject:   subject,
+		UserID:    int(uid),
+		Email:     email,
+		AppID:     int(appID),
+		ExpiresAt: exp.Time,
+		Access:    access,
+		Custom:    custom,
+	}, nil
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP follows RFC 6238 with the parameters every authenticator app supports:
+// HMAC-SHA1, 6 digits and a 30 second step.
+const (
+	_totpPeriod      = 30
+	_totpDigits      = 6
+	_totpSkew        = 1
+	_totpSecretBytes = 20
+
+	_recoveryCodeCount = 10
+	_recoveryCodeBytes = 10
+)
+
+var _base32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func newTOTPSecret() ([]byte, error) {
+	secret := make([]byte, _totpSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+func totpStep(t time.Time) int64 {
+	return t.Unix() / _totpPeriod
+}
+
+// totpCode is the HOTP value (RFC 4226) of secret at step.
+func totpCode(secret []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", _totpDigits, value%1_000_000)
+}
+
+// matchTOTP returns the step code was generated for, accepting codes of up to
+// _totpSkew steps before or after now to tolerate clock drift.
+func matchTOTP(secret []byte, code string, now time.Time) (int64, bool) {
+	if len(code) != _totpDigits {
+		return 0, false
+	}
+
+	current := totpStep(now)
+	for step := current - _totpSkew; step <= current+_totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(secret, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// totpURI is the otpauth URI authenticator apps import, usually from a QR code.
+func totpURI(issuer string, email string, secret []byte) string {
+	query := url.Values{}
+	query.Set("secret", _base32.EncodeToString(secret))
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(_totpDigits))
+	query.Set("period", fmt.Sprint(_totpPeriod))
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + email,
+		RawQuery: query.Encode(),
+	}
+
+	return u.String()
+}
+
+// newRecoveryCodes returns codes to hand to the user once and their hashes to
+// store. Codes look like abcd-efgh-ijkl-mnop.
+func newRecoveryCodes() ([]string, [][]byte, error) {
+	codes := make([]string, 0, _recoveryCodeCount)
+	hashes := make([][]byte, 0, _recoveryCodeCount)
+
+	for i := 0; i < _recoveryCodeCount; i++ {
+		b := make([]byte, _recoveryCodeBytes)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		raw := strings.ToLower(_base32.EncodeToString(b))
+
+		var code strings.Builder
+		for j := 0; j < len(raw); j += 4 {
+			if j > 0 {
+				code.WriteByte('-')
+			}
+			code.WriteString(raw[j:min(j+4, len(raw))])
+		}
+
+		codes = append(codes, code.String())
+		hashes = append(hashes, hashRecoveryCode(code.String()))
+	}
+
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode ignores case, dashes and spaces, so codes can be typed as
+// the user wrote them down.
+func hashRecoveryCode(code string) []byte {
+	normalized := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+
+		return r
+	}, strings.ToLower(code))
+
+	return hashToken(normalized)
+}
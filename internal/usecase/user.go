@@ -8,7 +8,6 @@ import (
 
 	"github.com/1kovalevskiy/sso/internal/entity"
 	error_ "github.com/1kovalevskiy/sso/internal/error"
-	"golang.org/x/crypto/bcrypt"
 )
 
 func (a *AuthUseCase) Login(ctx context.Context, email string, password string, appID int) (string, error) {
@@ -19,42 +18,206 @@ func (a *AuthUseCase) Login(ctx context.Context, email string, password string,
 		slog.String("username", email),
 	)
 
+	user, app, err := a.authenticate(ctx, log, email, password, appID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.requireMFA(ctx, log, user, app, false); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.loginSucceeded(ctx, log, user, app)
+
+	token, err := a.newToken(ctx, user, app)
+	if err != nil {
+		log.Error("failed to generate token", error_.Err(err))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, nil
+}
+
+func (a *AuthUseCase) LoginWithRefresh(ctx context.Context, email string, password string, appID int) (entity.TokenPair, error) {
+	const op = "internal - usecase - Auth.LoginWithRefresh"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("username", email),
+	)
+
+	user, app, err := a.authenticate(ctx, log, email, password, appID)
+	if err != nil {
+		return entity.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.requireMFA(ctx, log, user, app, true); err != nil {
+		return entity.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.loginSucceeded(ctx, log, user, app)
+
+	familyID, err := newOpaqueToken()
+	if err != nil {
+		log.Error("failed to generate token family", error_.Err(err))
+
+		return entity.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	pair, err := a.issueTokenPair(ctx, user, app, familyID, nil)
+	if err != nil {
+		log.Error("failed to issue tokens", error_.Err(err))
+
+		return entity.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return pair, nil
+}
+
+// authenticate checks user credentials and resolves the app the user logs into.
+// The caller completes the login with loginSucceeded once every factor is
+// verified.
+func (a *AuthUseCase) authenticate(ctx context.Context, log *slog.Logger, email string, password string, appID int) (entity.User, entity.App, error) {
 	log.Info("attempting to login user")
 
+	email, err := normalizeEmail(email)
+	if err != nil {
+		log.Info("invalid email", error_.Err(err))
+
+		return entity.User{}, entity.App{}, err
+	}
+
+	failure := entity.AuthEvent{Type: entity.EventLoginFailure, AppID: appID, Email: email}
+
+	if err := a.checkLoginAllowed(ctx, email); err != nil {
+		if errors.Is(err, entity.ErrLoginLocked) || errors.Is(err, entity.ErrLoginThrottled) {
+			log.Warn("login rejected", error_.Err(err))
+
+			failure.Details = err.Error()
+			a.recordEvent(ctx, failure)
+
+			return entity.User{}, entity.App{}, err
+		}
+
+		log.Error("failed to check login failures", error_.Err(err))
+
+		return entity.User{}, entity.App{}, err
+	}
+
 	user, err := a.repo.GetUser(ctx, email)
 	if err != nil {
 		if errors.Is(err, entity.ErrUserNotFound) {
 			log.Warn("user not found", error_.Err(err))
 
-			return "", fmt.Errorf("%s: %w", op, error_.ErrInvalidCredentials)
+			failure.Details = "user not found"
+			a.recordEvent(ctx, failure)
+			a.registerLoginFailure(ctx, log, email)
+
+			return entity.User{}, entity.App{}, error_.ErrInvalidCredentials
 		}
 
 		log.Error("failed to get user", error_.Err(err))
 
-		return "", fmt.Errorf("%s: %w", op, err)
+		return entity.User{}, entity.App{}, err
 	}
 
-	if err := bcrypt.CompareHashAndPassword(user.PassHash, []byte(password)); err != nil {
-		log.Info("invalid credentials", error_.Err(err))
+	failure.UserID = user.ID
+
+	if !a.checkPassword(log, user.PassHash, password) {
+		log.Info("invalid credentials")
 
-		return "", fmt.Errorf("%s: %w", op, error_.ErrInvalidCredentials)
+		failure.Details = "invalid password"
+		a.recordEvent(ctx, failure)
+		a.registerLoginFailure(ctx, log, email)
+
+		return entity.User{}, entity.App{}, error_.ErrInvalidCredentials
 	}
 
 	app, err := a.repo.GetAppForUser(ctx, appID)
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", op, err)
+		if errors.Is(err, entity.ErrAppNotFound) {
+			failure.AppID = 0
+			failure.Details = fmt.Sprintf("app %d not found", appID)
+			a.recordEvent(ctx, failure)
+		}
+
+		return entity.User{}, entity.App{}, err
+	}
+
+	if app.Disabled {
+		log.Warn("login to disabled app", slog.Int("app_id", app.ID))
+
+		failure.Details = fmt.Sprintf("app %d disabled", appID)
+		a.recordEvent(ctx, failure)
+
+		return entity.User{}, entity.App{}, entity.ErrAppDisabled
 	}
 
+	if app.RequireVerifiedEmail && !user.EmailVerified {
+		log.Info("login with unverified email", slog.Int("app_id", app.ID))
+
+		failure.Details = "email not verified"
+		a.recordEvent(ctx, failure)
+
+		return entity.User{}, entity.App{}, entity.ErrEmailNotVerified
+	}
+
+	a.rehashPassword(ctx, log, user, password)
+
+	return user, app, nil
+}
+
+// loginSucceeded clears the failed logins of user once all factors are
+// verified.
+func (a *AuthUseCase) loginSucceeded(ctx context.Context, log *slog.Logger, user entity.User, app entity.App) {
+	a.resetLoginFailures(ctx, log, user.Email)
+	a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventLoginSuccess, AppID: app.ID, UserID: user.ID, Email: user.Email})
+
 	log.Info("user logged in successfully")
+}
 
-	token, err := NewToken(user, app)
+// checkPassword reports whether password matches hash. Hashes that can't be
+// verified at all are logged and treated as a mismatch.
+func (a *AuthUseCase) checkPassword(log *slog.Logger, hash []byte, password string) bool {
+	ok, err := a.hasher.Verify(hash, password)
 	if err != nil {
-		log.Error("failed to generate token", error_.Err(err))
+		log.Error("failed to verify password", error_.Err(err))
 
-		return "", fmt.Errorf("%s: %w", op, err)
+		return false
 	}
 
-	return token, nil
+	return ok
+}
+
+// rehashPassword upgrades the stored hash of user when it was made with an
+// outdated algorithm or parameters. It must only be called with the password
+// the hash was verified against. Failures are logged, the login goes on.
+func (a *AuthUseCase) rehashPassword(ctx context.Context, log *slog.Logger, user entity.User, password string) {
+	if !a.hasher.NeedsRehash(user.PassHash) {
+		return
+	}
+
+	passHash, err := a.hasher.Hash(password)
+	if err != nil {
+		log.Error("failed to generate password hash", error_.Err(err))
+
+		return
+	}
+
+	count, err := a.repo.RehashUserPassword(ctx, user.ID, user.PassHash, passHash)
+	if err != nil {
+		log.Error("failed to rehash password", error_.Err(err))
+
+		return
+	}
+	if count == 0 {
+		log.Info("password changed before it was rehashed")
+
+		return
+	}
+
+	log.Info("password rehashed")
 }
 
 func (a *AuthUseCase) RegisterNewUser(ctx context.Context, email string, pass string) (int, error) {
@@ -67,7 +230,20 @@ func (a *AuthUseCase) RegisterNewUser(ctx context.Context, email string, pass st
 
 	log.Info("registering user")
 
-	passHash, err := bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
+	email, err := normalizeEmail(email)
+	if err != nil {
+		log.Info("invalid email", error_.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.passwords.check(pass); err != nil {
+		log.Info("password rejected by policy", error_.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	passHash, err := a.hasher.Hash(pass)
 	if err != nil {
 		log.Error("failed to generate password hash", error_.Err(err))
 
@@ -81,5 +257,152 @@ func (a *AuthUseCase) RegisterNewUser(ctx context.Context, email string, pass st
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
+	a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventRegister, UserID: id, Email: email})
+
+	// The user exists at this point, a failed delivery is fixed by
+	// RequestEmailVerification rather than by registering again.
+	if err := a.sendEmailVerification(ctx, entity.User{ID: id, Email: email}); err != nil {
+		log.Error("failed to send email verification", error_.Err(err))
+	}
+
 	return id, nil
 }
+
+// GetUser returns the user with userID to the bearer of accessToken, who has
+// to be that user or an admin.
+func (a *AuthUseCase) GetUser(ctx context.Context, accessToken string, userID int) (entity.User, error) {
+	const op = "internal - usecase - Auth.GetUser"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("user_id", userID),
+	)
+
+	log.Info("getting user")
+
+	if err := a.authorizeUser(ctx, accessToken, userID); err != nil {
+		log.Info("access denied", error_.Err(err))
+
+		return entity.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := a.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, entity.ErrUserNotFound) {
+			log.Warn("user not found", error_.Err(err))
+
+			return entity.User{}, fmt.Errorf("%s: %w", op, entity.ErrUserNotFound)
+		}
+
+		log.Error("failed to get user", error_.Err(err))
+
+		return entity.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// IsAdmin reports whether the user with userID is an admin to the bearer of
+// accessToken, who has to be that user or an admin.
+func (a *AuthUseCase) IsAdmin(ctx context.Context, accessToken string, userID int) (bool, error) {
+	const op = "internal - usecase - Auth.IsAdmin"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("user_id", userID),
+	)
+
+	log.Info("checking if user is admin")
+
+	if err := a.authorizeUser(ctx, accessToken, userID); err != nil {
+		log.Info("access denied", error_.Err(err))
+
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	isAdmin, err := a.repo.IsAdmin(ctx, userID)
+	if err != nil {
+		if errors.Is(err, entity.ErrUserNotFound) {
+			log.Warn("user not found", error_.Err(err))
+
+			return false, fmt.Errorf("%s: %w", op, entity.ErrUserNotFound)
+		}
+
+		log.Error("failed to check if user is admin", error_.Err(err))
+
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("checked if user is admin", slog.Bool("is_admin", isAdmin))
+
+	return isAdmin, nil
+}
+
+// SetUserAdmin grants or revokes the admin rights of the user. It is only
+// exposed on the admin server; nothing grants admin rights on registration.
+func (a *AuthUseCase) SetUserAdmin(ctx context.Context, userID int, isAdmin bool) error {
+	const op = "internal - usecase - Auth.SetUserAdmin"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("user_id", userID),
+		slog.Bool("is_admin", isAdmin),
+	)
+
+	log.Info("changing user admin rights")
+
+	count, err := a.repo.SetUserAdmin(ctx, userID, isAdmin)
+	if err != nil {
+		log.Error("failed to save user admin rights", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count == 0 {
+		return fmt.Errorf("%s: %w", op, entity.ErrUserNotFound)
+	}
+
+	details := "revoked"
+	if isAdmin {
+		details = "granted"
+	}
+	a.recordEvent(ctx, entity.AuthEvent{Type: entity.EventAdminChanged, UserID: userID, Details: details})
+
+	return nil
+}
+
+// authorizeUser checks that token is a valid access token of the user with
+// userID or of an admin user.
+func (a *AuthUseCase) authorizeUser(ctx context.Context, token string, userID int) error {
+	claims, err := a.ValidateToken(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	if claims.Subject == "" && claims.UserID == userID {
+		return nil
+	}
+
+	return a.requireAdmin(ctx, claims)
+}
+
+func (a *AuthUseCase) requireAdmin(ctx context.Context, claims entity.Claims) error {
+	// Apps are never admins, whatever user shares their id.
+	if claims.Subject != "" {
+		return error_.ErrPermissionDenied
+	}
+
+	isAdmin, err := a.repo.IsAdmin(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, entity.ErrUserNotFound) {
+			return error_.ErrPermissionDenied
+		}
+
+		return err
+	}
+
+	if !isAdmin {
+		return error_.ErrPermissionDenied
+	}
+
+	return nil
+}
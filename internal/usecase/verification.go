@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/1kovalevskiy/sso/internal/entity"
+	error_ "github.com/1kovalevskiy/sso/internal/error"
+)
+
+// RequestEmailVerification sends a new verification token to the user with
+// email. Unknown and already verified emails are not reported, so the call
+// can't be used to probe for registered users.
+func (a *AuthUseCase) RequestEmailVerification(ctx context.Context, email string) error {
+	const op = "internal - usecase - Auth.RequestEmailVerification"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("username", email),
+	)
+
+	log.Info("requesting email verification")
+
+	email, err := normalizeEmail(email)
+	if err != nil {
+		log.Info("invalid email", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := a.repo.GetUser(ctx, email)
+	if err != nil {
+		if errors.Is(err, entity.ErrUserNotFound) {
+			log.Info("email verification for unknown user")
+
+			return nil
+		}
+
+		log.Error("failed to get user", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.EmailVerified {
+		log.Info("email already verified")
+
+		return nil
+	}
+
+	if err := a.sendEmailVerification(ctx, user); err != nil {
+		log.Error("failed to send email verification", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// VerifyEmail marks the email of the owner of a token issued on registration
+// or by RequestEmailVerification as verified.
+func (a *AuthUseCase) VerifyEmail(ctx context.Context, token string) error {
+	const op = "internal - usecase - Auth.VerifyEmail"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("verifying email")
+
+	userID, err := a.repo.VerifyEmail(ctx, hashToken(token), time.Now())
+	if err != nil {
+		if errors.Is(err, entity.ErrEmailVerificationNotFound) {
+			log.Warn("invalid email verification token")
+
+			return fmt.Errorf("%s: %w", op, entity.ErrEmailVerificationNotFound)
+		}
+
+		log.Error("failed to verify email", error_.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	event := entity.AuthEvent{Type: entity.EventEmailVerified, UserID: userID}
+	if user, err := a.repo.GetUserByID(ctx, userID); err == nil {
+		event.Email = user.Email
+	}
+	a.recordEvent(ctx, event)
+
+	log.Info("email verified", slog.Int("user_id", userID))
+
+	return nil
+}
+
+// sendEmailVerification stores a new verification token of user and hands it
+// to the notifier.
+func (a *AuthUseCase) sendEmailVerification(ctx context.Context, user entity.User) error {
+	token, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	verification := entity.EmailVerification{
+		TokenHash: hashToken(token),
+		UserID:    user.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(a.verifyTTL),
+	}
+
+	if err := a.repo.InsertEmailVerification(ctx, verification); err != nil {
+		return err
+	}
+
+	return a.notifier.Notify(ctx, entity.Notification{
+		Type:      entity.NotificationEmailVerification,
+		Email:     user.Email,
+		Token:     token,
+		ExpiresAt: verification.ExpiresAt,
+	})
+}
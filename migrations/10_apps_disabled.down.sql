@@ -0,0 +1 @@
+ALTER TABLE apps DROP COLUMN disabled;
\ No newline at end of file
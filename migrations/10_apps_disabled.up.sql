@@ -0,0 +1 @@
+ALTER TABLE apps ADD COLUMN disabled BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS password_resets
+(
+    token_hash  BLOB    NOT NULL PRIMARY KEY,
+    user_id     INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at  INTEGER NOT NULL,
+    expires_at  INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_password_resets_user ON password_resets (user_id);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS email_collisions;
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS email_collisions
+(
+    email   TEXT    NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    PRIMARY KEY (email, user_id)
+);
+
+INSERT INTO email_collisions(email, user_id)
+SELECT lower(email), id FROM users
+WHERE lower(email) IN (SELECT lower(email) FROM users GROUP BY lower(email) HAVING count(*) > 1);
+
+UPDATE users SET email = lower(email)
+WHERE email <> lower(email) AND id NOT IN (SELECT user_id FROM email_collisions);
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS user_totp
+(
+    user_id     INTEGER NOT NULL PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    secret      BLOB    NOT NULL,
+    confirmed   BOOLEAN NOT NULL DEFAULT FALSE,
+    last_step   INTEGER NOT NULL DEFAULT 0,
+    created_at  INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS recovery_codes
+(
+    code_hash   BLOB    NOT NULL PRIMARY KEY,
+    user_id     INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_user ON recovery_codes (user_id);
+
+CREATE TABLE IF NOT EXISTS mfa_challenges
+(
+    token_hash    BLOB    NOT NULL PRIMARY KEY,
+    user_id       INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    app_id        INTEGER NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    with_refresh  BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at    INTEGER NOT NULL,
+    expires_at    INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_mfa_challenges_user ON mfa_challenges (user_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS authorization_codes;
+DROP TABLE IF EXISTS app_redirect_uris;
\ No newline at end of file
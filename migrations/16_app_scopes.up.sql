@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS app_scopes
+(
+    app_id  INTEGER NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    scope   TEXT    NOT NULL,
+    PRIMARY KEY (app_id, scope)
+);
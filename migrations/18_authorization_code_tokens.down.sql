@@ -0,0 +1,4 @@
+ALTER TABLE authorization_codes DROP COLUMN family_id;
+ALTER TABLE authorization_codes DROP COLUMN token_expires_at;
+ALTER TABLE authorization_codes DROP COLUMN token_id;
+ALTER TABLE authorization_codes DROP COLUMN used;
\ No newline at end of file
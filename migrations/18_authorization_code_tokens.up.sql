@@ -0,0 +1,4 @@
+ALTER TABLE authorization_codes ADD COLUMN used BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE authorization_codes ADD COLUMN token_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE authorization_codes ADD COLUMN token_expires_at INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE authorization_codes ADD COLUMN family_id TEXT NOT NULL DEFAULT '';
\ No newline at end of file
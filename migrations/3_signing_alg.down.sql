@@ -0,0 +1,3 @@
+ALTER TABLE apps DROP COLUMN public_key;
+ALTER TABLE apps DROP COLUMN private_key;
+ALTER TABLE apps DROP COLUMN signing_alg;
\ No newline at end of file
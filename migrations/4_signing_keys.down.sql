@@ -0,0 +1,8 @@
+ALTER TABLE apps ADD COLUMN private_key BLOB;
+ALTER TABLE apps ADD COLUMN public_key BLOB;
+
+UPDATE apps
+SET private_key = (SELECT private_key FROM signing_keys WHERE signing_keys.app_id = apps.id AND retires_at IS NULL),
+    public_key  = (SELECT public_key FROM signing_keys WHERE signing_keys.app_id = apps.id AND retires_at IS NULL);
+
+DROP TABLE IF EXISTS signing_keys;
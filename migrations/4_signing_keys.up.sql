@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS signing_keys
+(
+    id          INTEGER PRIMARY KEY,
+    kid         TEXT    NOT NULL UNIQUE,
+    app_id      INTEGER NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    alg         TEXT    NOT NULL,
+    secret      TEXT,
+    private_key BLOB,
+    public_key  BLOB,
+    created_at  INTEGER NOT NULL,
+    retires_at  INTEGER
+);
+CREATE INDEX IF NOT EXISTS idx_signing_keys_app ON signing_keys (app_id);
+
+INSERT INTO signing_keys (kid, app_id, alg, secret, private_key, public_key, created_at)
+SELECT 'app-' || id,
+       id,
+       signing_alg,
+       CASE WHEN signing_alg = 'HS256' THEN secret END,
+       private_key,
+       public_key,
+       CAST(strftime('%s', 'now') AS INTEGER)
+FROM apps;
+
+ALTER TABLE apps DROP COLUMN private_key;
+ALTER TABLE apps DROP COLUMN public_key;
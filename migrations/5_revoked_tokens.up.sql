@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS revoked_tokens
+(
+    jti         TEXT    PRIMARY KEY,
+    user_id     INTEGER NOT NULL,
+    app_id      INTEGER NOT NULL,
+    expires_at  INTEGER NOT NULL,
+    revoked_at  INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
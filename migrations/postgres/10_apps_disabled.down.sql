@@ -0,0 +1 @@
+ALTER TABLE apps DROP COLUMN IF EXISTS disabled;
\ No newline at end of file
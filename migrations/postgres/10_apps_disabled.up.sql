@@ -0,0 +1 @@
+ALTER TABLE apps ADD COLUMN IF NOT EXISTS disabled BOOLEAN NOT NULL DEFAULT FALSE;
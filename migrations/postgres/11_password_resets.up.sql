@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS password_resets
+(
+    token_hash  BYTEA     NOT NULL PRIMARY KEY,
+    user_id     BIGINT    NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at  BIGINT    NOT NULL,
+    expires_at  BIGINT    NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_password_resets_user ON password_resets (user_id);
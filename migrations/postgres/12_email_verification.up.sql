@@ -0,0 +1,11 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE apps ADD COLUMN IF NOT EXISTS require_verified_email BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS email_verifications
+(
+    token_hash  BYTEA     NOT NULL PRIMARY KEY,
+    user_id     BIGINT    NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at  BIGINT    NOT NULL,
+    expires_at  BIGINT    NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_email_verifications_user ON email_verifications (user_id);
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS user_totp
+(
+    user_id     BIGINT    NOT NULL PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    secret      BYTEA     NOT NULL,
+    confirmed   BOOLEAN   NOT NULL DEFAULT FALSE,
+    last_step   BIGINT    NOT NULL DEFAULT 0,
+    created_at  BIGINT    NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS recovery_codes
+(
+    code_hash   BYTEA     NOT NULL PRIMARY KEY,
+    user_id     BIGINT    NOT NULL REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_user ON recovery_codes (user_id);
+
+CREATE TABLE IF NOT EXISTS mfa_challenges
+(
+    token_hash    BYTEA     NOT NULL PRIMARY KEY,
+    user_id       BIGINT    NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    app_id        BIGINT    NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    with_refresh  BOOLEAN   NOT NULL DEFAULT FALSE,
+    created_at    BIGINT    NOT NULL,
+    expires_at    BIGINT    NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_mfa_challenges_user ON mfa_challenges (user_id);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS app_redirect_uris
+(
+    app_id  BIGINT  NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    uri     TEXT    NOT NULL,
+    PRIMARY KEY (app_id, uri)
+);
+
+CREATE TABLE IF NOT EXISTS authorization_codes
+(
+    code_hash       BYTEA   NOT NULL PRIMARY KEY,
+    app_id          BIGINT  NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    user_id         BIGINT  NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    redirect_uri    TEXT    NOT NULL,
+    code_challenge  TEXT    NOT NULL,
+    scope           TEXT    NOT NULL,
+    nonce           TEXT    NOT NULL,
+    created_at      BIGINT  NOT NULL,
+    expires_at      BIGINT  NOT NULL
+);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS app_claims
+(
+    app_id      BIGINT  NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    name        TEXT    NOT NULL,
+    template    TEXT    NOT NULL,
+    PRIMARY KEY (app_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS user_attributes
+(
+    user_id     BIGINT  NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    key         TEXT    NOT NULL,
+    value       TEXT    NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
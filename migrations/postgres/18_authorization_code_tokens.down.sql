@@ -0,0 +1,4 @@
+ALTER TABLE authorization_codes DROP COLUMN IF EXISTS family_id;
+ALTER TABLE authorization_codes DROP COLUMN IF EXISTS token_expires_at;
+ALTER TABLE authorization_codes DROP COLUMN IF EXISTS token_id;
+ALTER TABLE authorization_codes DROP COLUMN IF EXISTS used;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE authorization_codes ADD COLUMN IF NOT EXISTS used BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE authorization_codes ADD COLUMN IF NOT EXISTS token_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE authorization_codes ADD COLUMN IF NOT EXISTS token_expires_at BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE authorization_codes ADD COLUMN IF NOT EXISTS family_id TEXT NOT NULL DEFAULT '';
\ No newline at end of file
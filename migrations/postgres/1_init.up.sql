@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS users
+(
+    id           BIGSERIAL PRIMARY KEY,
+    email        TEXT      NOT NULL UNIQUE,
+    pass_hash    BYTEA     NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS apps
+(
+    id          BIGSERIAL PRIMARY KEY,
+    name        TEXT      NOT NULL UNIQUE,
+    pass_hash   BYTEA     NOT NULL,
+    secret      TEXT      NOT NULL,
+    ttl_hours   INTEGER   NOT NULL
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS refresh_tokens
+(
+    id          BIGSERIAL PRIMARY KEY,
+    token_hash  BYTEA     NOT NULL UNIQUE,
+    family_id   TEXT      NOT NULL,
+    user_id     BIGINT    NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    app_id      BIGINT    NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    expires_at  BIGINT    NOT NULL,
+    rotated_at  BIGINT,
+    revoked_at  BIGINT
+);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family ON refresh_tokens (family_id);
@@ -0,0 +1 @@
+ALTER TABLE apps DROP COLUMN IF EXISTS signing_alg;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE apps ADD COLUMN IF NOT EXISTS signing_alg TEXT NOT NULL DEFAULT 'HS256';
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS signing_keys
+(
+    id          BIGSERIAL PRIMARY KEY,
+    kid         TEXT      NOT NULL UNIQUE,
+    app_id      BIGINT    NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    alg         TEXT      NOT NULL,
+    secret      TEXT,
+    private_key BYTEA,
+    public_key  BYTEA,
+    created_at  BIGINT    NOT NULL,
+    retires_at  BIGINT
+);
+CREATE INDEX IF NOT EXISTS idx_signing_keys_app ON signing_keys (app_id);
+
+INSERT INTO signing_keys (kid, app_id, alg, secret, created_at)
+SELECT 'app-' || id,
+       id,
+       signing_alg,
+       secret,
+       EXTRACT(EPOCH FROM now())::BIGINT
+FROM apps
+WHERE signing_alg = 'HS256';
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS revoked_tokens
+(
+    jti         TEXT      PRIMARY KEY,
+    user_id     BIGINT    NOT NULL,
+    app_id      BIGINT    NOT NULL,
+    expires_at  BIGINT    NOT NULL,
+    revoked_at  BIGINT    NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS roles
+(
+    id          BIGSERIAL PRIMARY KEY,
+    app_id      BIGINT    NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    name        TEXT      NOT NULL,
+    UNIQUE (app_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions
+(
+    role_id     BIGINT    NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    permission  TEXT      NOT NULL,
+    PRIMARY KEY (role_id, permission)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles
+(
+    user_id     BIGINT    NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role_id     BIGINT    NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, role_id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_roles_role ON user_roles (role_id);
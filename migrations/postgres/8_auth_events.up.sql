@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS auth_events
+(
+    id          BIGSERIAL PRIMARY KEY,
+    type        TEXT      NOT NULL,
+    created_at  BIGINT    NOT NULL,
+    app_id      BIGINT,
+    user_id     BIGINT,
+    email       TEXT      NOT NULL DEFAULT '',
+    peer_addr   TEXT      NOT NULL DEFAULT '',
+    user_agent  TEXT      NOT NULL DEFAULT '',
+    details     TEXT      NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_auth_events_user ON auth_events (user_id, id);
+CREATE INDEX IF NOT EXISTS idx_auth_events_app ON auth_events (app_id, id);
+CREATE INDEX IF NOT EXISTS idx_auth_events_created_at ON auth_events (created_at);
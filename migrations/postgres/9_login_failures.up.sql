@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS login_failures
+(
+    key             TEXT      PRIMARY KEY,
+    failures        INTEGER   NOT NULL,
+    last_failed_at  BIGINT    NOT NULL, -- unix milliseconds
+    locked_until    BIGINT    NOT NULL DEFAULT 0 -- unix milliseconds
+);
+CREATE INDEX IF NOT EXISTS idx_login_failures_last_failed_at ON login_failures (last_failed_at);
@@ -0,0 +1,68 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	_defaultReadTimeout     = 5 * time.Second
+	_defaultWriteTimeout    = 5 * time.Second
+	_defaultShutdownTimeout = 3 * time.Second
+)
+
+type Server struct {
+	server *http.Server
+	notify chan error
+	log    *slog.Logger
+	port   int
+}
+
+func New(log *slog.Logger, port int, handler http.Handler) *Server {
+	httpServer := &http.Server{
+		Handler:      handler,
+		ReadTimeout:  _defaultReadTimeout,
+		WriteTimeout: _defaultWriteTimeout,
+	}
+
+	return &Server{
+		server: httpServer,
+		notify: make(chan error, 1),
+		log:    log,
+		port:   port,
+	}
+}
+
+func (s *Server) Start() {
+	const op = "pkg - httpserver - Server.Start"
+
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", s.port))
+	if err != nil {
+		s.notify <- fmt.Errorf("%s: %w", op, err)
+		return
+	}
+
+	go func() {
+		s.log.Info("http server started", slog.String("addr", l.Addr().String()))
+		if err := s.server.Serve(l); !errors.Is(err, http.ErrServerClosed) {
+			s.notify <- err
+		}
+		close(s.notify)
+	}()
+}
+
+func (s *Server) Notify() <-chan error {
+	return s.notify
+}
+
+func (s *Server) Shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), _defaultShutdownTimeout)
+	defer cancel()
+
+	return s.server.Shutdown(ctx)
+}
@@ -3,16 +3,18 @@ package migrator
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/pgx/v5"
 	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
-func MigrateUp (migrationsPath, storagePath, migrationsTable string) {
+func MigrateUp (migrationsPath, databaseURL string) {
 	m, err := migrate.New(
 		"file://"+migrationsPath,
-		fmt.Sprintf("sqlite3://%s?x-migrations-table=%s", storagePath, migrationsTable),
+		databaseURL,
 	)
 	if err != nil {
 		panic(err)
@@ -31,3 +33,25 @@ func MigrateUp (migrationsPath, storagePath, migrationsTable string) {
 	fmt.Println("migrations applied")
 
 }
+
+// SQLiteURL returns the migrate database url of the sqlite file at storagePath.
+func SQLiteURL(storagePath, migrationsTable string) string {
+	return fmt.Sprintf("sqlite3://%s?x-migrations-table=%s", storagePath, migrationsTable)
+}
+
+// PostgresURL returns the migrate database url of a postgres:// connection url.
+func PostgresURL(storageURL, migrationsTable string) string {
+	url := storageURL
+	for _, scheme := range []string{"postgres://", "postgresql://"} {
+		if strings.HasPrefix(url, scheme) {
+			url = "pgx5://" + strings.TrimPrefix(url, scheme)
+		}
+	}
+
+	separator := "?"
+	if strings.Contains(url, "?") {
+		separator = "&"
+	}
+
+	return url + separator + "x-migrations-table=" + migrationsTable
+}
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+const (
+	_defaultTimeout = time.Second
+)
+
+type Postgres struct {
+	Timeout time.Duration
+	DB      *sql.DB
+}
+
+func New(url string, timeout string) (*Postgres, error) {
+	db, err := openDB(url)
+	if err != nil {
+		return nil, err
+	}
+	to, err := time.ParseDuration(timeout)
+	if err != nil {
+		to = _defaultTimeout
+	}
+
+	pg := &Postgres{
+		Timeout: to,
+		DB:      db,
+	}
+
+	return pg, nil
+}
+
+func (p *Postgres) Close() {
+	if p.DB != nil {
+		p.DB.Close()
+	}
+}
+
+func openDB(dsn string) (*sql.DB, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err = db.Ping(); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
@@ -0,0 +1,27 @@
+package sqlite
+
+import "time"
+
+// Option configures SQLite.
+type Option func(*SQLite)
+
+// JournalMode sets the journal mode of the database, e.g. WAL.
+func JournalMode(mode string) Option {
+	return func(s *SQLite) {
+		s.journalMode = mode
+	}
+}
+
+// BusyTimeout sets how long a connection waits for a lock before failing with SQLITE_BUSY.
+func BusyTimeout(timeout time.Duration) Option {
+	return func(s *SQLite) {
+		s.busyTimeout = timeout
+	}
+}
+
+// MaxOpenConns limits the number of open connections, zero means no limit.
+func MaxOpenConns(n int) Option {
+	return func(s *SQLite) {
+		s.maxOpenConns = n
+	}
+}
@@ -2,44 +2,132 @@ package sqlite
 
 import (
 	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
 const (
-	_defaultTimeout = time.Second
+	_defaultTimeout     = time.Second
+	_defaultBusyTimeout = 5 * time.Second
 )
 
 type SQLite struct {
 	Timeout time.Duration
 	DB      *sql.DB
+
+	journalMode  string
+	busyTimeout  time.Duration
+	maxOpenConns int
+
+	mu    sync.RWMutex
+	stmts map[string]*sql.Stmt
 }
 
-func New(url string, timeout string) (*SQLite, error) {
-	db, err := openDB(url)
-	if err != nil {
-		return nil, err
-	}
+func New(url string, timeout string, opts ...Option) (*SQLite, error) {
 	to, err := time.ParseDuration(timeout)
 	if err != nil {
 		to = _defaultTimeout
 	}
 
 	mysql := &SQLite{
-		Timeout: to,
-		DB:      db,
+		Timeout:     to,
+		busyTimeout: _defaultBusyTimeout,
+		stmts:       make(map[string]*sql.Stmt),
+	}
+
+	for _, opt := range opts {
+		opt(mysql)
+	}
+
+	db, err := openDB(mysql.dsn(url))
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(mysql.maxOpenConns)
+	// Zero means unlimited open connections but no idle ones, so the
+	// database/sql default is kept then.
+	if mysql.maxOpenConns > 0 {
+		db.SetMaxIdleConns(mysql.maxOpenConns)
 	}
+	mysql.DB = db
 
 	return mysql, nil
 }
 
+// Prepare prepares queries ahead of their first use, so broken SQL fails at startup.
+func (p *SQLite) Prepare(queries ...string) error {
+	for _, query := range queries {
+		if _, err := p.Stmt(query); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Stmt returns the prepared statement of query. Statements are prepared once
+// and reused until Close.
+func (p *SQLite) Stmt(query string) (*sql.Stmt, error) {
+	p.mu.RLock()
+	stmt, ok := p.stmts[query]
+	p.mu.RUnlock()
+	if ok {
+		return stmt, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if stmt, ok := p.stmts[query]; ok {
+		return stmt, nil
+	}
+
+	stmt, err := p.DB.Prepare(query)
+	if err != nil {
+		return nil, fmt.Errorf("prepare %q: %w", query, err)
+	}
+	p.stmts[query] = stmt
+
+	return stmt, nil
+}
+
 func (p *SQLite) Close() {
+	p.mu.Lock()
+	for query, stmt := range p.stmts {
+		stmt.Close()
+		delete(p.stmts, query)
+	}
+	p.mu.Unlock()
+
 	if p.DB != nil {
 		p.DB.Close()
 	}
 }
 
+// dsn adds the connection settings to url. The driver applies them to every
+// connection it opens. Foreign keys are always enforced, so ON DELETE CASCADE
+// behaves as declared in the schema.
+func (p *SQLite) dsn(url string) string {
+	params := []string{"_foreign_keys=1"}
+	if p.journalMode != "" {
+		params = append(params, "_journal_mode="+p.journalMode)
+	}
+	if p.busyTimeout > 0 {
+		params = append(params, fmt.Sprintf("_busy_timeout=%d", p.busyTimeout.Milliseconds()))
+	}
+
+	separator := "?"
+	if strings.Contains(url, "?") {
+		separator = "&"
+	}
+
+	return url + separator + strings.Join(params, "&")
+}
+
 func openDB(dsn string) (*sql.DB, error) {
 	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
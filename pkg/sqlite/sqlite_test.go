@@ -0,0 +1,99 @@
+package sqlite
+
+import (
+	"database/sql"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const _query = `SELECT 1`
+
+func newSQLite(t *testing.T) *SQLite {
+	t.Helper()
+
+	db, err := New(filepath.Join(t.TempDir(), "test.db"), "1s")
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestStmt_Cached(t *testing.T) {
+	db := newSQLite(t)
+	defer db.Close()
+
+	require.NoError(t, db.Prepare(_query, _query))
+	assert.Len(t, db.stmts, 1)
+
+	first, err := db.Stmt(_query)
+	require.NoError(t, err)
+
+	second, err := db.Stmt(_query)
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	var one int
+	require.NoError(t, second.QueryRow().Scan(&one))
+	assert.Equal(t, 1, one)
+}
+
+func TestStmt_Concurrent(t *testing.T) {
+	db := newSQLite(t)
+	defer db.Close()
+
+	var wg sync.WaitGroup
+	stmts := make(chan *sql.Stmt, 8)
+	for i := 0; i < cap(stmts); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			stmt, err := db.Stmt(_query)
+			assert.NoError(t, err)
+			stmts <- stmt
+		}()
+	}
+	wg.Wait()
+	close(stmts)
+
+	first := <-stmts
+	for stmt := range stmts {
+		assert.Same(t, first, stmt)
+	}
+}
+
+func TestPrepare_BrokenQuery(t *testing.T) {
+	db := newSQLite(t)
+	defer db.Close()
+
+	err := db.Prepare(_query, `SELEC 1`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SELEC 1")
+	assert.Len(t, db.stmts, 1)
+}
+
+func TestClose_ReleasesStatements(t *testing.T) {
+	db := newSQLite(t)
+
+	stmt, err := db.Stmt(_query)
+	require.NoError(t, err)
+
+	db.Close()
+	assert.Empty(t, db.stmts)
+
+	var one int
+	assert.Error(t, stmt.QueryRow().Scan(&one), "statement must be closed")
+}
+
+func TestNew_UnlimitedConnsKeepIdle(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "test.db"), "1s", MaxOpenConns(0))
+	require.NoError(t, err)
+	defer db.Close()
+
+	var one int
+	require.NoError(t, db.DB.QueryRow(_query).Scan(&one))
+	assert.Equal(t, 1, db.DB.Stats().Idle)
+}
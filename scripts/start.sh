@@ -1,4 +1,4 @@
 #!/bin/sh
 
-/migrator --config-path="./config/config.yml" --migrations-path=./migrations
+/migrator --config-path="./config/config.yml" --migrations-path="${MIGRATIONS_PATH:-./migrations}"
 /app --config-path="./config/config.yml"
\ No newline at end of file